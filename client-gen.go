@@ -1,26 +1,14 @@
 package main
 
 import (
-	"encoding/json"
-	"flag"
 	"fmt"
-	"github.com/softwaresale/client-gen/v2/internal/jscodegen"
-	"github.com/softwaresale/client-gen/v2/internal/types"
 	"os"
 )
 
-// ProgramArgs specifies the arguments passed to this binary
-type ProgramArgs struct {
-	InputSpec string
-	OutputDir string
-	Target    TargetLanguage
-}
-
-var args ProgramArgs
-
 const (
 	TargetAngular = "angular"
 	TargetSpring  = "spring"
+	TargetDocs    = "docs"
 )
 
 type TargetLanguage string
@@ -33,6 +21,8 @@ func (t *TargetLanguage) Set(value string) error {
 		*t = TargetAngular
 	case TargetSpring:
 		*t = TargetSpring
+	case TargetDocs:
+		*t = TargetDocs
 	default:
 		return fmt.Errorf("unknown target language: %s", value)
 	}
@@ -40,52 +30,73 @@ func (t *TargetLanguage) Set(value string) error {
 	return nil
 }
 
-func init() {
-	flag.StringVar(&args.InputSpec, "input", "", "Path to input specification")
-	flag.StringVar(&args.OutputDir, "output-dir", "", "The path to write this output to")
-	flag.Var(&args.Target, "target", "The target language. Options are ['angular' (default), 'spring']")
+// Command is a subcommand of this binary
+type Command struct {
+	Name        string                  // the name used to invoke this command
+	Description string                  // a short description of the command
+	Run         func(args []string) int // runs the command with the remaining arguments and returns the exit code
 }
 
-func main() {
-
-	// parse the input
-	flag.Parse()
-
-	if len(args.InputSpec) == 0 {
-		fmt.Println("input specification path is required")
-		return
-	}
+var commands = []Command{
+	{
+		Name:        "generate",
+		Description: "Generate API clients from a specification",
+		Run:         runGenerate,
+	},
+	{
+		Name:        "extract",
+		Description: "Derive a specification from Go or Spring source code",
+		Run:         runExtract,
+	},
+	{
+		Name:        "postman",
+		Description: "Convert between specifications and Postman collections",
+		Run:         runPostman,
+	},
+	{
+		Name:        "infer",
+		Description: "Infer a draft specification from recorded HTTP traffic",
+		Run:         runInfer,
+	},
+	{
+		Name:        "jsonschema",
+		Description: "Convert between entities and JSON Schema documents",
+		Run:         runJSONSchema,
+	},
+	{
+		Name:        "diff",
+		Description: "Report the changes between two specifications that break clients",
+		Run:         runDiff,
+	},
+}
 
-	if len(args.OutputDir) == 0 {
-		fmt.Println("output dir path is required")
-		return
-	}
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
 
-	apiDef, err := readAPIDefinition(args.InputSpec)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
+func run(args []string) int {
+	if len(args) > 0 {
+		for _, command := range commands {
+			if args[0] == command.Name {
+				return command.Run(args[1:])
+			}
+		}
+
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			printUsage()
+			return 0
+		}
 	}
 
-	compiler := jscodegen.NewNGCompiler(args.OutputDir)
-
-	err = compiler.Compile(apiDef)
-	if err != nil {
-		panic(err)
-	}
+	// without a subcommand, fall back to generating so that existing invocations keep working
+	return runGenerate(args)
 }
 
-func readAPIDefinition(path string) (types.APIDefinition, error) {
-	serviceFileContents, err := os.ReadFile(path)
-	if err != nil {
-		return types.APIDefinition{}, fmt.Errorf("failed to open API definition file: %w", err)
-	}
-
-	var apiDef types.APIDefinition
-	err = json.Unmarshal(serviceFileContents, &apiDef)
-	if err != nil {
-		return types.APIDefinition{}, fmt.Errorf("failed to parse API definition file: %w", err)
+func printUsage() {
+	fmt.Println("usage: client-gen <command> [arguments]")
+	fmt.Println()
+	fmt.Println("commands:")
+	for _, command := range commands {
+		fmt.Printf("  %-10s %s\n", command.Name, command.Description)
 	}
-
-	return apiDef, nil
 }
@@ -0,0 +1,279 @@
+// Package clientgen exposes the client generator as a library so that it can be embedded into other Go tooling, such
+// as go:generate helpers or build servers.
+package clientgen
+
+import (
+	"context"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/docgen"
+	"github.com/softwaresale/client-gen/v2/internal/goextract"
+	"github.com/softwaresale/client-gen/v2/internal/harinfer"
+	"github.com/softwaresale/client-gen/v2/internal/jscodegen"
+	"github.com/softwaresale/client-gen/v2/internal/jsonschema"
+	"github.com/softwaresale/client-gen/v2/internal/postman"
+	"github.com/softwaresale/client-gen/v2/internal/specdiff"
+	"github.com/softwaresale/client-gen/v2/internal/specfile"
+	"github.com/softwaresale/client-gen/v2/internal/springextract"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+)
+
+// The API specification model. These are aliases, so values can be freely passed between this package and the
+// compiler internals.
+type (
+	APIDefinition       = types.APIDefinition
+	APIConfig           = types.APIConfig
+	ServiceDefinition   = types.ServiceDefinition
+	APIEndpoint         = types.APIEndpoint
+	RequestValue        = types.RequestValue
+	EntitySpec          = types.EntitySpec
+	PropertySpec        = types.PropertySpec
+	DynamicType         = types.DynamicType
+	NamingStrategy      = types.NamingStrategy
+	ExternalType        = types.ExternalType
+	ExternalTypeMapping = types.ExternalTypeMapping
+)
+
+const (
+	TypeID_VOID      = types.TypeID_VOID
+	TypeID_STRING    = types.TypeID_STRING
+	TypeID_INTEGER   = types.TypeID_INTEGER
+	TypeID_FLOAT     = types.TypeID_FLOAT
+	TypeID_BOOLEAN   = types.TypeID_BOOLEAN
+	TypeID_USER      = types.TypeID_USER
+	TypeID_ARRAY     = types.TypeID_ARRAY
+	TypeID_GENERIC   = types.TypeID_GENERIC
+	TypeID_TIMESTAMP = types.TypeID_TIMESTAMP
+	TypeID_DATE      = types.TypeID_DATE
+	TypeID_ANY       = types.TypeID_ANY
+)
+
+const (
+	NamingStrategy_PRESERVE = types.NamingStrategy_PRESERVE
+	NamingStrategy_CAMEL    = types.NamingStrategy_CAMEL
+	NamingStrategy_SNAKE    = types.NamingStrategy_SNAKE
+	NamingStrategy_KEBAB    = types.NamingStrategy_KEBAB
+)
+
+// Interfaces for providing custom output destinations
+type (
+	OutputsManager = outputs.CompilerOutputsManager
+	OutputWriter   = outputs.CompilerOutputWriter
+	OutputLocation = outputs.CompilerOutputLocation
+)
+
+// MemoryOutputs collects compiler outputs in memory. Use Files to get the generated contents keyed by path
+type MemoryOutputs = outputs.MemoryCompilerOutputsManager
+
+// NewMemoryOutputs creates an empty in-memory outputs manager
+func NewMemoryOutputs() *MemoryOutputs {
+	return outputs.NewMemoryCompilerOutputsManager()
+}
+
+// DirectoryOutputs writes compiler outputs into a directory. Files are only rewritten when their contents change.
+// Use Stats to find out what changed during the most recent compilation.
+type DirectoryOutputs = outputs.DirectoryCompilerOutputsManager
+
+// Layout decides where each kind of output is written, using patterns such as "models/{{kebab .Name}}.model.ts".
+// Generated code imports other outputs by their relative paths, so any layout can be used
+type Layout = outputs.Layout
+
+// OutputStats counts what happened to each file written by DirectoryOutputs
+type OutputStats = outputs.OutputStats
+
+// NewDirectoryOutputs creates an outputs manager that writes into the given directory
+func NewDirectoryOutputs(dir string) *DirectoryOutputs {
+	return &outputs.DirectoryCompilerOutputsManager{
+		BasePath: dir,
+	}
+}
+
+// Target is a language that clients can be generated for
+type Target string
+
+const (
+	TargetAngular Target = "angular"
+	TargetSpring  Target = "spring"
+	TargetDocs    Target = "docs"
+)
+
+// Options configures a compilation
+type Options struct {
+	OutputDir    string         // directory to write outputs to. Required unless Outputs is provided
+	Outputs      OutputsManager // optional destination for outputs. If nil, outputs are written into OutputDir
+	Package      bool           // also emit a publishable package scaffold, such as package.json
+	PackageScope string         // optional scope of the generated package, such as "@acme". The leading "@" is added if it is missing
+	HTML         bool           // for the docs target, generate a static HTML site instead of markdown
+	Validators   bool           // emit runtime validators that check entities against their constraints
+	Zod          bool           // emit zod schemas for entities and parse responses with them
+	Naming       NamingStrategy // overrides how the API writes property names on the wire, if set
+	OnWarning    func(string)   // optionally receives warnings, such as names that had to be renamed
+	Layout       Layout         // where outputs are written within OutputDir. Ignored if Outputs is provided
+}
+
+// LoadAPIDefinition reads the API definition rooted at the given file. Files it includes and values it references with
+// "$ref" are loaded relative to the file that mentions them, and errors point at the file and line that caused them
+func LoadAPIDefinition(path string) (APIDefinition, error) {
+	return specfile.NewResolver().Resolve(path)
+}
+
+// ExtractOptions configures how an API definition is derived from Go source code
+type ExtractOptions = goextract.Options
+
+// ExtractAPIDefinition derives an API definition from the Go packages matching the given patterns, such as "./...".
+// Packages are type checked without being run. Structs marked with "//clientgen:entity" become entities and functions
+// marked with "//clientgen:endpoint METHOD /path" become endpoints
+func ExtractAPIDefinition(ctx context.Context, patterns []string, opts ExtractOptions) (APIDefinition, error) {
+	return goextract.Extract(ctx, patterns, opts)
+}
+
+// SpringExtractOptions configures how an API definition is derived from the sources of Spring controllers
+type SpringExtractOptions = springextract.Options
+
+// ExtractSpringAPIDefinition derives an API definition from the Java sources under the given files and directories.
+// Sources are parsed without a JVM. @RestController classes become services, their mapped handler methods become
+// endpoints, and the classes, records, and enums they exchange become entities
+func ExtractSpringAPIDefinition(roots []string, opts SpringExtractOptions) (APIDefinition, error) {
+	return springextract.Extract(roots, opts)
+}
+
+// PostmanCollection is a Postman collection, in version 2.1 of the collection format
+type PostmanCollection = postman.Collection
+
+// PostmanImportOptions configures how a Postman collection is converted into an API definition
+type PostmanImportOptions = postman.ImportOptions
+
+// ReadPostmanCollection decodes a Postman collection
+func ReadPostmanCollection(data []byte) (PostmanCollection, error) {
+	return postman.Read(data)
+}
+
+// ImportPostmanCollection converts a Postman collection into an API definition. Folders become services, requests
+// become endpoints, and entities are inferred from the JSON bodies of requests and their saved example responses
+func ImportPostmanCollection(collection PostmanCollection, opts PostmanImportOptions) (APIDefinition, error) {
+	return postman.Import(collection, opts)
+}
+
+// ExportPostmanCollection converts an API definition into a Postman collection. Requests are relative to a baseURL
+// collection variable that is set to the base URL of the API, and have sample bodies built from its entities
+func ExportPostmanCollection(api APIDefinition) (PostmanCollection, error) {
+	return postman.Export(api)
+}
+
+// HAR is a HAR file of recorded HTTP traffic
+type HAR = harinfer.Archive
+
+// InferOptions configures how a draft API definition is inferred from recorded traffic
+type InferOptions = harinfer.Options
+
+// ReadHAR decodes a HAR file
+func ReadHAR(data []byte) (HAR, error) {
+	return harinfer.Read(data)
+}
+
+// InferAPIDefinition infers a draft API definition from recorded traffic. Requests that send or receive JSON are
+// clustered into endpoints by their method and path, in which numeric and UUID segments become path variables. Query
+// variables and entities are inferred from every request of an endpoint. The result is meant to be reviewed by hand
+func InferAPIDefinition(archive HAR, opts InferOptions) (APIDefinition, error) {
+	return harinfer.Infer(archive, opts)
+}
+
+// JSONSchema is a JSON Schema document, in the 2020-12 version of JSON Schema
+type JSONSchema = jsonschema.Schema
+
+// JSONSchemaExportOptions configures how entities are exported as JSON Schema
+type JSONSchemaExportOptions = jsonschema.ExportOptions
+
+// ReadJSONSchema decodes a JSON Schema document
+func ReadJSONSchema(data []byte) (*JSONSchema, error) {
+	return jsonschema.Read(data)
+}
+
+// ExportJSONSchema builds a JSON Schema document that declares every entity of an API definition under $defs
+func ExportJSONSchema(api APIDefinition, opts JSONSchemaExportOptions) (*JSONSchema, error) {
+	return jsonschema.Export(api, opts)
+}
+
+// ImportJSONSchema converts the object schemas that a JSON Schema document declares into entities
+func ImportJSONSchema(document *JSONSchema) ([]EntitySpec, error) {
+	return jsonschema.Import(document)
+}
+
+// DiffReport lists the changes between two API definitions
+type DiffReport = specdiff.Report
+
+// Change is a single difference between two API definitions
+type Change = specdiff.Change
+
+// ChangeSeverity classifies how a change affects existing clients
+type ChangeSeverity = specdiff.Severity
+
+const (
+	Severity_BREAKING     = specdiff.Severity_BREAKING
+	Severity_NON_BREAKING = specdiff.Severity_NON_BREAKING
+)
+
+// DiffAPIDefinitions compares two versions of an API definition, classifying every change by whether it breaks clients
+// that were generated from the old version
+func DiffAPIDefinitions(oldAPI, newAPI APIDefinition) DiffReport {
+	return specdiff.Diff(oldAPI, newAPI)
+}
+
+// Compile generates a client for the given API definition in the target language
+func Compile(ctx context.Context, api APIDefinition, target Target, opts Options) error {
+	compiler, err := newCompiler(target, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.Naming) > 0 {
+		api.Naming = opts.Naming
+	}
+	compiler.OnWarning = opts.OnWarning
+
+	return compiler.Compile(ctx, api)
+}
+
+// docsFormat gets the format the docs target generates with the given options
+func docsFormat(opts Options) docgen.DocsFormat {
+	if opts.HTML {
+		return docgen.FormatHTML
+	}
+
+	return docgen.FormatMarkdown
+}
+
+func newCompiler(target Target, opts Options) (codegen.APICompiler, error) {
+	outputsManager := opts.Outputs
+	if outputsManager == nil {
+		if len(opts.OutputDir) == 0 {
+			return codegen.APICompiler{}, fmt.Errorf("either an output directory or an outputs manager is required")
+		}
+
+		err := opts.Layout.Validate()
+		if err != nil {
+			return codegen.APICompiler{}, err
+		}
+
+		directoryOutputs := NewDirectoryOutputs(opts.OutputDir)
+		directoryOutputs.Layout = opts.Layout
+		outputsManager = directoryOutputs
+	}
+
+	switch target {
+	case TargetAngular:
+		ngOptions := jscodegen.NGOptions{
+			Package:      opts.Package,
+			PackageScope: opts.PackageScope,
+			Validators:   opts.Validators,
+			Zod:          opts.Zod,
+		}
+
+		return jscodegen.NewNGCompilerWithOutputs(outputsManager, opts.OutputDir, ngOptions), nil
+	case TargetDocs:
+		return docgen.NewDocsCompiler(outputsManager, opts.OutputDir, docsFormat(opts)), nil
+	default:
+		return codegen.APICompiler{}, fmt.Errorf("target '%s' is not supported", target)
+	}
+}
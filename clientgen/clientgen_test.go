@@ -0,0 +1,145 @@
+package clientgen
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func exampleAPI() APIDefinition {
+	return APIDefinition{
+		Name: "people",
+		Entities: []EntitySpec{
+			{
+				Name: "Person",
+				Properties: map[string]PropertySpec{
+					"name": {Type: DynamicType{TypeID: TypeID_STRING}, Required: true},
+				},
+			},
+		},
+		Services: []ServiceDefinition{
+			{
+				Name: "Person",
+				Endpoints: []APIEndpoint{
+					{
+						Name:         "getAll",
+						Endpoint:     "/people",
+						Method:       "GET",
+						RequestBody:  RequestValue{Type: DynamicType{TypeID: TypeID_VOID}},
+						ResponseBody: RequestValue{Type: DynamicType{TypeID: TypeID_ARRAY, Inner: []DynamicType{{TypeID: TypeID_USER, Reference: "Person"}}}},
+					},
+				},
+			},
+		},
+		Config: APIConfig{
+			BaseURL: "http://localhost:8080",
+		},
+	}
+}
+
+func TestCompile_WritesToMemoryOutputs(t *testing.T) {
+	memoryOutputs := NewMemoryOutputs()
+
+	err := Compile(context.Background(), exampleAPI(), TargetAngular, Options{Outputs: memoryOutputs})
+	assert.NoError(t, err)
+
+	files := memoryOutputs.Files()
+	assert.Contains(t, files, "person.model.gen.ts")
+	assert.Contains(t, files, "person.service.gen.ts")
+	assert.Contains(t, files, "api-config.config.gen.ts")
+	assert.Contains(t, files, "index.ts")
+	assert.Contains(t, string(files["person.service.gen.ts"]), "export class PersonService")
+}
+
+func TestCompile_DocsUseFormatExtensionWithMemoryOutputs(t *testing.T) {
+	memoryOutputs := NewMemoryOutputs()
+
+	err := Compile(context.Background(), exampleAPI(), TargetDocs, Options{Outputs: memoryOutputs})
+	assert.NoError(t, err)
+
+	files := memoryOutputs.Files()
+	assert.Contains(t, files, "person.model.gen.md")
+	assert.Contains(t, files, "person.service.gen.md")
+	assert.NotContains(t, files, "person.model.gen.ts")
+	assert.Contains(t, string(files["README.md"]), "person.model.gen.md")
+}
+
+func TestCompile_RequiresDestination(t *testing.T) {
+	err := Compile(context.Background(), exampleAPI(), TargetAngular, Options{})
+	assert.Error(t, err)
+}
+
+func TestCompile_RejectsUnsupportedTarget(t *testing.T) {
+	err := Compile(context.Background(), exampleAPI(), TargetSpring, Options{Outputs: NewMemoryOutputs()})
+	assert.Error(t, err)
+}
+
+func TestCompile_ImportsRelativeToLayout(t *testing.T) {
+	memoryOutputs := NewMemoryOutputs()
+	memoryOutputs.Layout = Layout{
+		Model:   "models/{{kebab .Name}}.model.ts",
+		Service: "services/{{kebab .Name}}/{{kebab .Name}}.service.ts",
+	}
+
+	err := Compile(context.Background(), exampleAPI(), TargetAngular, Options{Outputs: memoryOutputs})
+	assert.NoError(t, err)
+
+	files := memoryOutputs.Files()
+	assert.Contains(t, files, "models/person.model.ts")
+	assert.Contains(t, string(files["services/person/person.service.ts"]), "from '../../models/person.model'")
+	assert.Contains(t, string(files["services/person/person.service.ts"]), "from '../../api-config.config.gen'")
+	assert.Contains(t, string(files["index.ts"]), "from './services/person/person.service'")
+}
+
+func TestCompile_RejectsInvalidLayout(t *testing.T) {
+	err := Compile(context.Background(), exampleAPI(), TargetAngular, Options{OutputDir: t.TempDir(), Layout: Layout{Model: "{{kebab"}})
+	assert.Error(t, err)
+}
+
+func TestCompile_AliasesImportsThatCollideWithLocalNames(t *testing.T) {
+	api := exampleAPI()
+	api.Entities = append(api.Entities, EntitySpec{
+		Name: "Observable",
+		Properties: map[string]PropertySpec{
+			"id": {Type: DynamicType{TypeID: TypeID_STRING}, Required: true},
+		},
+	})
+	api.Services[0].Endpoints[0].ResponseBody.Type = DynamicType{TypeID: TypeID_USER, Reference: "Observable"}
+
+	memoryOutputs := NewMemoryOutputs()
+	err := Compile(context.Background(), api, TargetAngular, Options{Outputs: memoryOutputs})
+	assert.NoError(t, err)
+
+	service := string(memoryOutputs.Files()["person.service.gen.ts"])
+	assert.Contains(t, service, "import { Observable as ObservableModel, } from './observable.model.gen';")
+	assert.Contains(t, service, "getAll(): Observable<ObservableModel>")
+}
+
+func TestCompile_RejectsTypesProvidedTwice(t *testing.T) {
+	api := exampleAPI()
+	api.Entities = append(api.Entities, EntitySpec{Name: "APIConfig"})
+
+	err := Compile(context.Background(), api, TargetAngular, Options{Outputs: NewMemoryOutputs()})
+	assert.ErrorContains(t, err, "type 'APIConfig' is provided by both")
+}
+
+func TestCompile_ProvidesAndInjectsConfigThroughToken(t *testing.T) {
+	memoryOutputs := NewMemoryOutputs()
+
+	err := Compile(context.Background(), exampleAPI(), TargetAngular, Options{Outputs: memoryOutputs})
+	assert.NoError(t, err)
+
+	files := memoryOutputs.Files()
+	config := string(files["api-config.config.gen.ts"])
+	assert.Contains(t, config, "export const API_CONFIG = new InjectionToken<APIConfig>('APIConfig');")
+	assert.Contains(t, config, "provide: API_CONFIG,")
+	assert.NotContains(t, config, "provide: APIConfig")
+
+	service := string(files["person.service.gen.ts"])
+	assert.Contains(t, service, "import { APIConfig,API_CONFIG, } from './api-config.config.gen';")
+	assert.Contains(t, service, "private readonly config: APIConfig = inject(API_CONFIG);")
+
+	providers := string(files["providers.gen.ts"])
+	assert.Contains(t, providers, "provideAPIConfiguration(config),")
+	assert.Contains(t, string(files["index.ts"]), "API_CONFIG")
+}
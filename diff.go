@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"github.com/softwaresale/client-gen/v2/internal/specfile"
+)
+
+const (
+	DiffFormatText = "text"
+	DiffFormatJSON = "json"
+)
+
+// exitBreakingChanges is the exit code of the diff command when the new specification breaks existing clients
+const exitBreakingChanges = 3
+
+// DiffArgs specifies the arguments passed to the diff command
+type DiffArgs struct {
+	OldSpec      string
+	NewSpec      string
+	InputFormat  string
+	OutputFormat string
+}
+
+func runDiff(argv []string) int {
+	var args DiffArgs
+
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.StringVar(&args.InputFormat, "input-format", "", "Format of both specifications: json, jsonc or yaml. Detected from the file extensions by default")
+	flags.StringVar(&args.OutputFormat, "format", DiffFormatText, "Format of the report: text or json")
+	flags.Usage = func() {
+		fmt.Println("usage: client-gen diff [flags] <old spec> <new spec>")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(argv)
+	if err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	args.OldSpec, args.NewSpec = flags.Arg(0), flags.Arg(1)
+
+	if args.OutputFormat != DiffFormatText && args.OutputFormat != DiffFormatJSON {
+		fmt.Printf("unknown report format '%s'. Options are 'text' and 'json'\n", args.OutputFormat)
+		return 2
+	}
+
+	resolver := specfile.NewResolver()
+	if len(args.InputFormat) > 0 {
+		resolver.Format, err = specfile.ParseFormat(args.InputFormat)
+		if err != nil {
+			fmt.Println(err.Error())
+			return 2
+		}
+	}
+
+	oldAPI, err := readAPIDefinition(resolver, args.OldSpec)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	newAPI, err := readAPIDefinition(resolver, args.NewSpec)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	report := clientgen.DiffAPIDefinitions(oldAPI, newAPI)
+	if args.OutputFormat == DiffFormatJSON {
+		err = writeJSON(report, "")
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+	} else {
+		printDiffReport(report)
+	}
+
+	if report.HasBreakingChanges() {
+		return exitBreakingChanges
+	}
+
+	return 0
+}
+
+// printDiffReport prints the changes of a report, grouped by whether they break existing clients
+func printDiffReport(report clientgen.DiffReport) {
+	if len(report.Changes) == 0 {
+		fmt.Println("no changes")
+		return
+	}
+
+	for _, severity := range []clientgen.ChangeSeverity{clientgen.Severity_BREAKING, clientgen.Severity_NON_BREAKING} {
+		header := "Breaking changes:"
+		if severity == clientgen.Severity_NON_BREAKING {
+			header = "Non-breaking changes:"
+		}
+
+		printedHeader := false
+		for _, change := range report.Changes {
+			if change.Severity != severity {
+				continue
+			}
+
+			if !printedHeader {
+				fmt.Println(header)
+				printedHeader = true
+			}
+			fmt.Printf("  %s: %s\n", change.Location, change.Message)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%d breaking, %d non-breaking\n", report.Breaking, report.NonBreaking)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"os"
+	"os/signal"
+	"path/filepath"
+)
+
+const (
+	SourceGo     = "go"
+	SourceSpring = "spring"
+)
+
+// ExtractArgs specifies the arguments passed to the extract command
+type ExtractArgs struct {
+	Source  string
+	Dir     string
+	Output  string
+	Name    string
+	Version string
+	BaseURL string
+}
+
+func runExtract(argv []string) int {
+	var args ExtractArgs
+
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	flags.StringVar(&args.Source, "source", SourceGo, "Language of the sources. Options are ['go' (default), 'spring']")
+	flags.StringVar(&args.Dir, "dir", "", "Directory that package patterns or source paths are relative to. Defaults to the working directory")
+	flags.StringVar(&args.Output, "output", "", "Path to write the specification to. Defaults to standard output")
+	flags.StringVar(&args.Name, "name", "", "Name of the API. Defaults to the name of the first package")
+	flags.StringVar(&args.Version, "version", "", "Version of the API")
+	flags.StringVar(&args.BaseURL, "base-url", "", "Base URL of the API")
+	flags.Usage = func() {
+		fmt.Println("usage: client-gen extract [flags] [packages or source paths]")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(argv)
+	if err != nil {
+		return 2
+	}
+
+	apiDef, err := extract(args, flags.Args())
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	err = writeJSON(apiDef, args.Output)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// extract derives a specification from the sources in the language selected by the arguments
+func extract(args ExtractArgs, paths []string) (clientgen.APIDefinition, error) {
+	switch args.Source {
+	case SourceGo:
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		return clientgen.ExtractAPIDefinition(ctx, paths, clientgen.ExtractOptions{
+			Dir:     args.Dir,
+			Name:    args.Name,
+			Version: args.Version,
+			BaseURL: args.BaseURL,
+		})
+
+	case SourceSpring:
+		if len(paths) == 0 {
+			paths = []string{"."}
+		}
+
+		roots := make([]string, 0, len(paths))
+		for _, path := range paths {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(args.Dir, path)
+			}
+			roots = append(roots, path)
+		}
+
+		return clientgen.ExtractSpringAPIDefinition(roots, clientgen.SpringExtractOptions{
+			Name:    args.Name,
+			Version: args.Version,
+			BaseURL: args.BaseURL,
+		})
+
+	default:
+		return clientgen.APIDefinition{}, fmt.Errorf("unknown source language '%s'. Options are ['%s', '%s']", args.Source, SourceGo, SourceSpring)
+	}
+}
+
+// writeJSON writes a value, such as a specification, as JSON to the given file, or to standard output if no file is
+// given
+func writeJSON(value any, path string) error {
+	contents, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	contents = append(contents, '\n')
+
+	if len(path) == 0 {
+		_, err = os.Stdout.Write(contents)
+		return err
+	}
+
+	err = os.WriteFile(path, contents, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"github.com/softwaresale/client-gen/v2/internal/specfile"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/softwaresale/client-gen/v2/internal/watch"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// GenerateArgs specifies the arguments passed to the generate command
+type GenerateArgs struct {
+	InputSpec string
+	Format    string
+	OutputDir string
+	Target    TargetLanguage
+	Watch     bool
+	Interval  time.Duration
+	Package   bool
+	Scope     string
+	HTML      bool
+	Validate  bool
+	Zod       bool
+	Naming    string
+	Layout    clientgen.Layout
+}
+
+func runGenerate(argv []string) int {
+	args := GenerateArgs{
+		Target: TargetAngular,
+	}
+
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.StringVar(&args.InputSpec, "input", "", "Path to input specification")
+	flags.StringVar(&args.Format, "input-format", "", "Format of the input specification: json, jsonc or yaml. Detected from the file extension by default")
+	flags.StringVar(&args.OutputDir, "output-dir", "", "The path to write this output to")
+	flags.Var(&args.Target, "target", "The target language. Options are ['angular' (default), 'spring', 'docs']")
+	flags.BoolVar(&args.Watch, "watch", false, "Keep running and regenerate whenever the specification changes")
+	flags.BoolVar(&args.Package, "package", false, "Also emit a publishable npm package scaffold")
+	flags.StringVar(&args.Scope, "package-scope", "", "npm scope of the generated package, such as '@acme'. The leading '@' is added if it is missing")
+	flags.BoolVar(&args.HTML, "html", false, "For the docs target, generate a static HTML site instead of markdown")
+	flags.BoolVar(&args.Validate, "validators", false, "Emit runtime validators that check entities against their constraints")
+	flags.BoolVar(&args.Zod, "zod", false, "Emit zod schemas for entities and parse responses with them")
+	flags.StringVar(&args.Naming, "naming", "", "Overrides how property names are written on the wire: camel, snake, kebab or preserve")
+	flags.StringVar(&args.Layout.Model, "model-layout", "", "Pattern of entity output paths, such as 'models/{{kebab .Name}}.model.ts'")
+	flags.StringVar(&args.Layout.Service, "service-layout", "", "Pattern of service output paths, such as 'services/{{kebab .Name}}/{{kebab .Name}}.service.ts'")
+	flags.StringVar(&args.Layout.Config, "config-layout", "", "Pattern of the API config output path")
+	flags.DurationVar(&args.Interval, "watch-interval", 500*time.Millisecond, "How often to check for changes in watch mode")
+
+	err := flags.Parse(argv)
+	if err != nil {
+		return 2
+	}
+
+	if len(args.InputSpec) == 0 {
+		fmt.Println("input specification path is required")
+		return 2
+	}
+
+	if len(args.OutputDir) == 0 {
+		fmt.Println("output dir path is required")
+		return 2
+	}
+
+	if args.Interval <= 0 {
+		fmt.Printf("watch interval must be positive, but is %s\n", args.Interval)
+		return 2
+	}
+
+	err = args.Layout.Validate()
+	if err != nil {
+		fmt.Println(err.Error())
+		return 2
+	}
+
+	resolver := specfile.NewResolver()
+	if len(args.Format) > 0 {
+		resolver.Format, err = specfile.ParseFormat(args.Format)
+		if err != nil {
+			fmt.Println(err.Error())
+			return 2
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	outputs := clientgen.NewDirectoryOutputs(args.OutputDir)
+	outputs.Layout = args.Layout
+
+	err = generate(ctx, args, resolver, outputs)
+	if err != nil {
+		fmt.Println(err.Error())
+		if !args.Watch {
+			return 1
+		}
+	}
+
+	if !args.Watch {
+		return 0
+	}
+
+	fmt.Printf("watching %s for changes...\n", args.InputSpec)
+	_ = watchSpec(ctx, args, resolver, outputs)
+	return 0
+}
+
+// watchSpec regenerates the outputs whenever a file of the specification changes, until the context is cancelled.
+// Errors, including failures to write outputs, are reported but never stop watching
+func watchSpec(ctx context.Context, args GenerateArgs, resolver *specfile.Resolver, outputs *clientgen.DirectoryOutputs) error {
+	watcher := watch.PollingWatcher{
+		Paths:    func() []string { return watchedFiles(args, resolver) },
+		Interval: args.Interval,
+		Debounce: args.Interval,
+	}
+
+	return watcher.Run(ctx, func() {
+		err := generate(ctx, args, resolver, outputs)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	})
+}
+
+// watchedFiles gets every file of the specification, so that changing an included or referenced file regenerates
+func watchedFiles(args GenerateArgs, resolver *specfile.Resolver) []string {
+	files := resolver.Files()
+	if len(files) == 0 {
+		return []string{args.InputSpec}
+	}
+
+	return files
+}
+
+// generate reads the input specification and compiles it into the output directory
+func generate(ctx context.Context, args GenerateArgs, resolver *specfile.Resolver, outputs *clientgen.DirectoryOutputs) error {
+	apiDef, err := readAPIDefinition(resolver, args.InputSpec)
+	if err != nil {
+		return err
+	}
+
+	opts := clientgen.Options{
+		OnWarning: func(warning string) {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		},
+		OutputDir:    args.OutputDir,
+		Outputs:      outputs,
+		Package:      args.Package,
+		PackageScope: args.Scope,
+		HTML:         args.HTML,
+		Validators:   args.Validate,
+		Zod:          args.Zod,
+		Naming:       clientgen.NamingStrategy(args.Naming),
+	}
+
+	err = clientgen.Compile(ctx, apiDef, clientgen.Target(args.Target), opts)
+	if err != nil {
+		return fmt.Errorf("failed to compile API definition: %w", err)
+	}
+
+	stats := outputs.Stats()
+	fmt.Printf("%d created, %d updated, %d unchanged, %d removed\n", stats.Created, stats.Updated, stats.Unchanged, stats.Removed)
+	return nil
+}
+
+// readAPIDefinition resolves the specification rooted at the given file, loading every file it includes or references
+func readAPIDefinition(resolver *specfile.Resolver, path string) (types.APIDefinition, error) {
+	apiDef, err := resolver.Resolve(path)
+	if err != nil {
+		return types.APIDefinition{}, fmt.Errorf("failed to read API definition: %w", err)
+	}
+
+	return apiDef, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"github.com/softwaresale/client-gen/v2/internal/specfile"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSpec writes a specification with a single entity, served from the given base URL
+func writeSpec(t *testing.T, path, baseURL string) {
+	spec := fmt.Sprintf(`{
+  "name": "people",
+  "version": "1",
+  "config": {"baseURL": %q},
+  "entities": [{"name": "Person", "properties": {"name": {"type": {"typeID": "STRING"}, "required": true}}}],
+  "services": []
+}`, baseURL)
+	assert.NoError(t, os.WriteFile(path, []byte(spec), 0644))
+}
+
+// waitForFile waits until the file at the given path contains the given text
+func waitForFile(t *testing.T, path, text string) {
+	assert.Eventually(t, func() bool {
+		contents, err := os.ReadFile(path)
+		return err == nil && strings.Contains(string(contents), text)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRunGenerate_RejectsNonPositiveWatchIntervals(t *testing.T) {
+	for _, interval := range []string{"0s", "-1s"} {
+		argv := []string{"-input", "spec.json", "-output-dir", t.TempDir(), "-watch", "-watch-interval", interval}
+		assert.Equal(t, 2, runGenerate(argv), interval)
+	}
+}
+
+func TestWatchSpec_KeepsWatchingWhenWritingFails(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	outputDir := t.TempDir()
+	writeSpec(t, specPath, "http://one")
+
+	args := GenerateArgs{InputSpec: specPath, OutputDir: outputDir, Target: TargetAngular, Interval: 10 * time.Millisecond}
+	resolver := specfile.NewResolver()
+	outputs := clientgen.NewDirectoryOutputs(outputDir)
+	assert.NoError(t, generate(context.Background(), args, resolver, outputs))
+
+	// a directory is in the way of the entity's file, so the next regeneration fails to write it
+	entityPath := filepath.Join(outputDir, "person.model.gen.ts")
+	assert.NoError(t, os.Remove(entityPath))
+	assert.NoError(t, os.Mkdir(entityPath, 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watchSpec(ctx, args, resolver, outputs) }()
+
+	// give the watcher a chance to take its first snapshot
+	time.Sleep(50 * time.Millisecond)
+
+	// the config is written before the entity, so once it changes the failing regeneration has run
+	configPath := filepath.Join(outputDir, "api-config.config.gen.ts")
+	writeSpec(t, specPath, "http://two")
+	waitForFile(t, configPath, "http://two")
+
+	// give the failing regeneration a chance to finish, so that the watcher takes a new snapshot
+	time.Sleep(50 * time.Millisecond)
+
+	// once the directory is gone, the next change regenerates everything
+	assert.NoError(t, os.Remove(entityPath))
+	writeSpec(t, specPath, "http://three")
+	waitForFile(t, configPath, "http://three")
+	waitForFile(t, entityPath, "export interface Person")
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"os"
+)
+
+// InferArgs specifies the arguments passed to the infer command
+type InferArgs struct {
+	HAR     string
+	Output  string
+	Name    string
+	Version string
+	BaseURL string
+}
+
+func runInfer(argv []string) int {
+	var args InferArgs
+
+	flags := flag.NewFlagSet("infer", flag.ContinueOnError)
+	flags.StringVar(&args.HAR, "har", "", "Path to a HAR file of recorded traffic")
+	flags.StringVar(&args.Output, "output", "", "Path to write the draft specification to. Defaults to standard output")
+	flags.StringVar(&args.Name, "name", "", "Name of the API. Defaults to the host of the base URL")
+	flags.StringVar(&args.Version, "version", "", "Version of the API")
+	flags.StringVar(&args.BaseURL, "base-url", "", "Only infer requests below this URL. Defaults to the origin with the most requests")
+
+	err := flags.Parse(argv)
+	if err != nil {
+		return 2
+	}
+
+	if len(args.HAR) == 0 {
+		fmt.Println("HAR file path is required")
+		return 2
+	}
+
+	data, err := os.ReadFile(args.HAR)
+	if err != nil {
+		fmt.Printf("failed to read HAR file: %s\n", err)
+		return 1
+	}
+
+	archive, err := clientgen.ReadHAR(data)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	apiDef, err := clientgen.InferAPIDefinition(archive, clientgen.InferOptions{
+		Name:    args.Name,
+		Version: args.Version,
+		BaseURL: args.BaseURL,
+		OnWarning: func(warning string) {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		},
+	})
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	err = writeJSON(apiDef, args.Output)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	return 0
+}
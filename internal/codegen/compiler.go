@@ -1,13 +1,15 @@
 package codegen
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
 	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
 	"github.com/softwaresale/client-gen/v2/internal/codegen/servicegen"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
 	"github.com/softwaresale/client-gen/v2/internal/types"
-	"github.com/softwaresale/client-gen/v2/internal/utils"
-	"strings"
+	"slices"
 )
 
 // APICompiler compiles a service into a set of target files
@@ -16,23 +18,62 @@ type APICompiler struct {
 	ImportManager  imports.ImportManager          // helps us manage imports
 	OutputsManager outputs.CompilerOutputsManager // facilitates writing compiler outputs
 	OutputPath     string
+	Extension      string               // optional file extension the target's outputs need, which overrides the outputs manager's
+	Target         string               // name of the target, which picks target-specific mappings of external types
+	Identifiers    *identifiers.Policy  // optional identifier policy of the target language. Names are used as-is if unset
+	OnWarning      func(warning string) // optionally receives warnings about the API raised while compiling
 }
 
-func (compiler *APICompiler) Compile(api types.APIDefinition) error {
+// Compile generates every output for the given API definition. The context is checked between outputs so that
+// long-running compilations can be cancelled
+func (compiler *APICompiler) Compile(ctx context.Context, api types.APIDefinition) error {
 
 	var err error
 
+	// make sure every name is a valid identifier in the target language
+	if compiler.Identifiers != nil {
+		var renames []identifiers.Rename
+		api, renames = identifiers.SanitizeAPI(api, *compiler.Identifiers)
+		for _, rename := range renames {
+			compiler.warn(rename.String())
+		}
+	}
+
+	// entities that are provided by external types are imported instead of generated
+	api.Entities = slices.DeleteFunc(slices.Clone(api.Entities), func(entity types.EntitySpec) bool {
+		return api.IsExternal(entity.Name)
+	})
+
+	// targets that need their own file extension apply it to any outputs manager that supports one
+	if len(compiler.Extension) > 0 {
+		if setter, ok := compiler.OutputsManager.(outputs.ExtensionSetter); ok {
+			setter.SetExtension(compiler.Extension)
+		}
+	}
+
 	// Prepare the output destination. This is where all generated compiler outputs will go
 	err = compiler.OutputsManager.PrepareOutputDirectory(compiler.OutputPath)
 	if err != nil {
 		return fmt.Errorf("failed to setup output directory: %w", err)
 	}
 
+	// let the generator analyze the API as a whole
+	if preparer, ok := compiler.Generator.(servicegen.APIPreparer); ok {
+		err = preparer.PrepareAPI(api)
+		if err != nil {
+			return fmt.Errorf("failed to prepare API: %w", err)
+		}
+	}
+
+	// every output we generate, in the order it was generated
+	var generated []outputs.GeneratedOutput
+
 	// register the API configuration as a type
-	err = compiler.compileConfig(api.Config)
+	configOutput, err := compiler.compileConfig(api.Config)
 	if err != nil {
 		return fmt.Errorf("failed to compile config: %w", err)
 	}
+	generated = append(generated, configOutput)
 
 	// maps user types to paths that they can be imported from
 	err = compiler.registerEntities(api)
@@ -42,102 +83,192 @@ func (compiler *APICompiler) Compile(api types.APIDefinition) error {
 
 	// create all dependent entities
 	for _, entitySpec := range api.Entities {
-		err = compiler.compileEntity(entitySpec)
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		entityOutput, err := compiler.compileEntity(entitySpec)
 		if err != nil {
 			return fmt.Errorf("failed to compile entity '%s': %w", entitySpec.Name, err)
 		}
+		generated = append(generated, outputs.GeneratedOutput{Location: entityOutput, Type: outputs.OutputType_MODEL, Name: entitySpec.Name})
 	}
 
 	// Create all services
 	for _, service := range api.Services {
-		err = compiler.compileService(service)
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		serviceOutput, err := compiler.compileService(service)
 		if err != nil {
 			return fmt.Errorf("failed to compile service '%s': %w", service.Name, err)
 		}
+		generated = append(generated, outputs.GeneratedOutput{Location: serviceOutput, Type: outputs.OutputType_SERVICE, Name: service.Name})
+	}
+
+	// create any outputs that are not tied to a single part of the API
+	if auxGenerator, ok := compiler.Generator.(servicegen.AuxiliaryGenerator); ok {
+		for _, name := range auxGenerator.AuxiliaryOutputs(api) {
+			auxOutput, err := compiler.compileAuxiliary(auxGenerator, name, api, generated)
+			if err != nil {
+				return fmt.Errorf("failed to compile auxiliary output '%s': %w", name, err)
+			}
+			generated = append(generated, outputs.GeneratedOutput{Location: auxOutput, Type: outputs.OutputType_AUXILIARY, Name: name})
+		}
+	}
+
+	// let the outputs manager clean up after itself
+	err = compiler.OutputsManager.FinalizeOutputs()
+	if err != nil {
+		return fmt.Errorf("failed to finalize outputs: %w", err)
 	}
 
 	return nil
 }
 
-func (compiler *APICompiler) compileEntity(entitySpec types.EntitySpec) error {
+// warn reports a warning, if anyone is listening for them
+func (compiler *APICompiler) warn(warning string) {
+	if compiler.OnWarning != nil {
+		compiler.OnWarning(warning)
+	}
+}
+
+func (compiler *APICompiler) compileEntity(entitySpec types.EntitySpec) (_ outputs.CompilerOutputLocation, err error) {
 	entityWriter, err := compiler.OutputsManager.CreateModelOutput(entitySpec)
 	if err != nil {
-		return fmt.Errorf("failed to create model output: %w", err)
+		return nil, fmt.Errorf("failed to create model output: %w", err)
 	}
+	defer func() { err = closeOutput(entityWriter, err) }()
 
-	defer utils.SafeClose(entityWriter)
-
+	compiler.setImporter(entityWriter)
 	err = compiler.Generator.GenerateEntity(entityWriter, entitySpec, compiler.ImportManager)
 	if err != nil {
-		return fmt.Errorf("failed to write entity: %w", err)
+		return nil, fmt.Errorf("failed to write entity: %w", err)
 	}
 
-	return nil
+	return entityWriter, nil
 }
 
-func (compiler *APICompiler) compileService(service types.ServiceDefinition) error {
+func (compiler *APICompiler) compileService(service types.ServiceDefinition) (_ outputs.CompilerOutputLocation, err error) {
 	implWriter, err := compiler.OutputsManager.CreateServiceOutput(service)
 	if err != nil {
-		return fmt.Errorf("failed to create service output: %w", err)
+		return nil, fmt.Errorf("failed to create service output: %w", err)
 	}
-	defer utils.SafeClose(implWriter)
+	defer func() { err = closeOutput(implWriter, err) }()
 
 	// create the api implementation for each service in the API
+	compiler.setImporter(implWriter)
 	err = compiler.Generator.GenerateService(implWriter, service, compiler.ImportManager)
 	if err != nil {
-		return fmt.Errorf("failed to write service: %w", err)
+		return nil, fmt.Errorf("failed to write service: %w", err)
 	}
 
-	return nil
+	return implWriter, nil
 }
 
-func (compiler *APICompiler) compileConfig(config types.APIConfig) error {
+func (compiler *APICompiler) compileConfig(config types.APIConfig) (_ outputs.GeneratedOutput, err error) {
 
 	// register the API configuration type
 	configEntitySpec, err := config.CreateEntitySpec()
 	if err != nil {
-		return fmt.Errorf("failed to create entity spec: %w", err)
+		return outputs.GeneratedOutput{}, fmt.Errorf("failed to create entity spec: %w", err)
 	}
 
 	configWriter, err := compiler.OutputsManager.CreateConfigOutput(config)
 	if err != nil {
-		return fmt.Errorf("failed to create config output: %w", err)
+		return outputs.GeneratedOutput{}, fmt.Errorf("failed to create config output: %w", err)
 	}
-	defer utils.SafeClose(configWriter)
+	defer func() { err = closeOutput(configWriter, err) }()
 
 	// register the configuration type
-	providerName := formatProviderName(configWriter.Name())
-	compiler.ImportManager.RegisterType(providerName, configEntitySpec.Name)
+	err = compiler.ImportManager.RegisterType(configWriter.Location(), configEntitySpec.Name)
+	if err != nil {
+		return outputs.GeneratedOutput{}, fmt.Errorf("failed to register config type: %w", err)
+	}
 
 	// generate the configuration
+	compiler.setImporter(configWriter)
 	err = compiler.Generator.GenerateConfig(configWriter, config, compiler.ImportManager)
 	if err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
+		return outputs.GeneratedOutput{}, fmt.Errorf("failed to write config: %w", err)
 	}
 
-	return nil
+	return outputs.GeneratedOutput{Location: configWriter, Type: outputs.OutputType_CONFIG, Name: configEntitySpec.Name}, nil
+}
+
+func (compiler *APICompiler) compileAuxiliary(generator servicegen.AuxiliaryGenerator, name string, api types.APIDefinition, generated []outputs.GeneratedOutput) (_ outputs.CompilerOutputLocation, err error) {
+	auxWriter, err := compiler.OutputsManager.CreateAuxiliaryOutput(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auxiliary output: %w", err)
+	}
+	defer func() { err = closeOutput(auxWriter, err) }()
+
+	compiler.setImporter(auxWriter)
+	err = generator.GenerateAuxiliary(auxWriter, name, api, generated, compiler.ImportManager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write auxiliary output: %w", err)
+	}
+
+	return auxWriter, nil
+}
+
+// closeOutput closes an output once it has been generated, which commits its contents. If generating it failed, the
+// output is discarded first so that its partial contents are never committed. Errors of closing the output, such as
+// failing to write it to disk, are joined with the error of generating it
+func closeOutput(output outputs.CompilerOutputWriter, err error) error {
+	if err != nil {
+		if discardable, ok := output.(outputs.DiscardableOutput); ok {
+			discardable.Discard()
+		}
+	}
+
+	closeErr := output.Close()
+	if closeErr != nil {
+		closeErr = fmt.Errorf("failed to commit output '%s': %w", output.Location(), closeErr)
+	}
+
+	return errors.Join(err, closeErr)
 }
 
 // registerEntities registers all entities found in the API definition and works out which files
-// __will eventually contain them__. This does not actually create any files or modify the output
+// __will eventually contain them__. Entities are registered with the locations of their outputs, which the import
+// manager turns into whatever its target language imports. This does not actually create any files or modify the output
 // directory. This function just helps for generating imports
 func (compiler *APICompiler) registerEntities(api types.APIDefinition) error {
+	// external types only need to be registered if the target imports them
+	if externalImportManager, ok := compiler.ImportManager.(imports.ExternalImportManager); ok {
+		for _, external := range api.ExternalTypes {
+			mapping := external.ForTarget(compiler.Target)
+			if len(mapping.Module) == 0 {
+				return fmt.Errorf("external type '%s' does not have a module for target '%s'", external.Name, compiler.Target)
+			}
+
+			err := externalImportManager.RegisterExternalType(mapping.Module, mapping.Name, external.Name)
+			if err != nil {
+				return fmt.Errorf("failed to register external type '%s': %w", external.Name, err)
+			}
+		}
+	}
+
 	for _, entity := range api.Entities {
 		output, err := compiler.OutputsManager.ComputeModelLocation(entity)
 		if err != nil {
 			return fmt.Errorf("failed to compute model location: %w", err)
 		}
 
-		importProvider := formatProviderName(output.Name())
-		compiler.ImportManager.RegisterType(importProvider, entity.Name)
+		err = compiler.ImportManager.RegisterType(output.Location(), entity.Name)
+		if err != nil {
+			return fmt.Errorf("failed to register entity '%s': %w", entity.Name, err)
+		}
 	}
 
 	return nil
 }
 
-// TODO i don't like this...
-func formatProviderName(outputFileName string) string {
-	outputFileName = strings.TrimSuffix(outputFileName, ".ts")
-	outputFileName = fmt.Sprintf("./%s", outputFileName)
-	return outputFileName
+// setImporter tells the import manager which output is about to be generated, if its imports depend on it
+func (compiler *APICompiler) setImporter(output outputs.CompilerOutputLocation) {
+	if located, ok := compiler.ImportManager.(imports.LocatedImportManager); ok {
+		located.SetImporter(output.Location())
+	}
 }
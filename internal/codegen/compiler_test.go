@@ -1,13 +1,19 @@
 package codegen
 
 import (
+	"context"
 	"fmt"
 	importsmocks "github.com/softwaresale/client-gen/v2/internal/codegen/imports/mocks"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
 	outputsmocks "github.com/softwaresale/client-gen/v2/internal/codegen/outputs/mocks"
 	servicegenmocks "github.com/softwaresale/client-gen/v2/internal/codegen/servicegen/mocks"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
 	"github.com/softwaresale/client-gen/v2/internal/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -47,18 +53,23 @@ func setup(t *testing.T) {
 func configureDefaultAPIConfig(t *testing.T) {
 	outputName := "config"
 	mockConfigOutput := outputsmocks.NewMockCompilerOutputWriter(t)
-	mockConfigOutput.On("Name").Return(outputName).Once()
+	mockConfigOutput.On("Location").Return(outputName).Once()
 	mockConfigOutput.On("Close").Return(nil).Once()
 	mockOutputMan.On("CreateConfigOutput", apiDef.Config).Return(mockConfigOutput, nil).Once()
-	mockImportMan.On("RegisterType", fmt.Sprintf("./%s", outputName), "APIConfig").Return().Once()
+	mockImportMan.On("RegisterType", outputName, "APIConfig").Return(nil).Once()
 	mockServiceGen.On("GenerateConfig", mockConfigOutput, apiDef.Config, mockImportMan).Return(nil).Once()
 }
 
+func configureFinalize(t *testing.T) {
+	mockOutputMan.On("FinalizeOutputs").Return(nil).Once()
+}
+
 func TestAPICompiler_Compile_DoesNothing(t *testing.T) {
 	setup(t)
 	configureDefaultAPIConfig(t)
+	configureFinalize(t)
 
-	err := compiler.Compile(apiDef)
+	err := compiler.Compile(context.Background(), apiDef)
 	assert.NoError(t, err)
 	mockOutputMan.AssertExpectations(t)
 	mockServiceGen.AssertExpectations(t)
@@ -68,6 +79,7 @@ func TestAPICompiler_Compile_DoesNothing(t *testing.T) {
 func TestAPICompiler_Compile_GeneratesAnEntity(t *testing.T) {
 	setup(t)
 	configureDefaultAPIConfig(t)
+	configureFinalize(t)
 
 	// no properties
 	entity1 := types.EntitySpec{
@@ -77,17 +89,17 @@ func TestAPICompiler_Compile_GeneratesAnEntity(t *testing.T) {
 	apiDef.Entities = append(apiDef.Entities, entity1)
 
 	mockLocation := outputsmocks.NewMockCompilerOutputLocation(t)
-	mockLocation.On("Name").Return(entity1.Name).Once()
+	mockLocation.On("Location").Return(entity1.Name).Once()
 	mockOutput := outputsmocks.NewMockCompilerOutputWriter(t)
 	mockOutput.On("Close").Return(nil).Once()
 	mockOutputMan.On("ComputeModelLocation", entity1).Return(mockLocation, nil).Once()
 	mockOutputMan.On("CreateModelOutput", entity1).Return(mockOutput, nil).Once()
 
-	mockImportMan.On("RegisterType", mock.Anything, entity1.Name).Once()
+	mockImportMan.On("RegisterType", mock.Anything, entity1.Name).Return(nil).Once()
 
 	mockServiceGen.On("GenerateEntity", mockOutput, entity1, mockImportMan).Return(nil).Once()
 
-	err := compiler.Compile(apiDef)
+	err := compiler.Compile(context.Background(), apiDef)
 	assert.NoError(t, err)
 	mockOutputMan.AssertExpectations(t)
 	mockServiceGen.AssertExpectations(t)
@@ -97,6 +109,7 @@ func TestAPICompiler_Compile_GeneratesAnEntity(t *testing.T) {
 func TestAPICompiler_Compile_GeneratesAService(t *testing.T) {
 	setup(t)
 	configureDefaultAPIConfig(t)
+	configureFinalize(t)
 
 	service1 := types.ServiceDefinition{
 		Name: "service1",
@@ -109,9 +122,303 @@ func TestAPICompiler_Compile_GeneratesAService(t *testing.T) {
 
 	mockServiceGen.On("GenerateService", mockOutput, service1, mockImportMan).Return(nil).Once()
 
-	err := compiler.Compile(apiDef)
+	err := compiler.Compile(context.Background(), apiDef)
 	assert.NoError(t, err)
 	mockOutputMan.AssertExpectations(t)
 	mockServiceGen.AssertExpectations(t)
 	mockImportMan.AssertExpectations(t)
 }
+
+func TestAPICompiler_Compile_StopsWhenCancelled(t *testing.T) {
+	setup(t)
+	configureDefaultAPIConfig(t)
+
+	service1 := types.ServiceDefinition{
+		Name: "service1",
+	}
+	apiDef.Services = append(apiDef.Services, service1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := compiler.Compile(ctx, apiDef)
+	assert.ErrorIs(t, err, context.Canceled)
+	mockOutputMan.AssertNotCalled(t, "CreateServiceOutput", service1)
+}
+
+// auxServiceGenerator is a service generator that also produces auxiliary outputs
+type auxServiceGenerator struct {
+	*servicegenmocks.MockServiceGenerator
+	*servicegenmocks.MockAuxiliaryGenerator
+}
+
+func TestAPICompiler_Compile_GeneratesAuxiliaryOutputs(t *testing.T) {
+	setup(t)
+	configureDefaultAPIConfig(t)
+	configureFinalize(t)
+
+	mockAuxGen := servicegenmocks.NewMockAuxiliaryGenerator(t)
+	compiler.Generator = auxServiceGenerator{
+		MockServiceGenerator:   mockServiceGen,
+		MockAuxiliaryGenerator: mockAuxGen,
+	}
+
+	mockAuxOutput := outputsmocks.NewMockCompilerOutputWriter(t)
+	mockAuxOutput.On("Close").Return(nil).Once()
+	mockOutputMan.On("CreateAuxiliaryOutput", "index.ts").Return(mockAuxOutput, nil).Once()
+
+	mockAuxGen.On("AuxiliaryOutputs", apiDef).Return([]string{"index.ts"}).Once()
+	mockAuxGen.On("GenerateAuxiliary", mockAuxOutput, "index.ts", apiDef, mock.AnythingOfType("[]outputs.GeneratedOutput"), mockImportMan).
+		Run(func(args mock.Arguments) {
+			// the config output has already been generated
+			generated := args.Get(3).([]outputs.GeneratedOutput)
+			assert.Len(t, generated, 1)
+			assert.Equal(t, outputs.OutputType_CONFIG, generated[0].Type)
+		}).
+		Return(nil).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.NoError(t, err)
+}
+
+// preparingServiceGenerator is a service generator that also prepares the API before generating
+type preparingServiceGenerator struct {
+	*servicegenmocks.MockServiceGenerator
+	*servicegenmocks.MockAPIPreparer
+}
+
+func TestAPICompiler_Compile_PreparesAPIBeforeGenerating(t *testing.T) {
+	setup(t)
+
+	mockPreparer := servicegenmocks.NewMockAPIPreparer(t)
+	compiler.Generator = preparingServiceGenerator{
+		MockServiceGenerator: mockServiceGen,
+		MockAPIPreparer:      mockPreparer,
+	}
+
+	mockPreparer.On("PrepareAPI", apiDef).Return(fmt.Errorf("bad api")).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "bad api")
+	mockOutputMan.AssertNotCalled(t, "CreateConfigOutput", mock.Anything)
+}
+
+func TestAPICompiler_Compile_SanitizesNamesAndWarns(t *testing.T) {
+	setup(t)
+
+	mockPreparer := servicegenmocks.NewMockAPIPreparer(t)
+	compiler.Generator = preparingServiceGenerator{
+		MockServiceGenerator: mockServiceGen,
+		MockAPIPreparer:      mockPreparer,
+	}
+
+	var warnings []string
+	compiler.Identifiers = &identifiers.TypeScript
+	compiler.OnWarning = func(warning string) {
+		warnings = append(warnings, warning)
+	}
+
+	apiDef.Entities = []types.EntitySpec{{Name: "my-entity"}}
+
+	mockPreparer.On("PrepareAPI", mock.AnythingOfType("types.APIDefinition")).
+		Run(func(args mock.Arguments) {
+			prepared := args.Get(0).(types.APIDefinition)
+			assert.Equal(t, "MyEntity", prepared.Entities[0].Name)
+		}).
+		Return(fmt.Errorf("stop")).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "stop")
+	assert.Equal(t, []string{"entity 'my-entity' was renamed to 'MyEntity'"}, warnings)
+}
+
+func TestAPICompiler_Compile_SetsImporterOfLocatedImportManager(t *testing.T) {
+	setup(t)
+	configureFinalize(t)
+
+	mockLocatedImportMan := importsmocks.NewMockLocatedImportManager(t)
+	compiler.ImportManager = mockLocatedImportMan
+
+	configLocation := "out/api-config.ts"
+	mockConfigOutput := outputsmocks.NewMockCompilerOutputWriter(t)
+	mockConfigOutput.On("Location").Return(configLocation)
+	mockConfigOutput.On("Close").Return(nil).Once()
+	mockOutputMan.On("CreateConfigOutput", apiDef.Config).Return(mockConfigOutput, nil).Once()
+
+	entity1 := types.EntitySpec{Name: "entity1"}
+	apiDef.Entities = append(apiDef.Entities, entity1)
+
+	entityLocation := "out/models/entity1.ts"
+	mockLocation := outputsmocks.NewMockCompilerOutputLocation(t)
+	mockLocation.On("Location").Return(entityLocation).Once()
+	mockOutput := outputsmocks.NewMockCompilerOutputWriter(t)
+	mockOutput.On("Location").Return(entityLocation).Once()
+	mockOutput.On("Close").Return(nil).Once()
+	mockOutputMan.On("ComputeModelLocation", entity1).Return(mockLocation, nil).Once()
+	mockOutputMan.On("CreateModelOutput", entity1).Return(mockOutput, nil).Once()
+
+	// types are registered by the locations of their outputs, and each output is set as the importer before it is
+	// generated
+	mockLocatedImportMan.On("RegisterType", configLocation, "APIConfig").Return(nil).Once()
+	mockLocatedImportMan.On("RegisterType", entityLocation, entity1.Name).Return(nil).Once()
+	mockLocatedImportMan.On("SetImporter", configLocation).Return().Once()
+	mockLocatedImportMan.On("SetImporter", entityLocation).Return().Once()
+	mockServiceGen.On("GenerateConfig", mockConfigOutput, apiDef.Config, mockLocatedImportMan).Return(nil).Once()
+	mockServiceGen.On("GenerateEntity", mockOutput, entity1, mockLocatedImportMan).Return(nil).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.NoError(t, err)
+	mockLocatedImportMan.AssertExpectations(t)
+}
+
+func TestAPICompiler_Compile_ImportsExternalTypesInsteadOfGenerating(t *testing.T) {
+	setup(t)
+	configureFinalize(t)
+
+	mockExternalImportMan := importsmocks.NewMockExternalImportManager(t)
+	compiler.ImportManager = mockExternalImportMan
+	compiler.Target = "angular"
+
+	apiDef.ExternalTypes = []types.ExternalType{
+		{
+			Name:    "Money",
+			Module:  "@acme/common-models",
+			Targets: map[string]types.ExternalTypeMapping{"angular": {Name: "MoneyDto"}},
+		},
+	}
+	// declaring the external type as an entity does not generate it
+	apiDef.Entities = append(apiDef.Entities, types.EntitySpec{Name: "Money"})
+
+	mockConfigOutput := outputsmocks.NewMockCompilerOutputWriter(t)
+	mockConfigOutput.On("Location").Return("config")
+	mockConfigOutput.On("Close").Return(nil).Once()
+	mockOutputMan.On("CreateConfigOutput", apiDef.Config).Return(mockConfigOutput, nil).Once()
+	mockExternalImportMan.On("RegisterType", "config", "APIConfig").Return(nil).Once()
+	mockServiceGen.On("GenerateConfig", mockConfigOutput, apiDef.Config, mockExternalImportMan).Return(nil).Once()
+
+	mockExternalImportMan.On("RegisterExternalType", "@acme/common-models", "MoneyDto", "Money").Return(nil).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.NoError(t, err)
+	mockExternalImportMan.AssertExpectations(t)
+	mockOutputMan.AssertNotCalled(t, "CreateModelOutput", mock.Anything)
+	mockServiceGen.AssertNotCalled(t, "GenerateEntity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAPICompiler_Compile_FailsForExternalTypesWithoutModules(t *testing.T) {
+	setup(t)
+
+	mockExternalImportMan := importsmocks.NewMockExternalImportManager(t)
+	compiler.ImportManager = mockExternalImportMan
+	compiler.Target = "angular"
+	apiDef.ExternalTypes = []types.ExternalType{{Name: "Money"}}
+
+	mockConfigOutput := outputsmocks.NewMockCompilerOutputWriter(t)
+	mockConfigOutput.On("Location").Return("config")
+	mockConfigOutput.On("Close").Return(nil).Once()
+	mockOutputMan.On("CreateConfigOutput", apiDef.Config).Return(mockConfigOutput, nil).Once()
+	mockExternalImportMan.On("RegisterType", "config", "APIConfig").Return(nil).Once()
+	mockServiceGen.On("GenerateConfig", mockConfigOutput, apiDef.Config, mockExternalImportMan).Return(nil).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "external type 'Money' does not have a module for target 'angular'")
+}
+
+func TestAPICompiler_Compile_ReturnsErrorWhenOutputFailsToCommit(t *testing.T) {
+	setup(t)
+	configureDefaultAPIConfig(t)
+
+	entity1 := types.EntitySpec{Name: "entity1"}
+	apiDef.Entities = append(apiDef.Entities, entity1)
+
+	mockLocation := outputsmocks.NewMockCompilerOutputLocation(t)
+	mockLocation.On("Location").Return(entity1.Name).Once()
+	mockOutput := outputsmocks.NewMockCompilerOutputWriter(t)
+	mockOutput.On("Close").Return(fmt.Errorf("disk full")).Once()
+	mockOutput.On("Location").Return(entity1.Name).Once()
+	mockOutputMan.On("ComputeModelLocation", entity1).Return(mockLocation, nil).Once()
+	mockOutputMan.On("CreateModelOutput", entity1).Return(mockOutput, nil).Once()
+	mockImportMan.On("RegisterType", mock.Anything, entity1.Name).Return(nil).Once()
+	mockServiceGen.On("GenerateEntity", mockOutput, entity1, mockImportMan).Return(nil).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.EqualError(t, err, "failed to compile entity 'entity1': failed to commit output 'entity1': disk full")
+	mockOutputMan.AssertNotCalled(t, "FinalizeOutputs")
+}
+
+// setupDirectoryCompiler sets up a compiler that writes its outputs into a temporary directory, which it returns
+func setupDirectoryCompiler(t *testing.T) string {
+	outputDir := t.TempDir()
+
+	mockServiceGen = servicegenmocks.NewMockServiceGenerator(t)
+	mockImportMan = importsmocks.NewMockImportManager(t)
+	compiler = APICompiler{
+		Generator:      mockServiceGen,
+		ImportManager:  mockImportMan,
+		OutputsManager: &outputs.DirectoryCompilerOutputsManager{BasePath: outputDir},
+		OutputPath:     outputDir,
+	}
+
+	apiDef = types.APIDefinition{
+		Name:     "api",
+		Entities: []types.EntitySpec{{Name: "Person"}},
+	}
+
+	mockImportMan.On("RegisterType", mock.Anything, mock.Anything).Return(nil)
+	mockServiceGen.On("GenerateConfig", mock.Anything, mock.Anything, mockImportMan).Return(nil).Once()
+
+	return outputDir
+}
+
+func TestAPICompiler_Compile_ReturnsErrorWhenFileCannotBeWritten(t *testing.T) {
+	outputDir := setupDirectoryCompiler(t)
+	mockServiceGen.On("GenerateEntity", mock.Anything, apiDef.Entities[0], mockImportMan).Return(nil).Once()
+
+	// a directory is in the way of the entity's file
+	assert.NoError(t, os.Mkdir(filepath.Join(outputDir, "person.model.gen.ts"), 0755))
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "failed to compile entity 'Person': failed to commit output")
+}
+
+func TestAPICompiler_Compile_DiscardsOutputsThatFailToGenerate(t *testing.T) {
+	outputDir := setupDirectoryCompiler(t)
+	mockServiceGen.On("GenerateEntity", mock.Anything, apiDef.Entities[0], mockImportMan).
+		Run(func(args mock.Arguments) {
+			_, _ = args.Get(0).(io.Writer).Write([]byte("export interface Pers"))
+		}).
+		Return(fmt.Errorf("bad entity")).Once()
+
+	entityPath := filepath.Join(outputDir, "person.model.gen.ts")
+	assert.NoError(t, os.WriteFile(entityPath, []byte("previous"), 0644))
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.EqualError(t, err, "failed to compile entity 'Person': failed to write entity: bad entity")
+
+	contents, err := os.ReadFile(entityPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "previous", string(contents))
+}
+
+func TestAPICompiler_Compile_ReturnsErrorWhenSymlinkIsPlantedDuringGeneration(t *testing.T) {
+	outputDir := setupDirectoryCompiler(t)
+
+	outsideFile := filepath.Join(t.TempDir(), "victim.ts")
+	assert.NoError(t, os.WriteFile(outsideFile, []byte("precious"), 0644))
+
+	mockServiceGen.On("GenerateEntity", mock.Anything, apiDef.Entities[0], mockImportMan).
+		Run(func(args mock.Arguments) {
+			// the entity's file is swapped for a symlink out of the output directory while it is generated
+			assert.NoError(t, os.Symlink(outsideFile, filepath.Join(outputDir, "person.model.gen.ts")))
+			_, _ = args.Get(0).(io.Writer).Write([]byte("export interface Person {}"))
+		}).
+		Return(nil).Once()
+
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "failed to compile entity 'Person': failed to commit output")
+	assert.ErrorContains(t, err, "refusing to write output")
+
+	contents, err := os.ReadFile(outsideFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "precious", string(contents))
+}
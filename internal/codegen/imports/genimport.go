@@ -1,6 +1,10 @@
 package imports
 
-import "github.com/softwaresale/client-gen/v2/internal/types"
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"slices"
+	"strings"
+)
 
 // GenericImport provides an interface for generalized imports. An import accesses a number of
 // entities from a given provider
@@ -17,17 +21,49 @@ type GenericImport interface {
 //go:generate mockery --name ImportManager --structname MockImportManager --outpkg importsmocks
 type ImportManager interface {
 	RegisterProvider(providerName string)                              // RegisterProvider creates a new empty provider
-	RegisterType(providerName, typeName string)                        // RegisterType adds a type to the given provider
+	RegisterType(providerName, typeName string) error                  // RegisterType adds a type to the given provider. Fails if another provider already provides the type
 	GetEntityImports(entity ...types.EntitySpec) []GenericImport       // GetEntityImports gets a list of imports needed by this collection of entities
 	GetServiceImports(service types.ServiceDefinition) []GenericImport // GetServiceImports get all entities needed for the given service
 	GetImportForType(typeName string) (GenericImport, error)           // If the provided typename is registered, get an import for it
 }
 
+// LocatedImportManager is implemented by import managers whose imports depend on where the importing output is
+// located, such as those that import other outputs by relative path. Types are registered with the locations of the
+// outputs that provide them, and the compiler sets the importing output before each output is generated
+//
+//go:generate mockery --name LocatedImportManager --structname MockLocatedImportManager --outpkg importsmocks
+type LocatedImportManager interface {
+	ImportManager
+	SetImporter(location string)       // SetImporter sets the location of the output that imports are resolved for
+	ImportPath(location string) string // ImportPath gets the path that the current importer uses to import the output at the given location
+}
+
+// ExternalImportManager is implemented by import managers that can import types from modules outside of the generated
+// outputs, such as shared packages
+//
+//go:generate mockery --name ExternalImportManager --structname MockExternalImportManager --outpkg importsmocks
+type ExternalImportManager interface {
+	ImportManager
+	RegisterExternalType(module, exportedName, typeName string) error // RegisterExternalType registers a type that is imported from a module, where it may be exported under another name. Fails if the type is already provided
+}
+
+// ScopedImportManager is implemented by import managers that keep track of the names declared by the output being
+// generated. Imported types that collide with a declared name are imported under an alias, and generated code refers
+// to them by their local names
+//
+//go:generate mockery --name ScopedImportManager --structname MockScopedImportManager --outpkg importsmocks
+type ScopedImportManager interface {
+	ImportManager
+	DeclareScope(names ...string) error // DeclareScope starts the scope of the output being generated with the names it declares. Fails if a name is declared twice
+	LocalName(typeName string) string   // LocalName gets the name the output being generated refers to a type by
+}
+
 // ImportCombiner combines multiple imports with the same provider into a single import. Implementation
 // is target-specific
 type ImportCombiner func([]GenericImport) GenericImport
 
-// UnionImports combines imports that use the same provider
+// UnionImports combines imports that use the same provider. The resulting imports are sorted by provider so that
+// generated code is stable between runs
 func UnionImports(combiner ImportCombiner, importSets ...[]GenericImport) []GenericImport {
 
 	// find unique map of providers
@@ -51,5 +87,9 @@ func UnionImports(combiner ImportCombiner, importSets ...[]GenericImport) []Gene
 		finalizedImports = append(finalizedImports, combined)
 	}
 
+	slices.SortFunc(finalizedImports, func(a, b GenericImport) int {
+		return strings.Compare(a.Provider(), b.Provider())
+	})
+
 	return finalizedImports
 }
@@ -97,3 +97,20 @@ func TestUnionImports_EmptyWithEmptyInput(t *testing.T) {
 	result := UnionImports(CombineExampleImports)
 	assert.Empty(t, result)
 }
+
+func TestUnionImports_SortsByProvider(t *testing.T) {
+	importSet := []GenericImport{
+		&ExampleImport{ProviderName: "pkg3", Provides: []string{"e3"}},
+		&ExampleImport{ProviderName: "pkg1", Provides: []string{"e1"}},
+		&ExampleImport{ProviderName: "pkg2", Provides: []string{"e2"}},
+	}
+
+	unionedImports := UnionImports(CombineExampleImports, importSet)
+
+	var providers []string
+	for _, imp := range unionedImports {
+		providers = append(providers, imp.Provider())
+	}
+
+	assert.Equal(t, []string{"pkg1", "pkg2", "pkg3"}, providers)
+}
@@ -96,8 +96,21 @@ func (_m *MockImportManager) RegisterProvider(providerName string) {
 }
 
 // RegisterType provides a mock function with given fields: providerName, typeName
-func (_m *MockImportManager) RegisterType(providerName string, typeName string) {
-	_m.Called(providerName, typeName)
+func (_m *MockImportManager) RegisterType(providerName string, typeName string) error {
+	ret := _m.Called(providerName, typeName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterType")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(providerName, typeName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // NewMockImportManager creates a new instance of MockImportManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
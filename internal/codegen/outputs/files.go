@@ -1,8 +1,10 @@
 package outputs
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"github.com/iancoleman/strcase"
 	"github.com/softwaresale/client-gen/v2/internal/types"
 	"os"
 	"path/filepath"
@@ -21,39 +23,82 @@ func (path FileCompilerOutputLocation) Location() string {
 	return string(path)
 }
 
+// FileCompilerOutput buffers an output in memory. When it is closed, the file on disk is only replaced if its
+// contents actually changed
 type FileCompilerOutput struct {
-	file    *os.File
-	absPath FileCompilerOutputLocation
+	buffer    *bytes.Buffer
+	absPath   FileCompilerOutputLocation
+	manager   *DirectoryCompilerOutputsManager // the manager that created this output
+	discarded bool                             // whether closing the output commits nothing
 }
 
-func (f FileCompilerOutput) Write(p []byte) (int, error) {
-	return f.file.Write(p)
+func (f *FileCompilerOutput) Write(p []byte) (int, error) {
+	return f.buffer.Write(p)
 }
 
-func (f FileCompilerOutput) Location() string {
+func (f *FileCompilerOutput) Location() string {
 	return f.absPath.Location()
 }
 
-func (f FileCompilerOutput) Name() string {
+func (f *FileCompilerOutput) Name() string {
 	return f.absPath.Name()
 }
 
-func (f FileCompilerOutput) Close() error {
-	return f.file.Close()
+func (f *FileCompilerOutput) Close() error {
+	if f.discarded {
+		return nil
+	}
+
+	return f.manager.commitFile(f.absPath.Location(), f.buffer.Bytes())
+}
+
+// Discard drops the buffered contents, so that closing the output leaves the file on disk alone
+func (f *FileCompilerOutput) Discard() {
+	f.discarded = true
+	f.buffer.Reset()
 }
 
 const (
-	OutputType_SERVICE = "service"
-	OutputType_MODEL   = "model"
-	OutputType_CONFIG  = "config"
+	OutputType_SERVICE   = "service"
+	OutputType_MODEL     = "model"
+	OutputType_CONFIG    = "config"
+	OutputType_AUXILIARY = "auxiliary"
 )
 
-// DirectoryCompilerOutputsManager outputs our files in a directory
+// OutputStats counts what happened to each file during a compilation
+type OutputStats struct {
+	Created   int // files that did not exist before
+	Updated   int // files whose contents changed
+	Unchanged int // files that already had the generated contents and were left alone
+	Removed   int // stale files from a previous run that were removed
+}
+
+// DirectoryCompilerOutputsManager outputs our files in a directory. Every generated file is recorded in a manifest
+// so that files which are no longer generated can be cleaned up on the next run
 type DirectoryCompilerOutputsManager struct {
-	BasePath string // path that all outputs are relative to
+	BasePath  string            // path that all outputs are relative to
+	Extension string            // file extension of generated outputs. Defaults to DefaultExtension
+	Layout    Layout            // where each kind of output is written. Outputs are written flat into BasePath by default
+	generated map[string]string // relative path -> content hash of files generated during this run
+	claimed   outputClaims      // relative paths of the outputs created during this run
+	stats     OutputStats
+}
+
+// Stats gets the file statistics of the most recent compilation
+func (outputs *DirectoryCompilerOutputsManager) Stats() OutputStats {
+	return outputs.stats
+}
+
+// SetExtension sets the file extension of the outputs created from now on
+func (outputs *DirectoryCompilerOutputsManager) SetExtension(extension string) {
+	outputs.Extension = extension
 }
 
 func (outputs *DirectoryCompilerOutputsManager) PrepareOutputDirectory(path string) error {
+	// start tracking a fresh set of generated files
+	outputs.generated = make(map[string]string)
+	outputs.claimed = make(outputClaims)
+	outputs.stats = OutputStats{}
 	return setupOutputDirectory(path)
 }
 
@@ -67,15 +112,12 @@ func (outputs *DirectoryCompilerOutputsManager) ComputeServiceLocation(serviceDe
 }
 
 func (outputs *DirectoryCompilerOutputsManager) CreateServiceOutput(serviceDef types.ServiceDefinition) (CompilerOutputWriter, error) {
-	outputFile, outputAbsPath, err := outputs.createOutputFile(serviceDef.Name, OutputType_SERVICE)
+	output, err := outputs.createOutput(serviceDef.Name, OutputType_SERVICE)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create output file: %w", err)
 	}
 
-	return &FileCompilerOutput{
-		file:    outputFile,
-		absPath: FileCompilerOutputLocation(outputAbsPath),
-	}, nil
+	return output, nil
 }
 
 func (outputs *DirectoryCompilerOutputsManager) ComputeModelLocation(model types.EntitySpec) (CompilerOutputLocation, error) {
@@ -88,27 +130,21 @@ func (outputs *DirectoryCompilerOutputsManager) ComputeModelLocation(model types
 }
 
 func (outputs *DirectoryCompilerOutputsManager) CreateModelOutput(model types.EntitySpec) (CompilerOutputWriter, error) {
-	outputFile, outputAbsPath, err := outputs.createOutputFile(model.Name, OutputType_MODEL)
+	output, err := outputs.createOutput(model.Name, OutputType_MODEL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create output file: %w", err)
 	}
 
-	return &FileCompilerOutput{
-		file:    outputFile,
-		absPath: FileCompilerOutputLocation(outputAbsPath),
-	}, nil
+	return output, nil
 }
 
 func (outputs *DirectoryCompilerOutputsManager) CreateConfigOutput(config types.APIConfig) (CompilerOutputWriter, error) {
-	outputFile, outputAbsPath, err := outputs.createOutputFile("APIConfig", OutputType_CONFIG)
+	output, err := outputs.createOutput("APIConfig", OutputType_CONFIG)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create output file: %w", err)
 	}
 
-	return &FileCompilerOutput{
-		file:    outputFile,
-		absPath: FileCompilerOutputLocation(outputAbsPath),
-	}, nil
+	return output, nil
 }
 
 func (outputs *DirectoryCompilerOutputsManager) ComputeConfigLocation(config types.APIConfig) (CompilerOutputLocation, error) {
@@ -120,33 +156,272 @@ func (outputs *DirectoryCompilerOutputsManager) ComputeConfigLocation(config typ
 	return FileCompilerOutputLocation(outputAbsPath), nil
 }
 
-func createOutputFileName(objectName, objectType string) string {
-	return fmt.Sprintf("%s.%s.gen.ts", strcase.ToKebab(objectName), objectType)
+func (outputs *DirectoryCompilerOutputsManager) CreateAuxiliaryOutput(name string) (CompilerOutputWriter, error) {
+	outputAbsPath, err := outputs.createAuxiliaryFilePath(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	err = outputs.claimPath(outputAbsPath, describeOutput(name, OutputType_AUXILIARY))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	return outputs.newFileCompilerOutput(outputAbsPath), nil
+}
+
+func (outputs *DirectoryCompilerOutputsManager) ComputeAuxiliaryLocation(name string) (CompilerOutputLocation, error) {
+	outputAbsPath, err := outputs.createAuxiliaryFilePath(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute auxiliary location: %w", err)
+	}
+
+	return FileCompilerOutputLocation(outputAbsPath), nil
 }
 
+// FinalizeOutputs writes the manifest for this run and removes stale files generated by a previous run
+func (outputs *DirectoryCompilerOutputsManager) FinalizeOutputs() error {
+	manifestPath, err := outputs.resolvePath(ManifestFileName)
+	if err != nil {
+		return fmt.Errorf("refusing to use manifest: %w", err)
+	}
+
+	previous, err := readManifest(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read previous manifest: %w", err)
+	}
+
+	err = outputs.removeStaleFiles(previous)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale outputs: %w", err)
+	}
+
+	err = writeManifest(manifestPath, newManifest(outputs.generated))
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// commitFile writes the generated contents to the given absolute path. If the file already has these contents, it is
+// left untouched so that its modification time is preserved. Otherwise, the contents are written to a temporary file
+// which atomically replaces the original.
+func (outputs *DirectoryCompilerOutputsManager) commitFile(absPath string, contents []byte) error {
+	relPath, err := outputs.relativePath(absPath)
+	if err != nil {
+		return err
+	}
+
+	// the directory may have changed since the location was computed, so check it again right before writing
+	_, err = outputs.resolvePath(filepath.FromSlash(relPath))
+	if err != nil {
+		return fmt.Errorf("refusing to write output: %w", err)
+	}
+
+	if outputs.generated == nil {
+		outputs.generated = make(map[string]string)
+	}
+
+	contentHash := sha256.Sum256(contents)
+	outputs.generated[relPath] = hex.EncodeToString(contentHash[:])
+
+	existing, err := os.ReadFile(absPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read existing output: %w", err)
+	}
+
+	exists := err == nil
+	if exists && bytes.Equal(existing, contents) {
+		outputs.stats.Unchanged++
+		return nil
+	}
+
+	// layouts may place outputs into directories that do not exist yet
+	err = os.MkdirAll(filepath.Dir(absPath), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	err = replaceFile(absPath, contents)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		outputs.stats.Updated++
+	} else {
+		outputs.stats.Created++
+	}
+
+	return nil
+}
+
+// removeStaleFiles removes every file listed in the previous manifest that was not generated during this run. Files
+// are only removed if they still have the contents we generated, so anything edited by hand is left alone.
+func (outputs *DirectoryCompilerOutputsManager) removeStaleFiles(previous Manifest) error {
+	for _, entry := range previous.Files {
+		if _, stillGenerated := outputs.generated[entry.Path]; stillGenerated {
+			continue
+		}
+
+		// the manifest lives in the output directory, so its entries are not trusted any more than the spec
+		stalePath, err := outputs.resolvePath(filepath.FromSlash(entry.Path))
+		if err != nil {
+			return fmt.Errorf("refusing to remove stale output: %w", err)
+		}
+
+		currentHash, err := hashFile(stalePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to hash stale output '%s': %w", entry.Path, err)
+		}
+
+		if currentHash != entry.SHA256 {
+			// the file was modified after we generated it, so we no longer own it
+			continue
+		}
+
+		err = os.Remove(stalePath)
+		if err != nil {
+			return fmt.Errorf("failed to remove stale output '%s': %w", entry.Path, err)
+		}
+
+		outputs.stats.Removed++
+		outputs.removeEmptyDirectories(filepath.Dir(stalePath))
+	}
+
+	return nil
+}
+
+// removeEmptyDirectories removes the given directory and its parents, up to the output directory, for as long as
+// they are empty. Directories that still hold files are left alone
+func (outputs *DirectoryCompilerOutputsManager) removeEmptyDirectories(dir string) {
+	baseAbsPath, err := filepath.Abs(outputs.BasePath)
+	if err != nil {
+		return
+	}
+
+	for dir != baseAbsPath && isWithin(baseAbsPath, dir) {
+		// removing a directory that is not empty fails, which is exactly when we want to stop
+		if os.Remove(dir) != nil {
+			return
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
+
+func (outputs *DirectoryCompilerOutputsManager) relativePath(absPath string) (string, error) {
+	baseAbsPath, err := filepath.Abs(outputs.BasePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path of output directory: %w", err)
+	}
+
+	relPath, err := filepath.Rel(baseAbsPath, absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get path relative to output directory: %w", err)
+	}
+
+	return filepath.ToSlash(relPath), nil
+}
+
+// DefaultExtension is the file extension used for outputs when no other extension is configured
+const DefaultExtension = "ts"
+
 func (outputs *DirectoryCompilerOutputsManager) createOutputFilePath(objectName, objectType string) (string, error) {
-	outputFileName := createOutputFileName(objectName, objectType)
-	outputPath := filepath.Join(outputs.BasePath, outputFileName)
-	outputAbsPath, err := filepath.Abs(outputPath)
+	outputPath, err := outputs.Layout.Path(objectName, objectType, outputs.Extension)
+	if err != nil {
+		return "", err
+	}
+
+	outputAbsPath, err := outputs.resolvePath(filepath.FromSlash(outputPath))
 	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path of output file: %w", err)
+		return "", fmt.Errorf("name '%s' cannot be used for a %s output: %w", objectName, objectType, err)
 	}
 
 	return outputAbsPath, nil
 }
 
-func (outputs *DirectoryCompilerOutputsManager) createOutputFile(name, outputType string) (*os.File, string, error) {
+// createAuxiliaryFilePath gets the absolute path of an auxiliary output. Auxiliary outputs are named explicitly, so the
+// name is used as-is relative to the base path, as long as it stays inside of it
+func (outputs *DirectoryCompilerOutputsManager) createAuxiliaryFilePath(name string) (string, error) {
+	outputAbsPath, err := outputs.resolvePath(filepath.FromSlash(name))
+	if err != nil {
+		return "", fmt.Errorf("auxiliary output '%s' cannot be created: %w", name, err)
+	}
+
+	return outputAbsPath, nil
+}
+
+func (outputs *DirectoryCompilerOutputsManager) createOutput(name, outputType string) (*FileCompilerOutput, error) {
 	outputAbsPath, err := outputs.createOutputFilePath(name, outputType)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get absolute path of output file: %w", err)
+		return nil, fmt.Errorf("failed to get absolute path of output file: %w", err)
+	}
+
+	err = outputs.claimPath(outputAbsPath, describeOutput(name, outputType))
+	if err != nil {
+		return nil, err
 	}
 
-	outputFile, err := os.Create(outputAbsPath)
+	return outputs.newFileCompilerOutput(outputAbsPath), nil
+}
+
+// claimPath records that the described output is written to the given absolute path during this run
+func (outputs *DirectoryCompilerOutputsManager) claimPath(absPath, owner string) error {
+	relPath, err := outputs.relativePath(absPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create output file: %w", err)
+		return err
 	}
 
-	return outputFile, outputAbsPath, nil
+	return outputs.claimed.claim(relPath, owner)
+}
+
+func (outputs *DirectoryCompilerOutputsManager) newFileCompilerOutput(absPath string) *FileCompilerOutput {
+	return &FileCompilerOutput{
+		buffer:  &bytes.Buffer{},
+		absPath: FileCompilerOutputLocation(absPath),
+		manager: outputs,
+	}
+}
+
+// replaceFile atomically replaces the file at path with the given contents by writing a temporary file next to it
+// and renaming it into place
+func replaceFile(path string, contents []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), fmt.Sprintf(".%s-*.tmp", filepath.Base(path)))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary output file: %w", err)
+	}
+
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
+
+	_, err = tempFile.Write(contents)
+	if err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("failed to write temporary output file: %w", err)
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close temporary output file: %w", err)
+	}
+
+	err = os.Chmod(tempPath, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to set output file permissions: %w", err)
+	}
+
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace output file: %w", err)
+	}
+
+	return nil
 }
 
 func setupOutputDirectory(path string) error {
@@ -3,6 +3,7 @@ package outputs
 import (
 	"github.com/softwaresale/client-gen/v2/internal/types"
 	"github.com/stretchr/testify/assert"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -21,6 +22,16 @@ func TestFileCompilerOutputLocation_Location_IsJustPath(t *testing.T) {
 
 var directoryCompilerOutput *DirectoryCompilerOutputsManager
 
+// createOutputFileName gets the file name an output has under the default layout
+func createOutputFileName(objectName, objectType string) string {
+	fileName, err := Layout{}.Path(objectName, objectType, DefaultExtension)
+	if err != nil {
+		panic(err)
+	}
+
+	return fileName
+}
+
 func setup(t *testing.T) {
 	tempDir := t.TempDir()
 	directoryCompilerOutput = &DirectoryCompilerOutputsManager{
@@ -160,3 +171,301 @@ func TestDirectoryCompilerOutputsManager_ComputeConfigLocation_CorrectlyGenerate
 	assert.Equal(t, expectedName, location.Name())
 	assert.Equal(t, expectedLocation, location.Location())
 }
+
+func writeModel(t *testing.T, name, contents string) {
+	output, err := directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: name})
+	assert.NoError(t, err)
+	_, err = output.Write([]byte(contents))
+	assert.NoError(t, err)
+	assert.NoError(t, output.Close())
+}
+
+func TestDirectoryCompilerOutputsManager_FinalizeOutputs_WritesManifest(t *testing.T) {
+	setup(t)
+
+	writeModel(t, "SomeEntity", "Hello World")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	manifest, err := readManifest(filepath.Join(directoryCompilerOutput.BasePath, ManifestFileName))
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Files, 1)
+	assert.Equal(t, createOutputFileName("SomeEntity", OutputType_MODEL), manifest.Files[0].Path)
+	assert.NotEmpty(t, manifest.Files[0].SHA256)
+}
+
+func TestDirectoryCompilerOutputsManager_FinalizeOutputs_RemovesStaleFiles(t *testing.T) {
+	setup(t)
+	basePath := directoryCompilerOutput.BasePath
+
+	writeModel(t, "OldEntity", "old")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	// a file we did not generate should never be touched
+	userFile := filepath.Join(basePath, "user.ts")
+	assert.NoError(t, os.WriteFile(userFile, []byte("mine"), 0644))
+
+	// second run no longer produces the old entity
+	directoryCompilerOutput = &DirectoryCompilerOutputsManager{BasePath: basePath}
+	writeModel(t, "NewEntity", "new")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	assert.NoFileExists(t, filepath.Join(basePath, createOutputFileName("OldEntity", OutputType_MODEL)))
+	assert.FileExists(t, filepath.Join(basePath, createOutputFileName("NewEntity", OutputType_MODEL)))
+	assert.FileExists(t, userFile)
+}
+
+func TestDirectoryCompilerOutputsManager_FinalizeOutputs_KeepsModifiedStaleFiles(t *testing.T) {
+	setup(t)
+	basePath := directoryCompilerOutput.BasePath
+
+	writeModel(t, "OldEntity", "old")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	// somebody edited the generated file by hand
+	oldPath := filepath.Join(basePath, createOutputFileName("OldEntity", OutputType_MODEL))
+	assert.NoError(t, os.WriteFile(oldPath, []byte("edited"), 0644))
+
+	directoryCompilerOutput = &DirectoryCompilerOutputsManager{BasePath: basePath}
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	assert.FileExists(t, oldPath)
+}
+
+func TestDirectoryCompilerOutputsManager_CreateModelOutput_OnlyWritesOnClose(t *testing.T) {
+	setup(t)
+
+	output, err := directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: "SomeEntity"})
+	assert.NoError(t, err)
+	_, err = output.Write([]byte("Hello World"))
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, output.Location())
+	assert.NoError(t, output.Close())
+
+	contents, err := os.ReadFile(output.Location())
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello World", string(contents))
+}
+
+func TestDirectoryCompilerOutputsManager_Stats_CountsChanges(t *testing.T) {
+	setup(t)
+	basePath := directoryCompilerOutput.BasePath
+
+	assert.NoError(t, directoryCompilerOutput.PrepareOutputDirectory(basePath))
+	writeModel(t, "Unchanged", "same")
+	writeModel(t, "Changed", "before")
+	assert.Equal(t, OutputStats{Created: 2}, directoryCompilerOutput.Stats())
+
+	unchangedPath := filepath.Join(basePath, createOutputFileName("Unchanged", OutputType_MODEL))
+	before, err := os.Stat(unchangedPath)
+	assert.NoError(t, err)
+
+	assert.NoError(t, directoryCompilerOutput.PrepareOutputDirectory(basePath))
+	writeModel(t, "Unchanged", "same")
+	writeModel(t, "Changed", "after")
+	writeModel(t, "Added", "new")
+	assert.Equal(t, OutputStats{Created: 1, Updated: 1, Unchanged: 1}, directoryCompilerOutput.Stats())
+
+	after, err := os.Stat(unchangedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, before.ModTime(), after.ModTime())
+
+	contents, err := os.ReadFile(filepath.Join(basePath, createOutputFileName("Changed", OutputType_MODEL)))
+	assert.NoError(t, err)
+	assert.Equal(t, "after", string(contents))
+}
+
+func TestDirectoryCompilerOutputsManager_CreateAuxiliaryOutput_CorrectlyGeneratesLocation(t *testing.T) {
+	setup(t)
+
+	output, err := directoryCompilerOutput.CreateAuxiliaryOutput("index.ts")
+	assert.NoError(t, err)
+
+	location, err := directoryCompilerOutput.ComputeAuxiliaryLocation("index.ts")
+	assert.NoError(t, err)
+
+	expectedLocation := filepath.Join(directoryCompilerOutput.BasePath, "index.ts")
+	assert.Equal(t, "index.ts", output.Name())
+	assert.Equal(t, expectedLocation, output.Location())
+	assert.Equal(t, expectedLocation, location.Location())
+}
+
+func TestDirectoryCompilerOutputsManager_ComputeModelLocation_UsesExtension(t *testing.T) {
+	setup(t)
+	directoryCompilerOutput.Extension = "md"
+
+	location, err := directoryCompilerOutput.ComputeModelLocation(types.EntitySpec{Name: "SomeEntity"})
+	assert.NoError(t, err)
+	assert.Equal(t, "some-entity.model.gen.md", location.Name())
+}
+
+func TestDirectoryCompilerOutputsManager_ComputeModelLocation_RejectsPathTraversal(t *testing.T) {
+	setup(t)
+
+	_, err := directoryCompilerOutput.ComputeModelLocation(types.EntitySpec{Name: "../../etc/x"})
+	assert.ErrorContains(t, err, "path separator")
+
+	_, err = directoryCompilerOutput.CreateServiceOutput(types.ServiceDefinition{Name: `..\..\Windows`})
+	assert.ErrorContains(t, err, "path separator")
+}
+
+func TestDirectoryCompilerOutputsManager_CreateAuxiliaryOutput_RejectsEscapingNames(t *testing.T) {
+	setup(t)
+
+	for _, name := range []string{"../outside.ts", "nested/../../outside.ts", "/etc/passwd", ".", ""} {
+		_, err := directoryCompilerOutput.CreateAuxiliaryOutput(name)
+		assert.Error(t, err, name)
+	}
+
+	// staying inside of the output directory is fine, even if the path wanders around
+	location, err := directoryCompilerOutput.ComputeAuxiliaryLocation("nested/../index.ts")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(directoryCompilerOutput.BasePath, "index.ts"), location.Location())
+}
+
+func TestDirectoryCompilerOutputsManager_CreateModelOutput_RefusesSymlinkedFilesOutside(t *testing.T) {
+	setup(t)
+
+	outsideFile := filepath.Join(t.TempDir(), "victim.ts")
+	assert.NoError(t, os.WriteFile(outsideFile, []byte("precious"), 0644))
+
+	linkPath := filepath.Join(directoryCompilerOutput.BasePath, createOutputFileName("SomeEntity", OutputType_MODEL))
+	assert.NoError(t, os.Symlink(outsideFile, linkPath))
+
+	_, err := directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: "SomeEntity"})
+	assert.ErrorContains(t, err, "links outside of the output directory")
+
+	contents, err := os.ReadFile(outsideFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "precious", string(contents))
+}
+
+func TestDirectoryCompilerOutputsManager_CreateAuxiliaryOutput_RefusesSymlinkedDirectoriesOutside(t *testing.T) {
+	setup(t)
+
+	outsideDir := t.TempDir()
+	assert.NoError(t, os.Symlink(outsideDir, filepath.Join(directoryCompilerOutput.BasePath, "linked")))
+
+	_, err := directoryCompilerOutput.CreateAuxiliaryOutput("linked/index.ts")
+	assert.ErrorContains(t, err, "links outside of the output directory")
+}
+
+func TestDirectoryCompilerOutputsManager_CreateModelOutput_RechecksSymlinksOnClose(t *testing.T) {
+	setup(t)
+
+	output, err := directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: "SomeEntity"})
+	assert.NoError(t, err)
+
+	// the file is swapped for a symlink after its location was computed
+	outsideFile := filepath.Join(t.TempDir(), "victim.ts")
+	assert.NoError(t, os.WriteFile(outsideFile, []byte("precious"), 0644))
+	assert.NoError(t, os.Symlink(outsideFile, output.Location()))
+
+	_, err = output.Write([]byte("generated"))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, output.Close(), "refusing to write output")
+
+	contents, err := os.ReadFile(outsideFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "precious", string(contents))
+}
+
+func TestDirectoryCompilerOutputsManager_CreateModelOutput_AllowsSymlinkedOutputDirectory(t *testing.T) {
+	realDir := t.TempDir()
+	linkedDir := filepath.Join(t.TempDir(), "linked")
+	assert.NoError(t, os.Symlink(realDir, linkedDir))
+
+	directoryCompilerOutput = &DirectoryCompilerOutputsManager{BasePath: linkedDir}
+	writeModel(t, "SomeEntity", "Hello World")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	assert.FileExists(t, filepath.Join(realDir, createOutputFileName("SomeEntity", OutputType_MODEL)))
+}
+
+func TestDirectoryCompilerOutputsManager_FinalizeOutputs_RefusesEscapingManifestEntries(t *testing.T) {
+	setup(t)
+	basePath := directoryCompilerOutput.BasePath
+
+	outsideFile := filepath.Join(t.TempDir(), "victim.ts")
+	assert.NoError(t, os.WriteFile(outsideFile, []byte("precious"), 0644))
+	outsideHash, err := hashFile(outsideFile)
+	assert.NoError(t, err)
+
+	relPath, err := filepath.Rel(basePath, outsideFile)
+	assert.NoError(t, err)
+	manifest := Manifest{Files: []ManifestEntry{{Path: filepath.ToSlash(relPath), SHA256: outsideHash}}}
+	assert.NoError(t, writeManifest(filepath.Join(basePath, ManifestFileName), manifest))
+
+	err = directoryCompilerOutput.FinalizeOutputs()
+	assert.ErrorContains(t, err, "escapes the output directory")
+	assert.FileExists(t, outsideFile)
+}
+
+func TestDirectoryCompilerOutputsManager_CreateServiceOutput_UsesLayout(t *testing.T) {
+	setup(t)
+	directoryCompilerOutput.Layout = Layout{Service: "services/{{kebab .Name}}/{{kebab .Name}}.service.ts"}
+
+	output, err := directoryCompilerOutput.CreateServiceOutput(types.ServiceDefinition{Name: "PersonAdmin"})
+	assert.NoError(t, err)
+	_, err = output.Write([]byte("Hello World"))
+	assert.NoError(t, err)
+	assert.NoError(t, output.Close())
+
+	expectedLocation := filepath.Join(directoryCompilerOutput.BasePath, "services", "person-admin", "person-admin.service.ts")
+	assert.Equal(t, expectedLocation, output.Location())
+	assert.FileExists(t, expectedLocation)
+}
+
+func TestDirectoryCompilerOutputsManager_FinalizeOutputs_RemovesEmptiedDirectories(t *testing.T) {
+	setup(t)
+	basePath := directoryCompilerOutput.BasePath
+	layout := Layout{Model: "models/{{kebab .Name}}/{{kebab .Name}}.model.ts"}
+
+	directoryCompilerOutput.Layout = layout
+	writeModel(t, "OldEntity", "old")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+	assert.DirExists(t, filepath.Join(basePath, "models", "old-entity"))
+
+	directoryCompilerOutput = &DirectoryCompilerOutputsManager{BasePath: basePath, Layout: layout}
+	writeModel(t, "NewEntity", "new")
+	assert.NoError(t, directoryCompilerOutput.FinalizeOutputs())
+
+	assert.NoDirExists(t, filepath.Join(basePath, "models", "old-entity"))
+	assert.FileExists(t, filepath.Join(basePath, "models", "new-entity", "new-entity.model.ts"))
+}
+
+func TestDirectoryCompilerOutputsManager_ComputeModelLocation_RejectsLayoutsThatEscape(t *testing.T) {
+	setup(t)
+	directoryCompilerOutput.Layout = Layout{Model: "../{{kebab .Name}}.ts"}
+
+	_, err := directoryCompilerOutput.ComputeModelLocation(types.EntitySpec{Name: "SomeEntity"})
+	assert.ErrorContains(t, err, "escapes the output directory")
+}
+
+func TestDirectoryCompilerOutputsManager_CreateModelOutput_RejectsOutputsSharingAPath(t *testing.T) {
+	setup(t)
+	directoryCompilerOutput.Layout = Layout{Model: "models.ts"}
+	assert.NoError(t, directoryCompilerOutput.PrepareOutputDirectory(directoryCompilerOutput.BasePath))
+
+	_, err := directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: "Person"})
+	assert.NoError(t, err)
+
+	_, err = directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: "Address"})
+	assert.ErrorContains(t, err, "model 'Person' and model 'Address' are both written to 'models.ts'")
+
+	// every compile starts with a fresh set of paths
+	assert.NoError(t, directoryCompilerOutput.PrepareOutputDirectory(directoryCompilerOutput.BasePath))
+	_, err = directoryCompilerOutput.CreateModelOutput(types.EntitySpec{Name: "Address"})
+	assert.NoError(t, err)
+}
+
+func TestDirectoryCompilerOutputsManager_CreateAuxiliaryOutput_RejectsPathsOfOtherOutputs(t *testing.T) {
+	setup(t)
+	directoryCompilerOutput.Layout = Layout{Config: "index.ts"}
+
+	_, err := directoryCompilerOutput.CreateConfigOutput(types.APIConfig{})
+	assert.NoError(t, err)
+
+	_, err = directoryCompilerOutput.CreateAuxiliaryOutput("index.ts")
+	assert.ErrorContains(t, err, "config 'APIConfig' and auxiliary 'index.ts' are both written to 'index.ts'")
+}
@@ -0,0 +1,145 @@
+package outputs
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultLayoutPattern is the layout pattern used for any kind of output that does not have one configured. It
+// writes every output flat into the output directory
+const DefaultLayoutPattern = "{{kebab .Name}}.{{.Type}}.gen.{{.Extension}}"
+
+// Layout decides where each kind of output is written. Each pattern is a Go template that produces a slash-separated
+// path relative to the root of the outputs, such as "services/{{kebab .Name}}/{{kebab .Name}}.service.ts". Patterns
+// are given a LayoutData and can use the kebab, snake, camel, and lowerCamel functions to format names. Empty
+// patterns fall back to DefaultLayoutPattern
+type Layout struct {
+	Model   string // pattern of entity outputs
+	Service string // pattern of service outputs
+	Config  string // pattern of the API config output
+}
+
+// LayoutData is the data given to layout patterns
+type LayoutData struct {
+	Name      string // name of the entity, service, or config
+	Type      string // kind of output, one of the OutputType_ constants
+	Extension string // file extension of generated outputs
+}
+
+var layoutFuncs = template.FuncMap{
+	"kebab":      strcase.ToKebab,
+	"snake":      strcase.ToSnake,
+	"camel":      strcase.ToCamel,
+	"lowerCamel": strcase.ToLowerCamel,
+}
+
+// Validate checks that every pattern of the layout is a valid template
+func (layout Layout) Validate() error {
+	for _, outputType := range []string{OutputType_MODEL, OutputType_SERVICE, OutputType_CONFIG} {
+		_, err := parseLayoutPattern(layout.pattern(outputType))
+		if err != nil {
+			return fmt.Errorf("invalid %s layout: %w", outputType, err)
+		}
+	}
+
+	return nil
+}
+
+// Path gets the slash-separated path of an output relative to the root of the outputs. Names are checked before they
+// are placed into the pattern, so a name can never add directories to the path
+func (layout Layout) Path(objectName, objectType, extension string) (string, error) {
+	err := checkName(objectName)
+	if err != nil {
+		return "", fmt.Errorf("name '%s' cannot be used for a %s output: %w", objectName, objectType, err)
+	}
+
+	if len(extension) == 0 {
+		extension = DefaultExtension
+	}
+
+	layoutTemplate, err := parseLayoutPattern(layout.pattern(objectType))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s layout: %w", objectType, err)
+	}
+
+	var rendered bytes.Buffer
+	err = layoutTemplate.Execute(&rendered, LayoutData{Name: objectName, Type: objectType, Extension: extension})
+	if err != nil {
+		return "", fmt.Errorf("failed to apply %s layout to '%s': %w", objectType, objectName, err)
+	}
+
+	outputPath := path.Clean(strings.TrimSpace(rendered.String()))
+	if outputPath == "." || strings.HasSuffix(rendered.String(), "/") {
+		return "", fmt.Errorf("%s layout of '%s' does not name a file", objectType, objectName)
+	}
+
+	return outputPath, nil
+}
+
+// outputClaims records which output was rendered to each path during a compile, so that outputs whose layouts render
+// to the same path are reported instead of silently overwriting each other
+type outputClaims map[string]string // output path -> description of the output rendered to it
+
+// claim records that the described output is rendered to the given path. It fails if another output already claimed it
+func (claims *outputClaims) claim(outputPath, owner string) error {
+	if *claims == nil {
+		*claims = make(outputClaims)
+	}
+
+	if previous, claimed := (*claims)[outputPath]; claimed {
+		return fmt.Errorf("%s and %s are both written to '%s'", previous, owner, outputPath)
+	}
+
+	(*claims)[outputPath] = owner
+	return nil
+}
+
+// describeOutput describes an output for error messages, such as "model 'Person'"
+func describeOutput(objectName, objectType string) string {
+	return fmt.Sprintf("%s '%s'", objectType, objectName)
+}
+
+func (layout Layout) pattern(objectType string) string {
+	var pattern string
+	switch objectType {
+	case OutputType_MODEL:
+		pattern = layout.Model
+	case OutputType_SERVICE:
+		pattern = layout.Service
+	case OutputType_CONFIG:
+		pattern = layout.Config
+	}
+
+	if len(pattern) == 0 {
+		return DefaultLayoutPattern
+	}
+
+	return pattern
+}
+
+func parseLayoutPattern(pattern string) (*template.Template, error) {
+	return template.New("layout").Funcs(layoutFuncs).Parse(pattern)
+}
+
+// RelativeLocation gets the path from the output at one location to the output at another. Locations are either both
+// absolute or both relative to the same root. The result is slash-separated and always starts with "./" or "../", so
+// it can be used as a relative import or link
+func RelativeLocation(from, to string) string {
+	relPath, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		// the locations do not share a root, so the best we can do is the location itself
+		return filepath.ToSlash(to)
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	if relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return relPath
+	}
+
+	return "./" + relPath
+}
@@ -0,0 +1,61 @@
+package outputs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLayout_Path_DefaultsToFlatLayout(t *testing.T) {
+	outputPath, err := Layout{}.Path("SomeEntity", OutputType_MODEL, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "some-entity.model.gen.ts", outputPath)
+}
+
+func TestLayout_Path_AppliesPatterns(t *testing.T) {
+	layout := Layout{
+		Model:   "models/{{kebab .Name}}.model.ts",
+		Service: "services/{{kebab .Name}}/{{kebab .Name}}.service.{{.Extension}}",
+		Config:  "{{snake .Name}}.ts",
+	}
+
+	modelPath, err := layout.Path("SomeEntity", OutputType_MODEL, "ts")
+	assert.NoError(t, err)
+	assert.Equal(t, "models/some-entity.model.ts", modelPath)
+
+	servicePath, err := layout.Path("PersonAdmin", OutputType_SERVICE, "ts")
+	assert.NoError(t, err)
+	assert.Equal(t, "services/person-admin/person-admin.service.ts", servicePath)
+
+	configPath, err := layout.Path("APIConfig", OutputType_CONFIG, "ts")
+	assert.NoError(t, err)
+	assert.Equal(t, "api_config.ts", configPath)
+}
+
+func TestLayout_Path_RejectsNamesWithSeparators(t *testing.T) {
+	layout := Layout{Model: "models/{{.Name}}.ts"}
+
+	_, err := layout.Path("../../etc/x", OutputType_MODEL, "ts")
+	assert.ErrorContains(t, err, "path separator")
+
+	_, err = layout.Path("..", OutputType_MODEL, "ts")
+	assert.Error(t, err)
+}
+
+func TestLayout_Path_RejectsPatternsWithoutFiles(t *testing.T) {
+	_, err := Layout{Model: "models/"}.Path("SomeEntity", OutputType_MODEL, "ts")
+	assert.ErrorContains(t, err, "does not name a file")
+}
+
+func TestLayout_Validate_RejectsInvalidPatterns(t *testing.T) {
+	assert.NoError(t, Layout{Model: "models/{{kebab .Name}}.ts"}.Validate())
+	assert.Error(t, Layout{Service: "{{kebab .Name"}.Validate())
+	assert.Error(t, Layout{Config: "{{unknown .Name}}"}.Validate())
+}
+
+func TestRelativeLocation_ComputesRelativePaths(t *testing.T) {
+	assert.Equal(t, "./person.model.gen.ts", RelativeLocation("person.service.gen.ts", "person.model.gen.ts"))
+	assert.Equal(t, "./person.model.gen.ts", RelativeLocation("", "person.model.gen.ts"))
+	assert.Equal(t, "../../models/person.model.ts", RelativeLocation("services/person/person.service.ts", "models/person.model.ts"))
+	assert.Equal(t, "./models/person.model.ts", RelativeLocation("index.ts", "models/person.model.ts"))
+	assert.Equal(t, "../b/c.ts", RelativeLocation("/out/a/x.ts", "/out/b/c.ts"))
+}
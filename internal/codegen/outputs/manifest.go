@@ -0,0 +1,93 @@
+package outputs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ManifestFileName is the name of the manifest file written into the output directory
+const ManifestFileName = ".client-gen-manifest.json"
+
+// ManifestEntry describes a single generated file
+type ManifestEntry struct {
+	Path   string `json:"path"`   // slash-separated path relative to the output directory
+	SHA256 string `json:"sha256"` // hex encoded hash of the generated contents
+}
+
+// Manifest lists every file generated during a compilation
+type Manifest struct {
+	Files []ManifestEntry `json:"files"`
+}
+
+// newManifest creates a manifest from a map of relative paths to content hashes. Entries are sorted so that the
+// manifest is stable between runs
+func newManifest(generated map[string]string) Manifest {
+	manifest := Manifest{
+		Files: make([]ManifestEntry, 0, len(generated)),
+	}
+
+	for path, contentHash := range generated {
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Path:   path,
+			SHA256: contentHash,
+		})
+	}
+
+	slices.SortFunc(manifest.Files, func(a, b ManifestEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return manifest
+}
+
+// readManifest reads the manifest at the given path. A missing manifest is treated as empty
+func readManifest(path string) (Manifest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Manifest{}, nil
+		}
+
+		return Manifest{}, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	err = json.Unmarshal(contents, &manifest)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+func writeManifest(path string, manifest Manifest) error {
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return os.WriteFile(path, append(contents, '\n'), 0644)
+}
+
+// hashFile computes the hex encoded SHA-256 hash of a file's contents
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
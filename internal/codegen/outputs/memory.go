@@ -0,0 +1,186 @@
+package outputs
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"path"
+	"sync"
+)
+
+// MemoryCompilerOutputLocation is the location of an output held in memory. Locations are slash-separated paths
+// relative to the root of the outputs
+type MemoryCompilerOutputLocation string
+
+func (location MemoryCompilerOutputLocation) Name() string {
+	return path.Base(string(location))
+}
+
+func (location MemoryCompilerOutputLocation) Location() string {
+	return string(location)
+}
+
+// MemoryCompilerOutput buffers an output until it is closed, at which point the contents are committed to the
+// manager that created it
+type MemoryCompilerOutput struct {
+	buffer    bytes.Buffer
+	location  MemoryCompilerOutputLocation
+	manager   *MemoryCompilerOutputsManager
+	discarded bool // whether closing the output commits nothing
+}
+
+func (output *MemoryCompilerOutput) Write(p []byte) (int, error) {
+	return output.buffer.Write(p)
+}
+
+func (output *MemoryCompilerOutput) Name() string {
+	return output.location.Name()
+}
+
+func (output *MemoryCompilerOutput) Location() string {
+	return output.location.Location()
+}
+
+func (output *MemoryCompilerOutput) Close() error {
+	if !output.discarded {
+		output.manager.commit(output.location.Location(), output.buffer.Bytes())
+	}
+	return nil
+}
+
+// Discard drops the buffered contents, so that closing the output commits nothing
+func (output *MemoryCompilerOutput) Discard() {
+	output.discarded = true
+	output.buffer.Reset()
+}
+
+// MemoryCompilerOutputsManager keeps all outputs in memory instead of writing them to the filesystem. This is useful
+// for embedding the compiler or post-processing outputs before they are persisted
+type MemoryCompilerOutputsManager struct {
+	Extension string // file extension of generated outputs. Defaults to DefaultExtension
+	Layout    Layout // where each kind of output is placed. Outputs are placed flat at the root by default
+	lock      sync.Mutex
+	files     map[string][]byte // output path -> contents
+	claimed   outputClaims      // paths of the outputs created during this compile
+}
+
+// NewMemoryCompilerOutputsManager creates an empty in-memory outputs manager
+func NewMemoryCompilerOutputsManager() *MemoryCompilerOutputsManager {
+	return &MemoryCompilerOutputsManager{
+		files: make(map[string][]byte),
+	}
+}
+
+// Files gets a copy of every committed output, keyed by its path relative to the output root
+func (outputs *MemoryCompilerOutputsManager) Files() map[string][]byte {
+	outputs.lock.Lock()
+	defer outputs.lock.Unlock()
+
+	return maps.Clone(outputs.files)
+}
+
+// SetExtension sets the file extension of the outputs created from now on
+func (outputs *MemoryCompilerOutputsManager) SetExtension(extension string) {
+	outputs.Extension = extension
+}
+
+// PrepareOutputDirectory discards the outputs of any previous compile, as there is no directory to prepare. Outputs
+// are always keyed relative to the output root, so the path is ignored.
+func (outputs *MemoryCompilerOutputsManager) PrepareOutputDirectory(path string) error {
+	outputs.lock.Lock()
+	defer outputs.lock.Unlock()
+
+	outputs.files = make(map[string][]byte)
+	outputs.claimed = make(outputClaims)
+	return nil
+}
+
+func (outputs *MemoryCompilerOutputsManager) CreateServiceOutput(serviceDef types.ServiceDefinition) (CompilerOutputWriter, error) {
+	return outputs.createOutput(serviceDef.Name, OutputType_SERVICE)
+}
+
+func (outputs *MemoryCompilerOutputsManager) ComputeServiceLocation(serviceDef types.ServiceDefinition) (CompilerOutputLocation, error) {
+	return outputs.computeLocation(serviceDef.Name, OutputType_SERVICE)
+}
+
+func (outputs *MemoryCompilerOutputsManager) CreateModelOutput(model types.EntitySpec) (CompilerOutputWriter, error) {
+	return outputs.createOutput(model.Name, OutputType_MODEL)
+}
+
+func (outputs *MemoryCompilerOutputsManager) ComputeModelLocation(model types.EntitySpec) (CompilerOutputLocation, error) {
+	return outputs.computeLocation(model.Name, OutputType_MODEL)
+}
+
+func (outputs *MemoryCompilerOutputsManager) CreateConfigOutput(config types.APIConfig) (CompilerOutputWriter, error) {
+	return outputs.createOutput("APIConfig", OutputType_CONFIG)
+}
+
+func (outputs *MemoryCompilerOutputsManager) ComputeConfigLocation(config types.APIConfig) (CompilerOutputLocation, error) {
+	return outputs.computeLocation("APIConfig", OutputType_CONFIG)
+}
+
+func (outputs *MemoryCompilerOutputsManager) CreateAuxiliaryOutput(name string) (CompilerOutputWriter, error) {
+	err := outputs.claimPath(path.Clean(name), describeOutput(name, OutputType_AUXILIARY))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryCompilerOutput{
+		location: MemoryCompilerOutputLocation(name),
+		manager:  outputs,
+	}, nil
+}
+
+func (outputs *MemoryCompilerOutputsManager) ComputeAuxiliaryLocation(name string) (CompilerOutputLocation, error) {
+	return MemoryCompilerOutputLocation(name), nil
+}
+
+// FinalizeOutputs does nothing, as all outputs are committed when they are closed
+func (outputs *MemoryCompilerOutputsManager) FinalizeOutputs() error {
+	return nil
+}
+
+func (outputs *MemoryCompilerOutputsManager) computeLocation(objectName, objectType string) (CompilerOutputLocation, error) {
+	outputPath, err := outputs.Layout.Path(objectName, objectType, outputs.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	return MemoryCompilerOutputLocation(outputPath), nil
+}
+
+func (outputs *MemoryCompilerOutputsManager) createOutput(objectName, objectType string) (CompilerOutputWriter, error) {
+	outputPath, err := outputs.Layout.Path(objectName, objectType, outputs.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	err = outputs.claimPath(outputPath, describeOutput(objectName, objectType))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryCompilerOutput{
+		location: MemoryCompilerOutputLocation(outputPath),
+		manager:  outputs,
+	}, nil
+}
+
+// claimPath records that the described output is placed at the given path during this compile
+func (outputs *MemoryCompilerOutputsManager) claimPath(outputPath, owner string) error {
+	outputs.lock.Lock()
+	defer outputs.lock.Unlock()
+
+	return outputs.claimed.claim(outputPath, owner)
+}
+
+func (outputs *MemoryCompilerOutputsManager) commit(location string, contents []byte) {
+	outputs.lock.Lock()
+	defer outputs.lock.Unlock()
+
+	// the zero value is usable, so the map may not be allocated yet
+	if outputs.files == nil {
+		outputs.files = make(map[string][]byte)
+	}
+	outputs.files[location] = bytes.Clone(contents)
+}
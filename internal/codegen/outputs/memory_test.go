@@ -0,0 +1,109 @@
+package outputs
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMemoryCompilerOutputsManager_CreateModelOutput_CommitsOnClose(t *testing.T) {
+	memoryOutputs := NewMemoryCompilerOutputsManager()
+
+	model := types.EntitySpec{
+		Name: "SomeEntity",
+	}
+
+	output, err := memoryOutputs.CreateModelOutput(model)
+	assert.NoError(t, err)
+
+	expectedName := createOutputFileName(model.Name, OutputType_MODEL)
+	assert.Equal(t, expectedName, output.Name())
+	assert.Equal(t, expectedName, output.Location())
+
+	_, err = output.Write([]byte("Hello World"))
+	assert.NoError(t, err)
+
+	// nothing is visible until the output is closed
+	assert.Empty(t, memoryOutputs.Files())
+
+	assert.NoError(t, output.Close())
+	assert.Equal(t, map[string][]byte{expectedName: []byte("Hello World")}, memoryOutputs.Files())
+}
+
+func TestMemoryCompilerOutputsManager_ComputeServiceLocation_MatchesCreatedOutput(t *testing.T) {
+	memoryOutputs := NewMemoryCompilerOutputsManager()
+
+	service := types.ServiceDefinition{
+		Name: "SomeService",
+	}
+
+	location, err := memoryOutputs.ComputeServiceLocation(service)
+	assert.NoError(t, err)
+
+	output, err := memoryOutputs.CreateServiceOutput(service)
+	assert.NoError(t, err)
+
+	assert.Equal(t, location.Location(), output.Location())
+	assert.Equal(t, location.Name(), output.Name())
+}
+
+func TestMemoryCompilerOutputsManager_Files_ReturnsCopy(t *testing.T) {
+	memoryOutputs := NewMemoryCompilerOutputsManager()
+
+	output, err := memoryOutputs.CreateConfigOutput(types.APIConfig{})
+	assert.NoError(t, err)
+	assert.NoError(t, output.Close())
+
+	files := memoryOutputs.Files()
+	delete(files, output.Location())
+
+	assert.Len(t, memoryOutputs.Files(), 1)
+}
+
+func TestMemoryCompilerOutputsManager_CreateModelOutput_UsesLayout(t *testing.T) {
+	memoryOutputs := NewMemoryCompilerOutputsManager()
+	memoryOutputs.Layout = Layout{Model: "models/{{kebab .Name}}.model.ts"}
+
+	output, err := memoryOutputs.CreateModelOutput(types.EntitySpec{Name: "SomeEntity"})
+	assert.NoError(t, err)
+	assert.Equal(t, "models/some-entity.model.ts", output.Location())
+	assert.Equal(t, "some-entity.model.ts", output.Name())
+
+	_, err = memoryOutputs.CreateModelOutput(types.EntitySpec{Name: "../SomeEntity"})
+	assert.Error(t, err)
+}
+
+func TestMemoryCompilerOutputsManager_ZeroValue_CommitsOutputs(t *testing.T) {
+	var memoryOutputs MemoryCompilerOutputsManager
+
+	output, err := memoryOutputs.CreateAuxiliaryOutput("index.ts")
+	assert.NoError(t, err)
+
+	_, err = output.Write([]byte("export {};"))
+	assert.NoError(t, err)
+	assert.NoError(t, output.Close())
+
+	assert.Equal(t, map[string][]byte{"index.ts": []byte("export {};")}, memoryOutputs.Files())
+}
+
+func TestMemoryCompilerOutputsManager_PrepareOutputDirectory_DiscardsPreviousOutputs(t *testing.T) {
+	memoryOutputs := NewMemoryCompilerOutputsManager()
+
+	output, err := memoryOutputs.CreateAuxiliaryOutput("stale.ts")
+	assert.NoError(t, err)
+	assert.NoError(t, output.Close())
+
+	assert.NoError(t, memoryOutputs.PrepareOutputDirectory(""))
+	assert.Empty(t, memoryOutputs.Files())
+}
+
+func TestMemoryCompilerOutputsManager_CreateModelOutput_RejectsOutputsSharingAPath(t *testing.T) {
+	memoryOutputs := NewMemoryCompilerOutputsManager()
+	memoryOutputs.Layout = Layout{Model: "models.ts"}
+
+	_, err := memoryOutputs.CreateModelOutput(types.EntitySpec{Name: "Person"})
+	assert.NoError(t, err)
+
+	_, err = memoryOutputs.CreateModelOutput(types.EntitySpec{Name: "Address"})
+	assert.ErrorContains(t, err, "model 'Person' and model 'Address' are both written to 'models.ts'")
+}
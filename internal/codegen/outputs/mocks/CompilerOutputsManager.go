@@ -13,6 +13,36 @@ type MockCompilerOutputsManager struct {
 	mock.Mock
 }
 
+// ComputeAuxiliaryLocation provides a mock function with given fields: name
+func (_m *MockCompilerOutputsManager) ComputeAuxiliaryLocation(name string) (outputs.CompilerOutputLocation, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ComputeAuxiliaryLocation")
+	}
+
+	var r0 outputs.CompilerOutputLocation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (outputs.CompilerOutputLocation, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) outputs.CompilerOutputLocation); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(outputs.CompilerOutputLocation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ComputeConfigLocation provides a mock function with given fields: config
 func (_m *MockCompilerOutputsManager) ComputeConfigLocation(config types.APIConfig) (outputs.CompilerOutputLocation, error) {
 	ret := _m.Called(config)
@@ -103,6 +133,36 @@ func (_m *MockCompilerOutputsManager) ComputeServiceLocation(serviceDef types.Se
 	return r0, r1
 }
 
+// CreateAuxiliaryOutput provides a mock function with given fields: name
+func (_m *MockCompilerOutputsManager) CreateAuxiliaryOutput(name string) (outputs.CompilerOutputWriter, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuxiliaryOutput")
+	}
+
+	var r0 outputs.CompilerOutputWriter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (outputs.CompilerOutputWriter, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) outputs.CompilerOutputWriter); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(outputs.CompilerOutputWriter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateConfigOutput provides a mock function with given fields: config
 func (_m *MockCompilerOutputsManager) CreateConfigOutput(config types.APIConfig) (outputs.CompilerOutputWriter, error) {
 	ret := _m.Called(config)
@@ -193,6 +253,24 @@ func (_m *MockCompilerOutputsManager) CreateServiceOutput(serviceDef types.Servi
 	return r0, r1
 }
 
+// FinalizeOutputs provides a mock function with given fields:
+func (_m *MockCompilerOutputsManager) FinalizeOutputs() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinalizeOutputs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PrepareOutputDirectory provides a mock function with given fields: path
 func (_m *MockCompilerOutputsManager) PrepareOutputDirectory(path string) error {
 	ret := _m.Called(path)
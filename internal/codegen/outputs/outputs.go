@@ -14,6 +14,12 @@ type CompilerOutputWriter interface {
 	CompilerOutputLocation
 }
 
+// DiscardableOutput is an output that buffers its contents until it is closed. Discarding it makes closing it commit
+// nothing, so that outputs whose generation failed never replace what was generated before
+type DiscardableOutput interface {
+	Discard()
+}
+
 //go:generate mockery --name CompilerOutputLocation --structname MockCompilerOutputLocation --outpkg outputsmocks
 type CompilerOutputLocation interface {
 	Name() string     // get the name of the output
@@ -32,4 +38,20 @@ type CompilerOutputsManager interface {
 	ComputeModelLocation(model types.EntitySpec) (CompilerOutputLocation, error)               // figure out where this entity will be located without actually creating the output
 	CreateConfigOutput(config types.APIConfig) (CompilerOutputWriter, error)                   // create a writer to write the API config
 	ComputeConfigLocation(config types.APIConfig) (CompilerOutputLocation, error)              // figure out where the API config will be located
+	CreateAuxiliaryOutput(name string) (CompilerOutputWriter, error)                           // create a writer for an output that is not tied to a service, entity or config
+	ComputeAuxiliaryLocation(name string) (CompilerOutputLocation, error)                      // figure out where the named auxiliary output will be located
+	FinalizeOutputs() error                                                                    // called once every output has been written and closed
+}
+
+// ExtensionSetter is implemented by outputs managers whose file extension can be chosen by the compiler. Targets that
+// do not generate TypeScript, such as docs, use it to name their outputs with their own extension
+type ExtensionSetter interface {
+	SetExtension(extension string)
+}
+
+// GeneratedOutput describes an output that was created during a compilation
+type GeneratedOutput struct {
+	Location CompilerOutputLocation // where the output is located
+	Type     string                 // the kind of output, one of the OutputType_ constants
+	Name     string                 // name of the service, entity, config, or auxiliary output this output contains
 }
@@ -0,0 +1,122 @@
+package outputs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checkName checks that a name taken from a specification can be placed into a path without reaching into other
+// directories
+func checkName(name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("name is empty")
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("name contains a path separator")
+	}
+
+	if strings.ContainsRune(name, 0) {
+		return fmt.Errorf("name contains a null character")
+	}
+
+	if name == "." || name == ".." {
+		return fmt.Errorf("name refers to a directory")
+	}
+
+	return nil
+}
+
+// resolvePath gets the absolute path of a path relative to the output directory. The path must stay inside of the
+// output directory, both lexically and once any symlinks along the way are followed
+func (outputs *DirectoryCompilerOutputsManager) resolvePath(relPath string) (string, error) {
+	if len(relPath) == 0 {
+		return "", fmt.Errorf("output path is empty")
+	}
+
+	if filepath.IsAbs(relPath) || len(filepath.VolumeName(relPath)) > 0 || strings.HasPrefix(relPath, `\`) {
+		return "", fmt.Errorf("output path '%s' must be relative to the output directory", relPath)
+	}
+
+	if strings.ContainsRune(relPath, 0) {
+		return "", fmt.Errorf("output path '%s' contains a null character", relPath)
+	}
+
+	baseAbsPath, err := filepath.Abs(outputs.BasePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path of output directory: %w", err)
+	}
+
+	absPath := filepath.Join(baseAbsPath, relPath)
+	if absPath == baseAbsPath {
+		return "", fmt.Errorf("output path '%s' is the output directory itself", relPath)
+	}
+
+	if !isWithin(baseAbsPath, absPath) {
+		return "", fmt.Errorf("output path '%s' escapes the output directory", relPath)
+	}
+
+	err = checkSymlinks(baseAbsPath, absPath)
+	if err != nil {
+		return "", fmt.Errorf("output path '%s' is unsafe: %w", relPath, err)
+	}
+
+	return absPath, nil
+}
+
+// checkSymlinks makes sure that following symlinks from the output directory to the given path does not leave the
+// output directory. The output directory itself may be a symlink. Parts of the path that do not exist yet are created
+// as regular files and directories, so only the deepest existing part of the path has to be checked
+func checkSymlinks(baseAbsPath, absPath string) error {
+	resolvedBase, err := filepath.EvalSymlinks(baseAbsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// nothing has been created yet, so there are no symlinks to follow
+			return nil
+		}
+
+		return fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+
+	existing := absPath
+	for existing != baseAbsPath {
+		_, err = os.Lstat(existing)
+		if err == nil {
+			break
+		}
+
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to stat '%s': %w", existing, err)
+		}
+
+		existing = filepath.Dir(existing)
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// a dangling symlink cannot be checked, so it cannot be trusted
+			return fmt.Errorf("'%s' is a symlink to a missing file", existing)
+		}
+
+		return fmt.Errorf("failed to resolve symlinks of '%s': %w", existing, err)
+	}
+
+	if !isWithin(resolvedBase, resolved) {
+		return fmt.Errorf("'%s' links outside of the output directory", existing)
+	}
+
+	return nil
+}
+
+// isWithin checks if a path is inside of a directory. Both paths must be absolute and clean
+func isWithin(dir, path string) bool {
+	relPath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return relPath == "." || (relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)))
+}
@@ -2,6 +2,7 @@ package servicegen
 
 import (
 	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
 	"github.com/softwaresale/client-gen/v2/internal/types"
 	"io"
 )
@@ -14,3 +15,21 @@ type ServiceGenerator interface {
 	GenerateEntity(writer io.Writer, entity types.EntitySpec, resolver imports.ImportManager) error
 	GenerateConfig(writer io.Writer, config types.APIConfig, resolver imports.ImportManager) error
 }
+
+// AuxiliaryGenerator is optionally implemented by a ServiceGenerator that produces outputs which are not tied to a
+// single service, entity or config, such as barrel files. Auxiliary outputs are generated after everything else, in
+// the order they are listed.
+//
+//go:generate mockery --name AuxiliaryGenerator --structname MockAuxiliaryGenerator --outpkg servicegenmocks
+type AuxiliaryGenerator interface {
+	AuxiliaryOutputs(api types.APIDefinition) []string                                                                                                   // names of the auxiliary outputs to generate
+	GenerateAuxiliary(writer io.Writer, name string, api types.APIDefinition, generated []outputs.GeneratedOutput, resolver imports.ImportManager) error // generated lists every output created so far
+}
+
+// APIPreparer is optionally implemented by a ServiceGenerator that needs to see the whole API definition before any
+// outputs are generated, such as to work out how entities reference each other.
+//
+//go:generate mockery --name APIPreparer --structname MockAPIPreparer --outpkg servicegenmocks
+type APIPreparer interface {
+	PrepareAPI(api types.APIDefinition) error // called once before any outputs are generated
+}
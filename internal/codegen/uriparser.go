@@ -17,7 +17,12 @@ type URITemplate struct {
 
 // FormatTemplate takes an API endpoint URI template and expands it into a template string
 func FormatTemplate(template URITemplate) (string, error) {
-	parser := regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+	if len(template.Template) == 0 {
+		return "", fmt.Errorf("URI template is empty")
+	}
+
+	// variables may be named anything that is not whitespace, as targets make them into valid identifiers
+	parser := regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*}}`)
 
 	for _, templateVar := range parser.FindAllStringSubmatch(template.Template, -1) {
 		wholeMatch := templateVar[0]
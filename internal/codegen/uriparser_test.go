@@ -33,6 +33,17 @@ func TestFormatTemplate_ShouldFailWhenMapperFails(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestFormatTemplate_ShouldFailForEmptyTemplates(t *testing.T) {
+	tmpl := URITemplate{
+		Template:  "",
+		VarMapper: func(variable string) (string, error) { return variable, nil },
+		Prefix:    "/prefix",
+	}
+
+	_, err := FormatTemplate(tmpl)
+	assert.ErrorContains(t, err, "URI template is empty")
+}
+
 func TestFormatTemplate_ShouldPrependPrefix(t *testing.T) {
 	tmpl := URITemplate{
 		Template:  "/hello/{{world}}",
@@ -84,3 +95,15 @@ func TestFormatTemplate_ShouldPrependPrefix_AccountingForSlash3(t *testing.T) {
 
 	assert.Equal(t, "/prefix/hello/world", formatted)
 }
+
+func TestFormatTemplate_AllowsAnyVariableName(t *testing.T) {
+	tmpl := URITemplate{
+		Template:  "/hello/{{ user-id }}",
+		VarMapper: func(variable string) (string, error) { return fmt.Sprintf("<%s>", variable), nil },
+	}
+
+	formatted, err := FormatTemplate(tmpl)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/hello/<user-id>", formatted)
+}
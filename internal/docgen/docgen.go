@@ -0,0 +1,176 @@
+package docgen
+
+import (
+	"embed"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+// DocsFormat is the format that API reference documentation is generated in
+type DocsFormat string
+
+const (
+	FormatMarkdown DocsFormat = "markdown"
+	FormatHTML     DocsFormat = "html"
+)
+
+// Extension gets the file extension of documents in this format
+func (format DocsFormat) Extension() string {
+	if format == FormatHTML {
+		return "html"
+	}
+
+	return "md"
+}
+
+// templateExecutor is implemented by both text and html templates
+type templateExecutor interface {
+	Execute(writer io.Writer, data any) error
+}
+
+// DocsGenerator generates an API reference with one page per service, entity, and the API configuration
+type DocsGenerator struct {
+	format          DocsFormat
+	formatter       typeFormatter
+	config          types.APIConfig      // the API configuration. Captured when the config page is generated
+	naming          types.NamingStrategy // how property names are written on the wire
+	serviceTemplate templateExecutor
+	entityTemplate  templateExecutor
+	configTemplate  templateExecutor
+	indexTemplate   templateExecutor
+	indexOutputName string
+}
+
+// NewDocsGenerator creates a documentation generator for the given format
+func NewDocsGenerator(format DocsFormat) *DocsGenerator {
+	generator := &DocsGenerator{
+		format: format,
+	}
+
+	switch format {
+	case FormatHTML:
+		generator.formatter = htmlTypeFormatter()
+		generator.indexOutputName = "index.html"
+		funcMap := htmltemplate.FuncMap{
+			"raw":  func(text string) htmltemplate.HTML { return htmltemplate.HTML(text) },
+			"json": formatExample,
+		}
+
+		parse := func(name string) templateExecutor {
+			return htmltemplate.Must(htmltemplate.New(name).Funcs(funcMap).ParseFS(templateFiles, "templates/html-common.tmpl", fmt.Sprintf("templates/%s", name)))
+		}
+
+		generator.serviceTemplate = parse("html-service.tmpl")
+		generator.entityTemplate = parse("html-entity.tmpl")
+		generator.configTemplate = parse("html-config.tmpl")
+		generator.indexTemplate = parse("html-index.tmpl")
+
+	default:
+		generator.format = FormatMarkdown
+		generator.formatter = markdownTypeFormatter()
+		generator.indexOutputName = "README.md"
+		funcMap := template.FuncMap{
+			"raw":  func(text string) string { return text },
+			"json": formatExample,
+			"cell": markdownCell,
+		}
+
+		parse := func(name string) templateExecutor {
+			return template.Must(template.New(name).Funcs(funcMap).ParseFS(templateFiles, "templates/md-common.tmpl", fmt.Sprintf("templates/%s", name)))
+		}
+
+		generator.serviceTemplate = parse("md-service.tmpl")
+		generator.entityTemplate = parse("md-entity.tmpl")
+		generator.configTemplate = parse("md-config.tmpl")
+		generator.indexTemplate = parse("md-index.tmpl")
+	}
+
+	return generator
+}
+
+// Format gets the format this generator produces
+func (generator *DocsGenerator) Format() DocsFormat {
+	return generator.format
+}
+
+// PrepareAPI captures how property names are written on the wire, as the reference documents the wire format
+func (generator *DocsGenerator) PrepareAPI(api types.APIDefinition) error {
+	if err := api.Naming.Validate(); err != nil {
+		return err
+	}
+
+	generator.naming = api.Naming
+	return nil
+}
+
+func (generator *DocsGenerator) GenerateService(writer io.Writer, service types.ServiceDefinition, resolver imports.ImportManager) error {
+	serviceView, err := generator.translateService(service, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to translate service: %w", err)
+	}
+
+	return generator.serviceTemplate.Execute(writer, serviceView)
+}
+
+func (generator *DocsGenerator) GenerateEntity(writer io.Writer, entity types.EntitySpec, resolver imports.ImportManager) error {
+	entityView, err := generator.translateEntity(entity, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to translate entity: %w", err)
+	}
+
+	return generator.entityTemplate.Execute(writer, entityView)
+}
+
+// GenerateConfig documents the API configuration. The compiler always generates the config before any services, so
+// this is also where we learn the base URL used in example requests
+func (generator *DocsGenerator) GenerateConfig(writer io.Writer, config types.APIConfig, resolver imports.ImportManager) error {
+	generator.config = config
+
+	configInit, err := config.ConfigEntityInitializer()
+	if err != nil {
+		return fmt.Errorf("failed to create config initializer: %w", err)
+	}
+
+	return generator.configTemplate.Execute(writer, translateConfig(configInit))
+}
+
+func (generator *DocsGenerator) AuxiliaryOutputs(api types.APIDefinition) []string {
+	return []string{generator.indexOutputName}
+}
+
+func (generator *DocsGenerator) GenerateAuxiliary(writer io.Writer, name string, api types.APIDefinition, generated []outputs.GeneratedOutput, resolver imports.ImportManager) error {
+	if name != generator.indexOutputName {
+		return fmt.Errorf("unknown auxiliary output '%s'", name)
+	}
+
+	return generator.indexTemplate.Execute(writer, translateIndex(api, generated, resolver))
+}
+
+// markdownCell formats documentation so that it fits into a single markdown table cell
+func markdownCell(doc types.Documentation) string {
+	var parts []string
+	if doc.Deprecated {
+		parts = append(parts, "**Deprecated.**")
+	}
+
+	if len(doc.Summary) > 0 {
+		parts = append(parts, doc.Summary)
+	}
+
+	if len(doc.Description) > 0 {
+		parts = append(parts, doc.Description)
+	}
+
+	cell := strings.Join(parts, " ")
+	cell = strings.ReplaceAll(cell, "\n", " ")
+	return strings.ReplaceAll(cell, "|", "\\|")
+}
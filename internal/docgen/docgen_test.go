@@ -0,0 +1,164 @@
+package docgen
+
+import (
+	"context"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func exampleAPI() types.APIDefinition {
+	return types.APIDefinition{
+		Name:    "people",
+		Version: "1.0.0",
+		Entities: []types.EntitySpec{
+			{
+				Name: "Person",
+				Properties: map[string]types.PropertySpec{
+					"name":    {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+					"friends": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}},
+				},
+			},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Name: "Person",
+				Endpoints: []types.APIEndpoint{
+					{
+						Documentation: types.Documentation{Summary: "Gets a person"},
+						Name:          "getById",
+						Endpoint:      "/people/{{id}}",
+						Method:        "get",
+						PathVariables: map[string]types.RequestValue{
+							"id": {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+						},
+						RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+						ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}},
+					},
+				},
+			},
+		},
+		Config: types.APIConfig{BaseURL: "https://api.example.com"},
+	}
+}
+
+func compileDocs(t *testing.T, format DocsFormat) map[string][]byte {
+	memoryOutputs := outputs.NewMemoryCompilerOutputsManager()
+
+	compiler := NewDocsCompiler(memoryOutputs, "", format)
+	assert.NoError(t, compiler.Compile(context.Background(), exampleAPI()))
+
+	return memoryOutputs.Files()
+}
+
+func TestDocsGenerator_Markdown_DocumentsServices(t *testing.T) {
+	files := compileDocs(t, FormatMarkdown)
+
+	service := string(files["person.service.gen.md"])
+	assert.Contains(t, service, "## getById")
+	assert.Contains(t, service, "`GET /people/{id}`")
+	assert.Contains(t, service, "Gets a person")
+	assert.Contains(t, service, "[Person](./person.model.gen.md)")
+	assert.Contains(t, service, "curl -X GET 'https://api.example.com/people/{id}'")
+}
+
+func TestDocsGenerator_Markdown_CrossLinksEntities(t *testing.T) {
+	files := compileDocs(t, FormatMarkdown)
+
+	entity := string(files["person.model.gen.md"])
+	assert.Contains(t, entity, "| `friends` | [Person](./person.model.gen.md)\\[\\] | no |")
+}
+
+func TestDocsGenerator_Markdown_GeneratesIndex(t *testing.T) {
+	files := compileDocs(t, FormatMarkdown)
+
+	index := string(files["README.md"])
+	assert.Contains(t, index, "# people (1.0.0)")
+	assert.Contains(t, index, "- [Person](./person.service.gen.md)")
+	assert.Contains(t, index, "- [Person](./person.model.gen.md)")
+}
+
+func TestDocsGenerator_HTML_GeneratesSite(t *testing.T) {
+	files := compileDocs(t, FormatHTML)
+
+	assert.Contains(t, files, "index.html")
+	service := string(files["person.service.gen.html"])
+	assert.Contains(t, service, `<a href="./person.model.gen.html">Person</a>`)
+	assert.Contains(t, service, "<code>GET /people/{id}</code>")
+}
+
+func TestTypeFormatter_Format_EscapesGenerics(t *testing.T) {
+	dtype := types.DynamicType{
+		TypeID:    types.TypeID_GENERIC,
+		Reference: "Page",
+		Inner:     []types.DynamicType{{TypeID: types.TypeID_STRING}},
+	}
+
+	formatted, err := htmlTypeFormatter().Format(dtype, NewLinkManager())
+	assert.NoError(t, err)
+	assert.Equal(t, "Page&lt;string&gt;", formatted)
+}
+
+func TestTypeFormatter_Format_FailsForArraysWithoutElementTypes(t *testing.T) {
+	_, err := markdownTypeFormatter().Format(types.DynamicType{TypeID: types.TypeID_ARRAY}, NewLinkManager())
+	assert.ErrorContains(t, err, "array type does not have an element type")
+}
+
+func TestDocsCompiler_Compile_FailsForArraysWithoutElementTypes(t *testing.T) {
+	apiDef := exampleAPI()
+	apiDef.Entities[0].Properties["friends"] = types.PropertySpec{Type: types.DynamicType{TypeID: types.TypeID_ARRAY}}
+
+	compiler := NewDocsCompiler(outputs.NewMemoryCompilerOutputsManager(), "", FormatMarkdown)
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "array type does not have an element type")
+}
+
+func TestDocsCompiler_Compile_FailsForEmptyEndpoints(t *testing.T) {
+	apiDef := exampleAPI()
+	apiDef.Services[0].Endpoints[0].Endpoint = ""
+	apiDef.Services[0].Endpoints[0].PathVariables = nil
+
+	compiler := NewDocsCompiler(outputs.NewMemoryCompilerOutputsManager(), "", FormatMarkdown)
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "failed to translate endpoint 'getById'")
+	assert.ErrorContains(t, err, "URI template is empty")
+}
+
+func TestDocsGenerator_Markdown_DocumentsWireNames(t *testing.T) {
+	apiDef := exampleAPI()
+	apiDef.Naming = types.NamingStrategy_SNAKE
+	apiDef.Entities[0].Properties["firstName"] = types.PropertySpec{Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true}
+	apiDef.Entities[0].Properties["lastName"] = types.PropertySpec{Type: types.DynamicType{TypeID: types.TypeID_STRING}, WireName: "surname"}
+	apiDef.Services[0].Endpoints[0].QueryVariables = map[string]types.RequestValue{
+		"pageSize": {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true},
+	}
+
+	memoryOutputs := outputs.NewMemoryCompilerOutputsManager()
+	compiler := NewDocsCompiler(memoryOutputs, "", FormatMarkdown)
+	assert.NoError(t, compiler.Compile(context.Background(), apiDef))
+	files := memoryOutputs.Files()
+
+	entity := string(files["person.model.gen.md"])
+	assert.Contains(t, entity, "| `first_name` |")
+	assert.Contains(t, entity, "| `surname` |")
+	assert.NotContains(t, entity, "firstName")
+	assert.NotContains(t, entity, "lastName")
+
+	service := string(files["person.service.gen.md"])
+	assert.Contains(t, service, "| `page_size` |")
+	assert.Contains(t, service, "| `id` |")
+	assert.Contains(t, service, "?page_size={page_size}'")
+	assert.NotContains(t, service, "`pageSize`")
+}
+
+func TestDocsCompiler_Compile_FailsForPropertiesSentWithTheSameName(t *testing.T) {
+	apiDef := exampleAPI()
+	apiDef.Naming = types.NamingStrategy_SNAKE
+	apiDef.Entities[0].Properties["first_name"] = types.PropertySpec{Type: types.DynamicType{TypeID: types.TypeID_STRING}}
+	apiDef.Entities[0].Properties["firstName"] = types.PropertySpec{Type: types.DynamicType{TypeID: types.TypeID_STRING}}
+
+	compiler := NewDocsCompiler(outputs.NewMemoryCompilerOutputsManager(), "", FormatMarkdown)
+	err := compiler.Compile(context.Background(), apiDef)
+	assert.ErrorContains(t, err, "properties 'firstName' and 'first_name' are both sent as 'first_name'")
+}
@@ -0,0 +1,22 @@
+package docgen
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/codegen"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+)
+
+// DocsTargetName is the name of the docs target, which keys docs-specific mappings in the specification
+const DocsTargetName = "docs"
+
+// NewDocsCompiler creates an API compiler that produces an API reference in the given format. Outputs managers that
+// support it are switched to the extension of the format
+func NewDocsCompiler(outputsManager outputs.CompilerOutputsManager, outputDirectory string, format DocsFormat) codegen.APICompiler {
+	return codegen.APICompiler{
+		Generator:      NewDocsGenerator(format),
+		ImportManager:  NewLinkManager(),
+		OutputsManager: outputsManager,
+		OutputPath:     outputDirectory,
+		Extension:      format.Extension(),
+		Target:         DocsTargetName,
+	}
+}
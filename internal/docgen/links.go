@@ -0,0 +1,76 @@
+package docgen
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+)
+
+// PageLink links to the page that documents a type
+type PageLink struct {
+	Page      string
+	TypeNames []string
+}
+
+func (link *PageLink) ProvidedEntities() []string {
+	return link.TypeNames
+}
+
+func (link *PageLink) Provider() string {
+	return link.Page
+}
+
+// LinkManager is an import manager that keeps track of which page documents each type. Pages never import each
+// other, so the only thing it is used for is cross-linking. Links are relative to the page being generated
+type LinkManager struct {
+	typePages map[string]string // type name -> location of the page that documents it
+	page      string            // location of the page being generated
+}
+
+func NewLinkManager() *LinkManager {
+	return &LinkManager{
+		typePages: make(map[string]string),
+	}
+}
+
+func (manager *LinkManager) RegisterProvider(providerName string) {}
+
+func (manager *LinkManager) RegisterType(providerName, typeName string) error {
+	if existing, exists := manager.typePages[typeName]; exists && existing != providerName {
+		return fmt.Errorf("type '%s' is documented by both '%s' and '%s'", typeName, existing, providerName)
+	}
+
+	manager.typePages[typeName] = providerName
+	return nil
+}
+
+// SetImporter sets the location of the page that links are created for
+func (manager *LinkManager) SetImporter(location string) {
+	manager.page = location
+}
+
+// ImportPath gets the relative link from the page being generated to the page at the given location
+func (manager *LinkManager) ImportPath(location string) string {
+	return outputs.RelativeLocation(manager.page, location)
+}
+
+func (manager *LinkManager) GetEntityImports(entity ...types.EntitySpec) []imports.GenericImport {
+	return nil
+}
+
+func (manager *LinkManager) GetServiceImports(service types.ServiceDefinition) []imports.GenericImport {
+	return nil
+}
+
+func (manager *LinkManager) GetImportForType(typeName string) (imports.GenericImport, error) {
+	page, exists := manager.typePages[typeName]
+	if !exists {
+		return nil, fmt.Errorf("type '%s' is not registered", typeName)
+	}
+
+	return &PageLink{
+		Page:      manager.ImportPath(page),
+		TypeNames: []string{typeName},
+	}, nil
+}
@@ -0,0 +1,392 @@
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// PropertyView documents a single property, path variable, or query variable
+type PropertyView struct {
+	Documentation types.Documentation
+	Name          string
+	Type          string // the type, already formatted for the output format
+	Required      bool
+}
+
+// EndpointView documents a single endpoint of a service
+type EndpointView struct {
+	Documentation  types.Documentation
+	Name           string
+	Method         string
+	Path           string // the URI template with path variables in {braces}
+	PathVariables  []PropertyView
+	QueryVariables []PropertyView
+	RequestBody    string // the formatted request body type. Empty if there is no body
+	ResponseBody   string // the formatted response body type
+	Curl           string // an example curl command
+}
+
+// ServiceView documents a service
+type ServiceView struct {
+	Documentation types.Documentation
+	Name          string
+	Endpoints     []EndpointView
+}
+
+// EntityView documents an entity
+type EntityView struct {
+	Documentation types.Documentation
+	Name          string
+	Properties    []PropertyView
+}
+
+// ConfigValueView documents a single configuration value
+type ConfigValueView struct {
+	Name  string
+	Value string
+}
+
+// LinkView links to another page
+type LinkView struct {
+	Text   string
+	Target string
+}
+
+// IndexView is the landing page of the reference
+type IndexView struct {
+	APIName  string
+	Version  string
+	Services []LinkView
+	Entities []LinkView
+	Config   *LinkView
+}
+
+// typeFormatter formats dynamic types for an output format
+type typeFormatter struct {
+	escape func(text string) string
+	link   func(text, target string) string
+}
+
+func markdownTypeFormatter() typeFormatter {
+	escaper := strings.NewReplacer("<", "&lt;", ">", "&gt;", "|", "\\|", "[", "\\[", "]", "\\]")
+	return typeFormatter{
+		escape: escaper.Replace,
+		link: func(text, target string) string {
+			return fmt.Sprintf("[%s](%s)", text, target)
+		},
+	}
+}
+
+func htmlTypeFormatter() typeFormatter {
+	return typeFormatter{
+		escape: html.EscapeString,
+		link: func(text, target string) string {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(target), text)
+		},
+	}
+}
+
+// Format formats the type, linking any referenced entities to their pages
+func (formatter typeFormatter) Format(dtype types.DynamicType, resolver imports.ImportManager) (string, error) {
+	switch dtype.TypeID {
+	case types.TypeID_VOID:
+		return formatter.escape("void"), nil
+	case types.TypeID_STRING:
+		return formatter.escape("string"), nil
+	case types.TypeID_INTEGER:
+		return formatter.escape("integer"), nil
+	case types.TypeID_FLOAT:
+		return formatter.escape("float"), nil
+	case types.TypeID_BOOLEAN:
+		return formatter.escape("boolean"), nil
+	case types.TypeID_TIMESTAMP:
+		return formatter.escape("timestamp"), nil
+	case types.TypeID_DATE:
+		return formatter.escape("date"), nil
+	case types.TypeID_ANY:
+		return formatter.escape("any"), nil
+	case types.TypeID_USER:
+		return formatter.reference(dtype.Reference, resolver), nil
+	case types.TypeID_ARRAY:
+		if len(dtype.Inner) == 0 {
+			return "", fmt.Errorf("array type does not have an element type")
+		}
+
+		inner, err := formatter.Format(dtype.ArrayElementTp(), resolver)
+		if err != nil {
+			return "", fmt.Errorf("failed to format array inner type: %w", err)
+		}
+
+		return inner + formatter.escape("[]"), nil
+	case types.TypeID_GENERIC:
+		var params []string
+		for genericIdx, inner := range dtype.Inner {
+			param, err := formatter.Format(inner, resolver)
+			if err != nil {
+				return "", fmt.Errorf("failed to format generic inner type at index %d: %w", genericIdx, err)
+			}
+
+			params = append(params, param)
+		}
+
+		return formatter.reference(dtype.Reference, resolver) + formatter.escape("<") + strings.Join(params, ", ") + formatter.escape(">"), nil
+	default:
+		return "", fmt.Errorf("unknown type ID %s", dtype.TypeID)
+	}
+}
+
+// reference formats a reference to a user type, linking it if we know which page documents it
+func (formatter typeFormatter) reference(typeName string, resolver imports.ImportManager) string {
+	imp, err := resolver.GetImportForType(typeName)
+	if err != nil {
+		return formatter.escape(typeName)
+	}
+
+	return formatter.link(formatter.escape(typeName), imp.Provider())
+}
+
+func (generator *DocsGenerator) translateService(service types.ServiceDefinition, resolver imports.ImportManager) (ServiceView, error) {
+	serviceView := ServiceView{
+		Documentation: service.Documentation,
+		Name:          service.Name,
+	}
+
+	for _, endpoint := range service.Endpoints {
+		endpointView, err := generator.translateEndpoint(endpoint, resolver)
+		if err != nil {
+			return ServiceView{}, fmt.Errorf("failed to translate endpoint '%s': %w", endpoint.Name, err)
+		}
+
+		serviceView.Endpoints = append(serviceView.Endpoints, endpointView)
+	}
+
+	return serviceView, nil
+}
+
+func (generator *DocsGenerator) translateEndpoint(endpoint types.APIEndpoint, resolver imports.ImportManager) (EndpointView, error) {
+	path, err := codegen.FormatTemplate(codegen.URITemplate{
+		Template: endpoint.Endpoint,
+		VarMapper: func(pathVar string) (string, error) {
+			return fmt.Sprintf("{%s}", pathVar), nil
+		},
+	})
+	if err != nil {
+		return EndpointView{}, fmt.Errorf("failed to format endpoint path: %w", err)
+	}
+
+	// path variables are substituted into the path, so their names are never sent
+	pathVariables, err := generator.translateRequestValues(endpoint.PathVariables, types.NamingStrategy_PRESERVE, resolver)
+	if err != nil {
+		return EndpointView{}, fmt.Errorf("failed to translate path variables: %w", err)
+	}
+
+	queryVariables, err := generator.translateRequestValues(endpoint.QueryVariables, generator.naming, resolver)
+	if err != nil {
+		return EndpointView{}, fmt.Errorf("failed to translate query variables: %w", err)
+	}
+
+	requestBody := ""
+	if !endpoint.RequestBody.Type.IsVoid() {
+		requestBody, err = generator.formatter.Format(endpoint.RequestBody.Type, resolver)
+		if err != nil {
+			return EndpointView{}, fmt.Errorf("failed to format request body: %w", err)
+		}
+	}
+
+	responseBody, err := generator.formatter.Format(endpoint.ResponseBody.Type, resolver)
+	if err != nil {
+		return EndpointView{}, fmt.Errorf("failed to format response body: %w", err)
+	}
+
+	curl, err := generator.createCurlExample(endpoint)
+	if err != nil {
+		return EndpointView{}, fmt.Errorf("failed to create curl example: %w", err)
+	}
+
+	return EndpointView{
+		Documentation:  endpoint.Documentation,
+		Name:           endpoint.Name,
+		Method:         strings.ToUpper(endpoint.Method),
+		Path:           path,
+		PathVariables:  pathVariables,
+		QueryVariables: queryVariables,
+		RequestBody:    requestBody,
+		ResponseBody:   responseBody,
+		Curl:           curl,
+	}, nil
+}
+
+// translateRequestValues documents request values under the names they are sent with, using the given naming strategy
+func (generator *DocsGenerator) translateRequestValues(values map[string]types.RequestValue, naming types.NamingStrategy, resolver imports.ImportManager) ([]PropertyView, error) {
+	var views []PropertyView
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		value := values[name]
+		formattedType, err := generator.formatter.Format(value.Type, resolver)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format type of '%s': %w", name, err)
+		}
+
+		views = append(views, PropertyView{
+			Documentation: value.Documentation,
+			Name:          naming.Apply(name),
+			Type:          formattedType,
+			Required:      value.Required,
+		})
+	}
+
+	return views, nil
+}
+
+// createCurlExample creates an example curl command that calls the endpoint relative to the configured base URL
+func (generator *DocsGenerator) createCurlExample(endpoint types.APIEndpoint) (string, error) {
+	url, err := codegen.FormatTemplate(codegen.URITemplate{
+		Template: endpoint.Endpoint,
+		VarMapper: func(pathVar string) (string, error) {
+			if example, ok := endpoint.PathVariables[pathVar]; ok && example.Example != nil {
+				return fmt.Sprintf("%v", example.Example), nil
+			}
+
+			return fmt.Sprintf("{%s}", pathVar), nil
+		},
+		Prefix: generator.config.BaseURL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var query []string
+	for _, name := range slices.Sorted(maps.Keys(endpoint.QueryVariables)) {
+		value := endpoint.QueryVariables[name]
+		if !value.Required && value.Example == nil {
+			continue
+		}
+
+		wireName := generator.naming.Apply(name)
+		example := fmt.Sprintf("{%s}", wireName)
+		if value.Example != nil {
+			example = fmt.Sprintf("%v", value.Example)
+		}
+
+		query = append(query, fmt.Sprintf("%s=%s", wireName, example))
+	}
+
+	if len(query) > 0 {
+		url = fmt.Sprintf("%s?%s", url, strings.Join(query, "&"))
+	}
+
+	curl := fmt.Sprintf("curl -X %s '%s'", strings.ToUpper(endpoint.Method), url)
+	if !endpoint.RequestBody.Type.IsVoid() {
+		body := "{}"
+		if endpoint.RequestBody.Example != nil {
+			encoded, err := json.Marshal(endpoint.RequestBody.Example)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode request body example: %w", err)
+			}
+			body = string(encoded)
+		}
+
+		curl = fmt.Sprintf("%s \\\n  -H 'Content-Type: application/json' \\\n  -d '%s'", curl, strings.ReplaceAll(body, "'", `'\''`))
+	}
+
+	return curl, nil
+}
+
+func (generator *DocsGenerator) translateEntity(entity types.EntitySpec, resolver imports.ImportManager) (EntityView, error) {
+	entityView := EntityView{
+		Documentation: entity.Documentation,
+		Name:          entity.Name,
+	}
+
+	// properties are documented under the names they are sent with
+	wireNames := make(map[string]string, len(entity.Properties))
+	for _, name := range slices.Sorted(maps.Keys(entity.Properties)) {
+		wireName := entity.Properties[name].ResolveWireName(name, generator.naming)
+		if existing, exists := wireNames[wireName]; exists {
+			return EntityView{}, fmt.Errorf("properties '%s' and '%s' are both sent as '%s'", existing, name, wireName)
+		}
+		wireNames[wireName] = name
+	}
+
+	for _, wireName := range slices.Sorted(maps.Keys(wireNames)) {
+		name := wireNames[wireName]
+		prop := entity.Properties[name]
+		formattedType, err := generator.formatter.Format(prop.Type, resolver)
+		if err != nil {
+			return EntityView{}, fmt.Errorf("failed to format type of property '%s': %w", name, err)
+		}
+
+		entityView.Properties = append(entityView.Properties, PropertyView{
+			Documentation: prop.Documentation,
+			Name:          wireName,
+			Type:          formattedType,
+			Required:      prop.Required,
+		})
+	}
+
+	return entityView, nil
+}
+
+func translateConfig(configInit types.EntityInitializer) []ConfigValueView {
+	var values []ConfigValueView
+	for _, name := range slices.Sorted(maps.Keys(configInit.PropertyValues)) {
+		values = append(values, ConfigValueView{
+			Name:  name,
+			Value: fmt.Sprintf("%v", configInit.PropertyValues[name]),
+		})
+	}
+
+	return values
+}
+
+func translateIndex(api types.APIDefinition, generated []outputs.GeneratedOutput, resolver imports.ImportManager) IndexView {
+	index := IndexView{
+		APIName: api.Name,
+		Version: api.Version,
+	}
+
+	for _, output := range generated {
+		link := LinkView{
+			Text:   output.Name,
+			Target: pageLink(resolver, output.Location.Location()),
+		}
+
+		switch output.Type {
+		case outputs.OutputType_SERVICE:
+			index.Services = append(index.Services, link)
+		case outputs.OutputType_MODEL:
+			index.Entities = append(index.Entities, link)
+		case outputs.OutputType_CONFIG:
+			index.Config = &link
+		}
+	}
+
+	return index
+}
+
+// pageLink gets the link from the page being generated to the page at the given location. If the resolver does not
+// know where pages are located, the link is relative to the root of the docs
+func pageLink(resolver imports.ImportManager, location string) string {
+	if located, ok := resolver.(imports.LocatedImportManager); ok {
+		return located.ImportPath(location)
+	}
+
+	return outputs.RelativeLocation("", location)
+}
+
+// formatExample pretty-prints an example value as JSON
+func formatExample(example any) (string, error) {
+	encoded, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to format example: %w", err)
+	}
+
+	return string(encoded), nil
+}
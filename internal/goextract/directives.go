@@ -0,0 +1,67 @@
+package goextract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// directivePrefix starts every directive. Like "//go:" directives, there is no space after the slashes, so directives
+// are left out of doc comments
+const directivePrefix = "//clientgen:"
+
+const (
+	directive_ENTITY   = "entity"   // marks a struct as an entity
+	directive_ENDPOINT = "endpoint" // marks a function as an endpoint, such as "//clientgen:endpoint GET /people/{id}"
+	directive_SERVICE  = "service"  // names the service of an endpoint. Defaults to the name of the package
+	directive_REQUEST  = "request"  // sets the request body type of an endpoint, such as "//clientgen:request Person"
+	directive_RESPONSE = "response" // sets the response body type of an endpoint, such as "//clientgen:response []Person"
+	directive_PATH     = "path"     // types a path variable of an endpoint, such as "//clientgen:path id int"
+	directive_QUERY    = "query"    // adds a query variable to an endpoint, such as "//clientgen:query limit int required"
+)
+
+// directives lists every known directive
+var directives = []string{directive_ENTITY, directive_ENDPOINT, directive_SERVICE, directive_REQUEST, directive_RESPONSE, directive_PATH, directive_QUERY}
+
+// directive is a "//clientgen:" comment, split into its name and arguments
+type directive struct {
+	pos  token.Pos // where the directive is written
+	name string    // name of the directive, such as "endpoint"
+	args []string  // arguments that follow the name, separated by spaces
+}
+
+// parseDirectives finds the directives of a doc comment
+func parseDirectives(fset *token.FileSet, doc *ast.CommentGroup) ([]directive, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	var dirs []directive
+	for _, comment := range doc.List {
+		text, isDirective := strings.CutPrefix(comment.Text, directivePrefix)
+		if !isDirective {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 || !slices.Contains(directives, fields[0]) {
+			return nil, fmt.Errorf("%s: unknown directive '%s'. Options are %v", fset.Position(comment.Pos()), comment.Text, directives)
+		}
+
+		dirs = append(dirs, directive{pos: comment.Pos(), name: fields[0], args: fields[1:]})
+	}
+
+	return dirs, nil
+}
+
+// findDirective finds the first directive with the given name
+func findDirective(dirs []directive, name string) (directive, bool) {
+	idx := slices.IndexFunc(dirs, func(dir directive) bool { return dir.name == name })
+	if idx < 0 {
+		return directive{}, false
+	}
+
+	return dirs[idx], true
+}
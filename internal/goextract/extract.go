@@ -0,0 +1,422 @@
+// Package goextract derives API specifications from Go source code. Packages are loaded and type checked, but never
+// run, so the specification always matches the code that serves the API.
+package goextract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"golang.org/x/tools/go/packages"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// loadMode loads everything needed to type check packages and read their comments
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo
+
+// Options configures an extraction
+type Options struct {
+	Dir     string // directory that package patterns are relative to. Defaults to the working directory
+	Name    string // name of the API. Defaults to the name of the first package
+	Version string // version of the API
+	BaseURL string // base URL of the API
+}
+
+// pathVariable matches ServeMux style path variables, such as "{id}" or "{path...}"
+var pathVariable = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(\.\.\.)?}`)
+
+// extractor collects the entities and services declared across the loaded packages
+type extractor struct {
+	fset     *token.FileSet
+	mapper   *typeMapper
+	services []*types.ServiceDefinition
+}
+
+// Extract loads the Go packages matching the given patterns, such as "./...", and derives an API definition from them.
+// Exported structs marked with "//clientgen:entity" become entities, along with every struct they reference. Functions
+// marked with "//clientgen:endpoint METHOD /path" become endpoints of a service, whose request and response types come
+// from the function's signature or from further directives
+func Extract(ctx context.Context, patterns []string, opts Options) (types.APIDefinition, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	fset := token.NewFileSet()
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    loadMode,
+		Dir:     opts.Dir,
+		Fset:    fset,
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return types.APIDefinition{}, fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	err = packageErrors(pkgs)
+	if err != nil {
+		return types.APIDefinition{}, err
+	}
+
+	if len(pkgs) == 0 {
+		return types.APIDefinition{}, fmt.Errorf("no packages match %v", patterns)
+	}
+
+	ext := &extractor{
+		fset:   fset,
+		mapper: newTypeMapper(fset),
+	}
+
+	for _, pkg := range pkgs {
+		ext.mapper.addDocs(pkg.Syntax)
+	}
+
+	for _, pkg := range pkgs {
+		err = ext.extractPackage(pkg)
+		if err != nil {
+			return types.APIDefinition{}, err
+		}
+	}
+
+	entities, err := ext.mapper.entities()
+	if err != nil {
+		return types.APIDefinition{}, err
+	}
+
+	apiDef := types.APIDefinition{
+		Name:     opts.Name,
+		Version:  opts.Version,
+		Entities: entities,
+		Config: types.APIConfig{
+			BaseURL: opts.BaseURL,
+		},
+	}
+	if len(apiDef.Name) == 0 {
+		apiDef.Name = pkgs[0].Name
+	}
+
+	for _, service := range ext.services {
+		apiDef.Services = append(apiDef.Services, *service)
+	}
+
+	return apiDef, nil
+}
+
+// packageErrors combines the errors of packages that failed to load or type check
+func packageErrors(pkgs []*packages.Package) error {
+	var errs []error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, pkgErr := range pkg.Errors {
+			errs = append(errs, pkgErr)
+		}
+	})
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to load packages: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// extractPackage extracts the marked declarations of a package, in the order they appear
+func (ext *extractor) extractPackage(pkg *packages.Package) error {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			var err error
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				err = ext.extractTypes(pkg, decl)
+			case *ast.FuncDecl:
+				err = ext.extractEndpoint(pkg, decl)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// extractTypes registers every struct of a type declaration that is marked as an entity
+func (ext *extractor) extractTypes(pkg *packages.Package, decl *ast.GenDecl) error {
+	if decl.Tok != token.TYPE {
+		return nil
+	}
+
+	for _, spec := range decl.Specs {
+		typeSpec := spec.(*ast.TypeSpec)
+		doc := typeSpec.Doc
+		if doc == nil && len(decl.Specs) == 1 {
+			doc = decl.Doc
+		}
+
+		dirs, err := parseDirectives(ext.fset, doc)
+		if err != nil {
+			return err
+		}
+
+		if len(dirs) == 0 {
+			continue
+		}
+
+		for _, dir := range dirs {
+			if dir.name != directive_ENTITY {
+				return ext.errorAt(dir.pos, "//clientgen:%s cannot be used on types", dir.name)
+			}
+		}
+
+		obj, isTypeName := pkg.TypesInfo.Defs[typeSpec.Name].(*gotypes.TypeName)
+		if !isTypeName || !obj.Exported() {
+			return ext.errorAt(typeSpec.Pos(), "entity '%s' must be an exported struct", typeSpec.Name.Name)
+		}
+
+		named, isNamed := obj.Type().(*gotypes.Named)
+		if !isNamed {
+			return ext.errorAt(typeSpec.Pos(), "entity '%s' must be an exported struct", typeSpec.Name.Name)
+		}
+
+		if _, isStruct := named.Underlying().(*gotypes.Struct); !isStruct {
+			return ext.errorAt(typeSpec.Pos(), "entity '%s' must be an exported struct", typeSpec.Name.Name)
+		}
+
+		_, err = ext.mapper.Convert(named)
+		if err != nil {
+			return ext.errorAt(typeSpec.Pos(), "%w", err)
+		}
+	}
+
+	return nil
+}
+
+// extractEndpoint adds a function that is marked as an endpoint to its service
+func (ext *extractor) extractEndpoint(pkg *packages.Package, decl *ast.FuncDecl) error {
+	dirs, err := parseDirectives(ext.fset, decl.Doc)
+	if err != nil {
+		return err
+	}
+
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	endpointDir, found := findDirective(dirs, directive_ENDPOINT)
+	if !found {
+		return ext.errorAt(dirs[0].pos, "//clientgen:%s can only be used with //clientgen:endpoint", dirs[0].name)
+	}
+
+	if len(endpointDir.args) != 2 {
+		return ext.errorAt(endpointDir.pos, "//clientgen:endpoint expects a method and a path, such as 'GET /people/{id}'")
+	}
+
+	method := strings.ToUpper(endpointDir.args[0])
+	endpoint := types.APIEndpoint{
+		Documentation:  types.Documentation{Description: strings.TrimSpace(decl.Doc.Text())},
+		Name:           strcase.ToLowerCamel(decl.Name.Name),
+		Method:         method,
+		Endpoint:       pathVariable.ReplaceAllString(endpointDir.args[1], "{{$1}}"),
+		PathVariables:  make(map[string]types.RequestValue),
+		QueryVariables: make(map[string]types.RequestValue),
+		RequestBody:    types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+		ResponseBody:   types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+	}
+
+	for _, match := range pathVariable.FindAllStringSubmatch(endpointDir.args[1], -1) {
+		endpoint.PathVariables[match[1]] = types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true}
+	}
+
+	err = ext.applySignature(pkg, decl, &endpoint)
+	if err != nil {
+		return err
+	}
+
+	serviceName := strcase.ToCamel(pkg.Name)
+	for _, dir := range dirs {
+		switch dir.name {
+		case directive_ENDPOINT:
+			continue
+
+		case directive_SERVICE:
+			if len(dir.args) != 1 {
+				return ext.errorAt(dir.pos, "//clientgen:service expects a service name")
+			}
+			serviceName = dir.args[0]
+
+		case directive_REQUEST, directive_RESPONSE:
+			if len(dir.args) == 0 {
+				return ext.errorAt(dir.pos, "//clientgen:%s expects a type", dir.name)
+			}
+
+			value, err := ext.evalType(pkg, decl, dir, strings.Join(dir.args, " "))
+			if err != nil {
+				return err
+			}
+
+			if dir.name == directive_REQUEST {
+				endpoint.RequestBody = value
+			} else {
+				endpoint.ResponseBody = value
+			}
+
+		case directive_PATH, directive_QUERY:
+			if len(dir.args) < 2 {
+				return ext.errorAt(dir.pos, "//clientgen:%s expects a name and a type, such as 'id int'", dir.name)
+			}
+
+			required := dir.name == directive_PATH
+			typeArgs := dir.args[1:]
+			if dir.name == directive_QUERY && typeArgs[len(typeArgs)-1] == "required" {
+				required = true
+				typeArgs = typeArgs[:len(typeArgs)-1]
+			}
+
+			value, err := ext.evalType(pkg, decl, dir, strings.Join(typeArgs, " "))
+			if err != nil {
+				return err
+			}
+			value.Required = required
+
+			if dir.name == directive_PATH {
+				if _, exists := endpoint.PathVariables[dir.args[0]]; !exists {
+					return ext.errorAt(dir.pos, "path '%s' has no variable '%s'", endpointDir.args[1], dir.args[0])
+				}
+				endpoint.PathVariables[dir.args[0]] = value
+			} else {
+				endpoint.QueryVariables[dir.args[0]] = value
+			}
+
+		default:
+			return ext.errorAt(dir.pos, "//clientgen:%s cannot be used on functions", dir.name)
+		}
+	}
+
+	ext.service(serviceName).Endpoints = append(ext.service(serviceName).Endpoints, endpoint)
+	return nil
+}
+
+// applySignature derives the types of an endpoint from the signature of its handler. Contexts, response writers, and
+// requests are skipped. Parameters named after path variables type those variables, and a remaining parameter is the
+// request body. The first result that is not an error is the response body
+func (ext *extractor) applySignature(pkg *packages.Package, decl *ast.FuncDecl, endpoint *types.APIEndpoint) error {
+	signature := pkg.TypesInfo.Defs[decl.Name].Type().(*gotypes.Signature)
+
+	isHTTPHandler := false
+	for idx := 0; idx < signature.Params().Len(); idx++ {
+		param := signature.Params().At(idx)
+		if isFrameworkParam(param.Type()) {
+			isHTTPHandler = isHTTPHandler || !isNamedType(param.Type(), "context", "Context")
+			continue
+		}
+
+		dtype, err := ext.mapper.Convert(param.Type())
+		if err != nil {
+			return ext.errorAt(param.Pos(), "parameter '%s' of '%s': %w", param.Name(), decl.Name.Name, err)
+		}
+
+		if _, isPathVariable := endpoint.PathVariables[param.Name()]; isPathVariable {
+			endpoint.PathVariables[param.Name()] = types.RequestValue{Type: dtype, Required: true}
+			continue
+		}
+
+		if !hasBody(endpoint.Method) || !endpoint.RequestBody.Type.IsVoid() {
+			return ext.errorAt(param.Pos(), "parameter '%s' of '%s' is not a path variable or request body. Describe query parameters with //clientgen:query", param.Name(), decl.Name.Name)
+		}
+
+		endpoint.RequestBody = types.RequestValue{Type: dtype, Required: true}
+	}
+
+	if isHTTPHandler {
+		return nil
+	}
+
+	for idx := 0; idx < signature.Results().Len(); idx++ {
+		result := signature.Results().At(idx)
+		if isNamedType(result.Type(), "", "error") {
+			continue
+		}
+
+		dtype, err := ext.mapper.Convert(result.Type())
+		if err != nil {
+			return ext.errorAt(result.Pos(), "result of '%s': %w", decl.Name.Name, err)
+		}
+
+		endpoint.ResponseBody = types.RequestValue{Type: dtype, Required: true}
+		break
+	}
+
+	return nil
+}
+
+// evalType evaluates a Go type expression of a directive, such as "[]Person", in the scope of a handler
+func (ext *extractor) evalType(pkg *packages.Package, decl *ast.FuncDecl, dir directive, expr string) (types.RequestValue, error) {
+	typeAndValue, err := gotypes.Eval(ext.fset, pkg.Types, decl.Pos(), expr)
+	if err != nil {
+		return types.RequestValue{}, ext.errorAt(dir.pos, "invalid type '%s': %w", expr, err)
+	}
+
+	if !typeAndValue.IsType() {
+		return types.RequestValue{}, ext.errorAt(dir.pos, "'%s' is not a type", expr)
+	}
+
+	dtype, err := ext.mapper.Convert(typeAndValue.Type)
+	if err != nil {
+		return types.RequestValue{}, ext.errorAt(dir.pos, "%w", err)
+	}
+
+	return types.RequestValue{Type: dtype, Required: true}, nil
+}
+
+// service gets the service with the given name, creating it the first time it is used
+func (ext *extractor) service(name string) *types.ServiceDefinition {
+	for _, service := range ext.services {
+		if service.Name == name {
+			return service
+		}
+	}
+
+	service := &types.ServiceDefinition{Name: name}
+	ext.services = append(ext.services, service)
+	return service
+}
+
+// errorAt creates an error that points at a position in the source code
+func (ext *extractor) errorAt(pos token.Pos, format string, args ...any) error {
+	return fmt.Errorf("%s: %w", ext.fset.Position(pos), fmt.Errorf(format, args...))
+}
+
+// isFrameworkParam checks if a parameter is provided by the HTTP framework rather than the client
+func isFrameworkParam(tp gotypes.Type) bool {
+	if pointer, isPointer := tp.(*gotypes.Pointer); isPointer {
+		return isNamedType(pointer.Elem(), "net/http", "Request")
+	}
+
+	return isNamedType(tp, "context", "Context") || isNamedType(tp, "net/http", "ResponseWriter")
+}
+
+// isNamedType checks if a type is the named type declared in the given package. Universe types have an empty package
+func isNamedType(tp gotypes.Type, pkgPath, name string) bool {
+	named, isNamed := gotypes.Unalias(tp).(*gotypes.Named)
+	if !isNamed || named.Obj().Name() != name {
+		return false
+	}
+
+	if named.Obj().Pkg() == nil {
+		return len(pkgPath) == 0
+	}
+
+	return named.Obj().Pkg().Path() == pkgPath
+}
+
+// hasBody checks if requests with the given method carry a body
+func hasBody(method string) bool {
+	return slices.Contains([]string{http.MethodPost, http.MethodPut, http.MethodPatch}, method)
+}
@@ -0,0 +1,153 @@
+package goextract
+
+import (
+	"context"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtract_DerivesEntitiesAndEndpoints(t *testing.T) {
+	apiDef, err := Extract(context.Background(), nil, Options{Dir: filepath.Join("testdata", "people"), BaseURL: "http://localhost"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "people", apiDef.Name)
+	assert.Equal(t, "http://localhost", apiDef.Config.BaseURL)
+
+	assert.Len(t, apiDef.Entities, 2)
+	person := apiDef.Entities[0]
+	assert.Equal(t, "Person", person.Name)
+	assert.Equal(t, "Person is somebody we know", person.Description)
+	assert.Equal(t, "Address", apiDef.Entities[1].Name)
+
+	expectedTypes := map[string]string{
+		"id":        types.TypeID_INTEGER,
+		"name":      types.TypeID_STRING,
+		"nickname":  types.TypeID_STRING,
+		"email":     types.TypeID_STRING,
+		"address":   types.TypeID_USER,
+		"friends":   types.TypeID_ARRAY,
+		"tags":      types.TypeID_GENERIC,
+		"avatar":    types.TypeID_STRING,
+		"score":     types.TypeID_STRING,
+		"status":    types.TypeID_STRING,
+		"extra":     types.TypeID_ANY,
+		"createdAt": types.TypeID_TIMESTAMP,
+	}
+	assert.Len(t, person.Properties, len(expectedTypes))
+	for name, typeID := range expectedTypes {
+		assert.Equal(t, typeID, person.Properties[name].Type.TypeID, name)
+	}
+
+	assert.True(t, person.Properties["name"].Required)
+	assert.Equal(t, "full name", person.Properties["name"].Description)
+	assert.False(t, person.Properties["nickname"].Required)
+	assert.False(t, person.Properties["email"].Required)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, person.Properties["friends"].Type.ArrayElementTp())
+
+	assert.Len(t, apiDef.Services, 2)
+	people := apiDef.Services[0]
+	assert.Equal(t, "People", people.Name)
+	assert.Len(t, people.Endpoints, 3)
+
+	getPerson := people.Endpoints[0]
+	assert.Equal(t, "getPerson", getPerson.Name)
+	assert.Equal(t, "GET", getPerson.Method)
+	assert.Equal(t, "/people/{{id}}", getPerson.Endpoint)
+	assert.Equal(t, "GetPerson gets a person by their ID", getPerson.Description)
+	assert.Equal(t, types.TypeID_INTEGER, getPerson.PathVariables["id"].Type.TypeID)
+	assert.True(t, getPerson.RequestBody.Type.IsVoid())
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, getPerson.ResponseBody.Type)
+
+	createPerson := people.Endpoints[1]
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, createPerson.RequestBody.Type)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, createPerson.ResponseBody.Type)
+
+	deletePerson := people.Endpoints[2]
+	assert.Equal(t, types.TypeID_INTEGER, deletePerson.PathVariables["id"].Type.TypeID)
+	assert.True(t, deletePerson.ResponseBody.Type.IsVoid())
+
+	directory := apiDef.Services[1]
+	assert.Equal(t, "Directory", directory.Name)
+	assert.Len(t, directory.Endpoints, 1)
+
+	listPeople := directory.Endpoints[0]
+	assert.Equal(t, types.TypeID_ARRAY, listPeople.ResponseBody.Type.TypeID)
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_INTEGER}}, listPeople.QueryVariables["limit"])
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true}, listPeople.QueryVariables["name"])
+}
+
+func TestExtract_ReportsInvalidAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		source   string
+		expected string
+	}{
+		"unknown directive": {
+			source:   "//clientgen:entiy\ntype Person struct{}\n",
+			expected: "api.go:3:1: unknown directive '//clientgen:entiy'",
+		},
+		"entity that is not a struct": {
+			source:   "//clientgen:entity\ntype Status string\n",
+			expected: "api.go:4:6: entity 'Status' must be an exported struct",
+		},
+		"unsupported field type": {
+			source:   "//clientgen:entity\ntype Person struct {\n\tUpdates chan int\n}\n",
+			expected: "api.go:5:2: field 'Updates': unsupported type 'chan int'",
+		},
+		"endpoint without a path": {
+			source:   "//clientgen:endpoint GET\nfunc GetPerson() {}\n",
+			expected: "api.go:3:1: //clientgen:endpoint expects a method and a path",
+		},
+		"parameter that is not a path variable": {
+			source:   "//clientgen:endpoint GET /people\nfunc ListPeople(limit int) {}\n",
+			expected: "api.go:4:17: parameter 'limit' of 'ListPeople' is not a path variable or request body",
+		},
+		"unknown path variable": {
+			source:   "//clientgen:endpoint GET /people\n//clientgen:path id int\nfunc ListPeople() {}\n",
+			expected: "api.go:4:1: path '/people' has no variable 'id'",
+		},
+		"invalid type expression": {
+			source:   "//clientgen:endpoint GET /people\n//clientgen:response []Persn\nfunc ListPeople() {}\n",
+			expected: "api.go:4:1: invalid type '[]Persn'",
+		},
+		"directive without an endpoint": {
+			source:   "//clientgen:service People\nfunc ListPeople() {}\n",
+			expected: "api.go:3:1: //clientgen:service can only be used with //clientgen:endpoint",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeModule(t, "package api\n\n"+test.source)
+
+			_, err := Extract(context.Background(), []string{"."}, Options{Dir: dir})
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestExtract_RejectsEntitiesDeclaredTwice(t *testing.T) {
+	dir := writeModule(t, "package api\n\nimport \"example.com/api/other\"\n\n//clientgen:entity\ntype Person struct {\n\tOther other.Person\n}\n")
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "other"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other", "other.go"), []byte("package other\n\ntype Person struct{}\n"), 0644))
+
+	_, err := Extract(context.Background(), []string{"."}, Options{Dir: dir})
+	assert.ErrorContains(t, err, "entity 'Person' is declared by both 'example.com/api' and 'example.com/api/other'")
+}
+
+func TestExtract_ReportsTypeErrors(t *testing.T) {
+	dir := writeModule(t, "package api\n\nfunc Broken() int { return \"\" }\n")
+
+	_, err := Extract(context.Background(), []string{"."}, Options{Dir: dir})
+	assert.ErrorContains(t, err, "failed to load packages")
+}
+
+// writeModule writes a module with a single file into a temporary directory
+func writeModule(t *testing.T, source string) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/api\n\ngo 1.23\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "api.go"), []byte(source), 0644))
+	return dir
+}
@@ -0,0 +1,76 @@
+// Package people serves people
+package people
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Person is somebody we know
+//
+//clientgen:entity
+type Person struct {
+	Audit
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`     // full name
+	Nickname *string           `json:"nickname"` // optional nickname
+	Email    string            `json:"email,omitempty"`
+	Address  Address           `json:"address"`
+	Friends  []Person          `json:"friends"`
+	Tags     map[string]string `json:"tags"`
+	Avatar   []byte            `json:"avatar"`
+	Score    int64             `json:"score,string"`
+	Status   Status            `json:"status"`
+	Extra    any               `json:"extra"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+// Audit records changes
+type Audit struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Address is where somebody lives
+type Address struct {
+	Street string
+}
+
+// Status is how a person is doing
+type Status string
+
+// GetPerson gets a person by their ID
+//
+//clientgen:endpoint GET /people/{id}
+func GetPerson(ctx context.Context, id int) (Person, error) {
+	return Person{}, nil
+}
+
+// CreatePerson creates a person
+//
+//clientgen:endpoint POST /people
+func CreatePerson(ctx context.Context, person Person) (*Person, error) {
+	return &person, nil
+}
+
+// ListPeople lists people
+//
+//clientgen:endpoint GET /people
+//clientgen:service Directory
+//clientgen:response []Person
+//clientgen:query limit int
+//clientgen:query name string required
+func ListPeople(w http.ResponseWriter, r *http.Request) {
+}
+
+// DeletePerson deletes a person
+//
+//clientgen:endpoint DELETE /people/{id}
+//clientgen:path id int64
+func DeletePerson(w http.ResponseWriter, r *http.Request) {
+}
+
+// unrelated is not an endpoint
+func unrelated() {
+}
@@ -0,0 +1,268 @@
+package goextract
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// typeMapper maps Go types onto dynamic types, the same way encoding/json would write them. Every named struct that
+// is mapped becomes an entity, so that the specification declares every type it references
+type typeMapper struct {
+	fset    *token.FileSet
+	docs    map[token.Pos]string      // doc comments of types and fields, by the position of their names
+	structs map[string]*gotypes.Named // structs that become entities, by name
+	order   []string                  // names of the structs, in the order they were first mapped
+}
+
+func newTypeMapper(fset *token.FileSet) *typeMapper {
+	return &typeMapper{
+		fset:    fset,
+		docs:    make(map[token.Pos]string),
+		structs: make(map[string]*gotypes.Named),
+	}
+}
+
+// addDocs records the doc comments of every type and struct field in the given files
+func (mapper *typeMapper) addDocs(files []*ast.File) {
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.GenDecl:
+				if n.Tok == token.TYPE && len(n.Specs) == 1 && n.Doc != nil {
+					mapper.docs[n.Specs[0].(*ast.TypeSpec).Name.Pos()] = strings.TrimSpace(n.Doc.Text())
+				}
+
+			case *ast.TypeSpec:
+				if n.Doc != nil {
+					mapper.docs[n.Name.Pos()] = strings.TrimSpace(n.Doc.Text())
+				}
+
+			case *ast.Field:
+				doc := n.Doc
+				if doc == nil {
+					doc = n.Comment
+				}
+
+				if doc != nil {
+					for _, name := range n.Names {
+						mapper.docs[name.Pos()] = strings.TrimSpace(doc.Text())
+					}
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// Convert maps a Go type onto a dynamic type. Pointers are mapped onto the type they point at
+func (mapper *typeMapper) Convert(goType gotypes.Type) (types.DynamicType, error) {
+	switch tp := gotypes.Unalias(goType).(type) {
+	case *gotypes.Pointer:
+		return mapper.Convert(tp.Elem())
+
+	case *gotypes.Named:
+		return mapper.convertNamed(tp)
+
+	case *gotypes.Basic:
+		return convertBasic(tp)
+
+	case *gotypes.Slice:
+		return mapper.convertList(tp.Elem())
+
+	case *gotypes.Array:
+		return mapper.convertList(tp.Elem())
+
+	case *gotypes.Map:
+		key, isBasic := tp.Key().Underlying().(*gotypes.Basic)
+		if !isBasic || key.Info()&(gotypes.IsString|gotypes.IsInteger) == 0 {
+			return types.DynamicType{}, fmt.Errorf("unsupported map key type '%s'. Keys must be strings or integers", tp.Key())
+		}
+
+		valueType, err := mapper.Convert(tp.Elem())
+		if err != nil {
+			return types.DynamicType{}, fmt.Errorf("failed to map map value type '%s': %w", tp.Elem(), err)
+		}
+
+		return types.DynamicType{
+			TypeID:    types.TypeID_GENERIC,
+			Reference: "Record",
+			Inner:     []types.DynamicType{{TypeID: types.TypeID_STRING}, valueType},
+		}, nil
+
+	case *gotypes.Interface:
+		if !tp.Empty() {
+			return types.DynamicType{}, fmt.Errorf("unsupported type '%s'. Only empty interfaces are supported", tp)
+		}
+
+		return types.DynamicType{TypeID: types.TypeID_ANY}, nil
+
+	case *gotypes.Struct:
+		return types.DynamicType{}, fmt.Errorf("unsupported type '%s'. Anonymous structs must be named to become entities", tp)
+
+	default:
+		return types.DynamicType{}, fmt.Errorf("unsupported type '%s'", goType)
+	}
+}
+
+// convertNamed maps a named type. Structs are referenced as entities, while other named types are mapped onto their
+// underlying types
+func (mapper *typeMapper) convertNamed(named *gotypes.Named) (types.DynamicType, error) {
+	switch {
+	case isNamedType(named, "time", "Time"):
+		return types.DynamicType{TypeID: types.TypeID_TIMESTAMP}, nil
+	case isNamedType(named, "encoding/json", "RawMessage"):
+		return types.DynamicType{TypeID: types.TypeID_ANY}, nil
+	}
+
+	if _, isStruct := named.Underlying().(*gotypes.Struct); !isStruct {
+		return mapper.Convert(named.Underlying())
+	}
+
+	if named.TypeArgs().Len() > 0 || named.TypeParams().Len() > 0 {
+		return types.DynamicType{}, fmt.Errorf("unsupported type '%s'. Generic structs cannot become entities", named)
+	}
+
+	name := named.Obj().Name()
+	if existing, exists := mapper.structs[name]; exists && existing != named {
+		return types.DynamicType{}, fmt.Errorf("entity '%s' is declared by both '%s' and '%s'", name, existing.Obj().Pkg().Path(), named.Obj().Pkg().Path())
+	}
+
+	if _, exists := mapper.structs[name]; !exists {
+		mapper.structs[name] = named
+		mapper.order = append(mapper.order, name)
+	}
+
+	return types.DynamicType{TypeID: types.TypeID_USER, Reference: name}, nil
+}
+
+// convertList maps slices and arrays. Byte slices are written as base64 strings
+func (mapper *typeMapper) convertList(elem gotypes.Type) (types.DynamicType, error) {
+	if basic, isBasic := elem.(*gotypes.Basic); isBasic && basic.Kind() == gotypes.Byte {
+		return types.DynamicType{TypeID: types.TypeID_STRING}, nil
+	}
+
+	innerType, err := mapper.Convert(elem)
+	if err != nil {
+		return types.DynamicType{}, fmt.Errorf("failed to map slice element type '%s': %w", elem, err)
+	}
+
+	return types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{innerType}}, nil
+}
+
+// convertBasic maps strings, numbers, and booleans
+func convertBasic(basic *gotypes.Basic) (types.DynamicType, error) {
+	info := basic.Info()
+	switch {
+	case info&gotypes.IsString != 0:
+		return types.DynamicType{TypeID: types.TypeID_STRING}, nil
+	case info&gotypes.IsInteger != 0:
+		return types.DynamicType{TypeID: types.TypeID_INTEGER}, nil
+	case info&gotypes.IsFloat != 0:
+		return types.DynamicType{TypeID: types.TypeID_FLOAT}, nil
+	case info&gotypes.IsBoolean != 0:
+		return types.DynamicType{TypeID: types.TypeID_BOOLEAN}, nil
+	default:
+		return types.DynamicType{}, fmt.Errorf("unsupported type '%s'", basic)
+	}
+}
+
+// entities creates an entity for every struct that was mapped. Mapping the properties of an entity may reference
+// further structs, which are added as they are found
+func (mapper *typeMapper) entities() ([]types.EntitySpec, error) {
+	var entities []types.EntitySpec
+	for idx := 0; idx < len(mapper.order); idx++ {
+		named := mapper.structs[mapper.order[idx]]
+		entity := types.EntitySpec{
+			Documentation: types.Documentation{Description: mapper.docs[named.Obj().Pos()]},
+			Name:          named.Obj().Name(),
+			Properties:    make(map[string]types.PropertySpec),
+		}
+
+		err := mapper.addProperties(entity.Properties, named.Underlying().(*gotypes.Struct))
+		if err != nil {
+			return nil, fmt.Errorf("failed to map entity '%s': %w", entity.Name, err)
+		}
+
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+// addProperties adds a property for every field of a struct that encoding/json would write. The fields of embedded
+// structs are promoted, unless the struct declares a field with the same name itself
+func (mapper *typeMapper) addProperties(properties map[string]types.PropertySpec, strct *gotypes.Struct) error {
+	var embedded []*gotypes.Struct
+	for idx := 0; idx < strct.NumFields(); idx++ {
+		field := strct.Field(idx)
+		name, opts, _ := strings.Cut(reflect.StructTag(strct.Tag(idx)).Get("json"), ",")
+		if name == "-" && len(opts) == 0 {
+			continue
+		}
+
+		if field.Embedded() && len(name) == 0 {
+			if embeddedStruct, isStruct := derefStruct(field.Type()); isStruct {
+				embedded = append(embedded, embeddedStruct)
+				continue
+			}
+		}
+
+		if !field.Exported() {
+			continue
+		}
+
+		if len(name) == 0 {
+			name = field.Name()
+		}
+
+		dtype, err := mapper.Convert(field.Type())
+		if err != nil {
+			return fmt.Errorf("%s: field '%s': %w", mapper.fset.Position(field.Pos()), field.Name(), err)
+		}
+
+		optionList := strings.Split(opts, ",")
+		if slices.Contains(optionList, "string") && (dtype.TypeID == types.TypeID_INTEGER || dtype.TypeID == types.TypeID_FLOAT || dtype.TypeID == types.TypeID_BOOLEAN) {
+			dtype = types.DynamicType{TypeID: types.TypeID_STRING}
+		}
+
+		_, isPointer := gotypes.Unalias(field.Type()).(*gotypes.Pointer)
+		properties[name] = types.PropertySpec{
+			Documentation: types.Documentation{Description: mapper.docs[field.Pos()]},
+			Type:          dtype,
+			Required:      !isPointer && !slices.Contains(optionList, "omitempty") && !slices.Contains(optionList, "omitzero"),
+		}
+	}
+
+	for _, embeddedStruct := range embedded {
+		promoted := make(map[string]types.PropertySpec)
+		err := mapper.addProperties(promoted, embeddedStruct)
+		if err != nil {
+			return err
+		}
+
+		for name, property := range promoted {
+			if _, exists := properties[name]; !exists {
+				properties[name] = property
+			}
+		}
+	}
+
+	return nil
+}
+
+// derefStruct gets the struct a type or a pointer to it is declared as
+func derefStruct(tp gotypes.Type) (*gotypes.Struct, bool) {
+	if pointer, isPointer := gotypes.Unalias(tp).(*gotypes.Pointer); isPointer {
+		tp = pointer.Elem()
+	}
+
+	strct, isStruct := tp.Underlying().(*gotypes.Struct)
+	return strct, isStruct
+}
@@ -0,0 +1,203 @@
+package harinfer
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
+	"github.com/softwaresale/client-gen/v2/internal/sampleinfer"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"strings"
+)
+
+// builtEndpoint is an endpoint whose values are typed once the samples of every endpoint have been collected
+type builtEndpoint struct {
+	service *types.ServiceDefinition
+	index   int // index of the endpoint within its service
+	cluster *cluster
+	root    string // PascalCase name that samples of the endpoint are added under
+}
+
+// build names the endpoint of every cluster, groups the endpoints into services, and infers the types of their values
+func (inferrer *inferrer) build(apiName string) ([]types.ServiceDefinition, []types.EntitySpec) {
+	prefix := inferrer.commonPrefix()
+	samples := sampleinfer.New()
+
+	services := make(map[string]*types.ServiceDefinition)
+	var serviceOrder []string
+	var endpoints []builtEndpoint
+	roots := make(map[string]bool)
+
+	for _, key := range inferrer.order {
+		c := inferrer.clusters[key]
+
+		serviceName := c.serviceName(prefix, apiName)
+		service, exists := services[serviceName]
+		if !exists {
+			service = &types.ServiceDefinition{Name: serviceName}
+			services[serviceName] = service
+			serviceOrder = append(serviceOrder, serviceName)
+		}
+
+		name := uniqueName(c.endpointName(prefix), func(name string) bool { return hasEndpoint(service, name) })
+		root := uniqueName(identifiers.TypeName(name), func(name string) bool { return roots[name] })
+		roots[root] = true
+
+		requests := "requests"
+		if c.requests == 1 {
+			requests = "request"
+		}
+
+		endpoint := types.APIEndpoint{
+			Documentation:  types.Documentation{Description: fmt.Sprintf("Inferred from %d recorded %s", c.requests, requests)},
+			Name:           name,
+			Endpoint:       c.template.String(),
+			Method:         c.method,
+			PathVariables:  make(map[string]types.RequestValue),
+			QueryVariables: make(map[string]types.RequestValue),
+			RequestBody:    types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+			ResponseBody:   types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+		}
+
+		for variable, values := range c.pathSamples {
+			for _, value := range values {
+				samples.Add(root+" path "+variable, value)
+			}
+		}
+		for variable, values := range c.querySamples {
+			for _, value := range values {
+				samples.Add(root+" query "+variable, value)
+			}
+		}
+		for _, body := range c.bodies {
+			samples.Add(root+"Request", body)
+		}
+		for _, response := range c.responses {
+			samples.Add(root+"Response", response)
+		}
+
+		if c.otherBodies > 0 {
+			inferrer.warn("%d requests to %s %s have a body that is not JSON, so its type is unknown", c.otherBodies, c.method, endpoint.Endpoint)
+		}
+
+		endpoints = append(endpoints, builtEndpoint{service: service, index: len(service.Endpoints), cluster: c, root: root})
+		service.Endpoints = append(service.Endpoints, endpoint)
+	}
+
+	inference := samples.Infer()
+	for _, built := range endpoints {
+		built.resolve(inference)
+	}
+
+	serviceDefs := make([]types.ServiceDefinition, 0, len(serviceOrder))
+	for _, name := range serviceOrder {
+		serviceDefs = append(serviceDefs, *services[name])
+	}
+
+	return serviceDefs, inference.Entities
+}
+
+// resolve types the values of the endpoint
+func (built builtEndpoint) resolve(inference sampleinfer.Inference) {
+	endpoint := &built.service.Endpoints[built.index]
+	c := built.cluster
+
+	for variable := range c.pathSamples {
+		endpoint.PathVariables[variable] = types.RequestValue{Type: inference.Types[built.root+" path "+variable], Required: true}
+	}
+
+	for _, variable := range c.queryOrder {
+		dtype := inference.Types[built.root+" query "+variable]
+		if c.repeated[variable] {
+			dtype = types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{dtype}}
+		}
+
+		endpoint.QueryVariables[variable] = types.RequestValue{Type: dtype, Required: c.queryCounts[variable] == c.requests}
+	}
+
+	switch {
+	case len(c.bodies) > 0 && c.otherBodies == 0:
+		endpoint.RequestBody = types.RequestValue{Type: inference.Types[built.root+"Request"], Required: len(c.bodies) == c.requests}
+	case c.otherBodies > 0:
+		endpoint.RequestBody = types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_ANY}, Required: len(c.bodies)+c.otherBodies == c.requests}
+	}
+
+	if len(c.responses) > 0 {
+		endpoint.ResponseBody = types.RequestValue{Type: inference.Types[built.root+"Response"], Required: true}
+	}
+}
+
+// commonPrefix counts the leading literal segments that every endpoint shares, such as "/api/v1". The last segment of
+// an endpoint is never part of the prefix, so that it can still name a service
+func (inferrer *inferrer) commonPrefix() int {
+	var prefix []string
+	for i, key := range inferrer.order {
+		t := inferrer.clusters[key].template
+		limit := min(t.leadingLiterals(), max(len(t.segments)-1, 0))
+
+		if i == 0 {
+			prefix = t.segments[:limit]
+			continue
+		}
+
+		shared := 0
+		for shared < min(limit, len(prefix)) && prefix[shared] == t.segments[shared] {
+			shared++
+		}
+		prefix = prefix[:shared]
+	}
+
+	return len(prefix)
+}
+
+// serviceName names the service of a cluster after the first literal segment after the common prefix, such as "people"
+// in "/api/v1/people/{{personId}}"
+func (c *cluster) serviceName(prefix int, apiName string) string {
+	for i := prefix; i < len(c.template.segments); i++ {
+		if !c.template.isVariable(i) {
+			return identifiers.TypeName(c.template.segments[i])
+		}
+	}
+
+	// the rest of the path is variables, so name the service after the last segment of the prefix instead
+	if prefix > 0 {
+		return identifiers.TypeName(c.template.segments[prefix-1])
+	}
+
+	return apiName
+}
+
+// endpointName names the endpoint of a cluster after its method and the segments after the common prefix, such as
+// "getPeopleByPersonId" for "GET /api/v1/people/{{personId}}"
+func (c *cluster) endpointName(prefix int) string {
+	words := []string{strings.ToLower(c.method)}
+	for i := prefix; i < len(c.template.segments); i++ {
+		if !c.template.isVariable(i) {
+			words = append(words, c.template.segments[i])
+		}
+	}
+
+	if last := len(c.template.segments) - 1; last >= 0 && c.template.isVariable(last) {
+		words = append(words, "by", c.template.segments[last])
+	}
+
+	return identifiers.MemberName(strings.Join(words, " "))
+}
+
+// uniqueName numbers a name until it is not taken
+func uniqueName(name string, isTaken func(string) bool) string {
+	unique := name
+	for i := 2; isTaken(unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+
+	return unique
+}
+
+func hasEndpoint(service *types.ServiceDefinition, name string) bool {
+	for _, endpoint := range service.Endpoints {
+		if endpoint.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+// Package harinfer infers a draft API definition from HTTP traffic recorded in HAR files, such as those exported by the
+// developer tools of browsers
+package harinfer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Archive is a HAR file
+type Archive struct {
+	Log Log `json:"log"`
+}
+
+// Log is the recording of a HAR file
+type Log struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a single recorded request and its response
+type Entry struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+}
+
+// NameValue is a header or query variable
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a recorded request
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Response is a recorded HTTP response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers []NameValue `json:"headers"`
+	Content Content     `json:"content"`
+}
+
+// Content is the body of a recorded response
+type Content struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"` // set to base64 if the text is encoded
+}
+
+// Decoded gets the text of the body, decoding it if needed
+func (content Content) Decoded() (string, error) {
+	if content.Encoding != "base64" {
+		return content.Text, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(content.Text)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64 content: %w", err)
+	}
+
+	return string(decoded), nil
+}
+
+// Read decodes a HAR file
+func Read(data []byte) (Archive, error) {
+	var archive Archive
+	err := json.Unmarshal(data, &archive)
+	if err != nil {
+		return Archive{}, fmt.Errorf("failed to decode HAR file: %w", err)
+	}
+
+	return archive, nil
+}
+
+// isJSON checks if a MIME type describes JSON, such as application/json or application/problem+json
+func isJSON(mimeType string) bool {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || mediaType == "text/json"
+}
@@ -0,0 +1,255 @@
+package harinfer
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
+	"github.com/softwaresale/client-gen/v2/internal/sampleinfer"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"net/url"
+	"strings"
+)
+
+// Options configures how a draft API definition is inferred from recorded traffic
+type Options struct {
+	Name      string       // name of the API. Defaults to the host of the base URL
+	Version   string       // version of the API
+	BaseURL   string       // only requests below this URL are inferred. Defaults to the origin with the most requests
+	OnWarning func(string) // optionally receives warnings, such as requests that were skipped
+}
+
+// Infer derives a draft API definition from recorded traffic. Only requests that send or receive JSON, or receive no
+// content, are considered. They are clustered into endpoints by their method and normalized path, in which numeric and
+// UUID segments are path variables. Query variables and entities are inferred from every request of an endpoint, so a
+// value is only required if every request had it
+func Infer(archive Archive, opts Options) (types.APIDefinition, error) {
+	base, err := chooseBase(archive.Log.Entries, opts)
+	if err != nil {
+		return types.APIDefinition{}, err
+	}
+
+	inferrer := &inferrer{
+		opts:     opts,
+		clusters: make(map[string]*cluster),
+	}
+
+	skipped := 0
+	for _, entry := range archive.Log.Entries {
+		path, isBelow := base.relative(entry.Request.URL)
+		if !isBelow || !isAPIRequest(entry) {
+			skipped++
+			continue
+		}
+
+		inferrer.add(entry, path)
+	}
+
+	if len(inferrer.order) == 0 {
+		return types.APIDefinition{}, fmt.Errorf("no requests below '%s' send or receive JSON", base)
+	}
+	if skipped > 0 {
+		inferrer.warn("skipped %d requests that are not below '%s' or do not send or receive JSON", skipped, base)
+	}
+
+	apiDef := types.APIDefinition{
+		Name:    opts.Name,
+		Version: opts.Version,
+		Config:  types.APIConfig{BaseURL: strings.TrimSuffix(base.String(), "/")},
+	}
+	if len(apiDef.Name) == 0 {
+		apiDef.Name = base.Hostname()
+	}
+
+	apiDef.Services, apiDef.Entities = inferrer.build(identifiers.TypeName(apiDef.Name))
+	return apiDef, nil
+}
+
+// baseURL is the URL that endpoints are relative to
+type baseURL struct {
+	*url.URL
+}
+
+// relative gets the path of a request below the base URL
+func (base baseURL) relative(rawURL string) (string, bool) {
+	requestURL, err := url.Parse(rawURL)
+	if err != nil || requestURL.Scheme != base.Scheme || requestURL.Host != base.Host {
+		return "", false
+	}
+
+	basePath := strings.TrimSuffix(base.Path, "/")
+	path, isBelow := strings.CutPrefix(requestURL.Path, basePath)
+	if !isBelow || (len(path) > 0 && path[0] != '/') {
+		return "", false
+	}
+
+	return path, true
+}
+
+// chooseBase gets the base URL from the options, or else the origin that most requests were sent to
+func chooseBase(entries []Entry, opts Options) (baseURL, error) {
+	if len(opts.BaseURL) > 0 {
+		parsed, err := url.Parse(opts.BaseURL)
+		if err != nil || len(parsed.Host) == 0 {
+			return baseURL{}, fmt.Errorf("invalid base URL '%s'", opts.BaseURL)
+		}
+
+		return baseURL{parsed}, nil
+	}
+
+	counts := make(map[string]int)
+	var best string
+	for _, entry := range entries {
+		if !isAPIRequest(entry) {
+			continue
+		}
+
+		parsed, err := url.Parse(entry.Request.URL)
+		if err != nil || len(parsed.Host) == 0 {
+			continue
+		}
+
+		origin := parsed.Scheme + "://" + parsed.Host
+		counts[origin]++
+		if counts[origin] > counts[best] {
+			best = origin
+		}
+	}
+
+	if len(best) == 0 {
+		return baseURL{}, fmt.Errorf("no recorded requests send or receive JSON")
+	}
+
+	parsed, _ := url.Parse(best)
+	return baseURL{parsed}, nil
+}
+
+// isAPIRequest checks if a recorded request belongs to an API, rather than being a page, script or image
+func isAPIRequest(entry Entry) bool {
+	if entry.Request.Method == "OPTIONS" || entry.Response.Status == 0 {
+		return false
+	}
+
+	if entry.Request.PostData != nil && isJSON(entry.Request.PostData.MimeType) {
+		return true
+	}
+
+	return isJSON(entry.Response.Content.MimeType) || entry.Response.Status == 204
+}
+
+// cluster collects the recorded requests of a single endpoint
+type cluster struct {
+	method   string
+	template template
+	requests int
+
+	pathSamples  map[string][]any // samples of each path variable
+	querySamples map[string][]any // samples of each query variable
+	queryCounts  map[string]int   // the number of requests that had each query variable
+	queryOrder   []string         // query variables, in the order they were first seen
+	repeated     map[string]bool  // query variables that were given more than once in a single request
+	bodies       []any            // samples of JSON request bodies
+	otherBodies  int              // the number of requests that had a body which is not JSON
+	responses    []any            // samples of successful JSON response bodies
+}
+
+// inferrer clusters recorded requests and collects samples of their values
+type inferrer struct {
+	opts     Options
+	clusters map[string]*cluster
+	order    []string // keys of the clusters, in the order they were first seen
+}
+
+func (inferrer *inferrer) warn(format string, args ...any) {
+	if inferrer.opts.OnWarning != nil {
+		inferrer.opts.OnWarning(fmt.Sprintf(format, args...))
+	}
+}
+
+// add adds a recorded request to the cluster of its endpoint
+func (inferrer *inferrer) add(entry Entry, path string) {
+	normalized, values := normalizePath(path)
+	method := strings.ToUpper(entry.Request.Method)
+	key := method + " " + normalized.String()
+
+	c, exists := inferrer.clusters[key]
+	if !exists {
+		c = &cluster{
+			method:       method,
+			template:     normalized,
+			pathSamples:  make(map[string][]any),
+			querySamples: make(map[string][]any),
+			queryCounts:  make(map[string]int),
+			repeated:     make(map[string]bool),
+		}
+		inferrer.clusters[key] = c
+		inferrer.order = append(inferrer.order, key)
+	}
+	c.requests++
+
+	for i, variable := range normalized.variables {
+		name := normalized.segments[variable]
+		c.pathSamples[name] = append(c.pathSamples[name], sampleinfer.Scalar(values[i]))
+	}
+
+	seen := make(map[string]bool)
+	for _, param := range queryOf(entry.Request) {
+		if _, known := c.querySamples[param.Name]; !known {
+			c.queryOrder = append(c.queryOrder, param.Name)
+		}
+
+		if seen[param.Name] {
+			c.repeated[param.Name] = true
+		} else {
+			seen[param.Name] = true
+			c.queryCounts[param.Name]++
+		}
+		c.querySamples[param.Name] = append(c.querySamples[param.Name], sampleinfer.Scalar(param.Value))
+	}
+
+	if postData := entry.Request.PostData; postData != nil && len(strings.TrimSpace(postData.Text)) > 0 {
+		sample, err := sampleinfer.Decode(postData.Text)
+		if isJSON(postData.MimeType) && err == nil {
+			c.bodies = append(c.bodies, sample)
+		} else {
+			c.otherBodies++
+		}
+	}
+
+	if entry.Response.Status >= 200 && entry.Response.Status < 300 && isJSON(entry.Response.Content.MimeType) {
+		text, err := entry.Response.Content.Decoded()
+		if err == nil && len(strings.TrimSpace(text)) > 0 {
+			sample, err := sampleinfer.Decode(text)
+			if err != nil {
+				inferrer.warn("the response of %s %s is not valid JSON, so it was skipped", method, entry.Request.URL)
+				return
+			}
+
+			c.responses = append(c.responses, sample)
+		}
+	}
+}
+
+// queryOf gets the query variables of a recorded request, parsing them from its URL if they were not recorded
+func queryOf(request Request) []NameValue {
+	if len(request.QueryString) > 0 {
+		return request.QueryString
+	}
+
+	parsed, err := url.Parse(request.URL)
+	if err != nil {
+		return nil
+	}
+
+	var params []NameValue
+	for _, pair := range strings.Split(parsed.RawQuery, "&") {
+		if len(pair) == 0 {
+			continue
+		}
+
+		name, value, _ := strings.Cut(pair, "=")
+		name, _ = url.QueryUnescape(name)
+		value, _ = url.QueryUnescape(value)
+		params = append(params, NameValue{Name: name, Value: value})
+	}
+
+	return params
+}
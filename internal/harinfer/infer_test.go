@@ -0,0 +1,137 @@
+package harinfer
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readArchive(t *testing.T) Archive {
+	data, err := os.ReadFile(filepath.Join("testdata", "traffic.har"))
+	assert.NoError(t, err)
+
+	archive, err := Read(data)
+	assert.NoError(t, err)
+	return archive
+}
+
+func TestInfer_ClustersRequestsIntoEndpoints(t *testing.T) {
+	var warnings []string
+	apiDef, err := Infer(readArchive(t), Options{OnWarning: func(warning string) { warnings = append(warnings, warning) }})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "api.acme.com", apiDef.Name)
+	assert.Equal(t, "https://api.acme.com", apiDef.Config.BaseURL)
+	assert.Equal(t, []string{"skipped 3 requests that are not below 'https://api.acme.com' or do not send or receive JSON"}, warnings)
+
+	assert.Len(t, apiDef.Services, 2)
+	people, orders := apiDef.Services[0], apiDef.Services[1]
+	assert.Equal(t, "People", people.Name)
+	assert.Equal(t, "Orders", orders.Name)
+
+	var names []string
+	for _, endpoint := range people.Endpoints {
+		names = append(names, endpoint.Method+" "+endpoint.Endpoint+" "+endpoint.Name)
+	}
+	assert.Equal(t, []string{
+		"GET /api/v1/people getPeople",
+		"GET /api/v1/people/{{personId}} getPeopleByPersonId",
+		"POST /api/v1/people postPeople",
+		"DELETE /api/v1/people/{{personId}} deletePeopleByPersonId",
+	}, names)
+
+	getPeople := people.Endpoints[0]
+	assert.Equal(t, "Inferred from 2 recorded requests", getPeople.Description)
+	assert.Equal(t, map[string]types.RequestValue{
+		"limit": {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}},
+		"tag":   {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}}}},
+	}, getPeople.QueryVariables)
+	assert.True(t, getPeople.RequestBody.Type.IsVoid())
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "GetPeopleResponseItem"}}}, getPeople.ResponseBody.Type)
+
+	getPerson := people.Endpoints[1]
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true}, getPerson.PathVariables["personId"])
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "GetPeopleByPersonIdResponse"}, getPerson.ResponseBody.Type)
+
+	postPeople := people.Endpoints[2]
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "PostPeopleRequest"}, Required: true}, postPeople.RequestBody)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "PostPeopleResponse"}, postPeople.ResponseBody.Type)
+
+	deletePerson := people.Endpoints[3]
+	assert.Equal(t, "Inferred from 1 recorded request", deletePerson.Description)
+	assert.True(t, deletePerson.ResponseBody.Type.IsVoid())
+
+	updateItem := orders.Endpoints[0]
+	assert.Equal(t, "putOrdersItemsByItemId", updateItem.Name)
+	assert.Equal(t, "/api/v1/orders/{{orderId}}/items/{{itemId}}", updateItem.Endpoint)
+	assert.Equal(t, types.TypeID_STRING, updateItem.PathVariables["orderId"].Type.TypeID)
+	assert.Equal(t, types.TypeID_INTEGER, updateItem.PathVariables["itemId"].Type.TypeID)
+
+	entities := make(map[string]types.EntitySpec)
+	for _, entity := range apiDef.Entities {
+		entities[entity.Name] = entity
+	}
+	assert.Len(t, entities, 7)
+
+	person := entities["GetPeopleResponseItem"]
+	assert.True(t, person.Properties["name"].Required)
+	assert.False(t, person.Properties["email"].Required)
+	assert.Equal(t, types.TypeID_USER, person.Properties["address"].Type.TypeID)
+	assert.False(t, entities["GetPeopleResponseItemAddress"].Properties["zip"].Required)
+
+	assert.Equal(t, types.TypeID_TIMESTAMP, entities["GetPeopleByPersonIdResponse"].Properties["createdAt"].Type.TypeID)
+	assert.True(t, entities["PostPeopleRequest"].Properties["name"].Required)
+	assert.False(t, entities["PostPeopleRequest"].Properties["age"].Required)
+	assert.Equal(t, types.TypeID_FLOAT, entities["PutOrdersItemsByItemIdResponse"].Properties["price"].Type.TypeID)
+}
+
+func TestInfer_OnlyConsidersRequestsBelowBaseURL(t *testing.T) {
+	apiDef, err := Infer(readArchive(t), Options{Name: "orders", BaseURL: "https://api.acme.com/api/v1/orders/"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "orders", apiDef.Name)
+	assert.Equal(t, "https://api.acme.com/api/v1/orders", apiDef.Config.BaseURL)
+	assert.Len(t, apiDef.Services, 1)
+	assert.Equal(t, "Items", apiDef.Services[0].Name)
+	assert.Equal(t, "/{{id}}/items/{{itemId}}", apiDef.Services[0].Endpoints[0].Endpoint)
+}
+
+func TestInfer_RequiresJSONRequests(t *testing.T) {
+	archive := Archive{Log: Log{Entries: []Entry{
+		{Request: Request{Method: "GET", URL: "https://acme.com/"}, Response: Response{Status: 200, Content: Content{MimeType: "text/html"}}},
+	}}}
+
+	_, err := Infer(archive, Options{})
+	assert.EqualError(t, err, "no recorded requests send or receive JSON")
+
+	_, err = Infer(archive, Options{BaseURL: "https://acme.com"})
+	assert.EqualError(t, err, "no requests below 'https://acme.com' send or receive JSON")
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		expected string
+		values   []string
+	}{
+		"literal":      {path: "/people", expected: "/people"},
+		"numeric id":   {path: "/people/42", expected: "/people/{{personId}}", values: []string{"42"}},
+		"uuid":         {path: "/orders/6fa459ea-ee8a-3ca4-894e-db77e160355e/", expected: "/orders/{{orderId}}", values: []string{"6fa459ea-ee8a-3ca4-894e-db77e160355e"}},
+		"nested":       {path: "/categories/1/boxes/2", expected: "/categories/{{categoryId}}/boxes/{{boxId}}", values: []string{"1", "2"}},
+		"leading id":   {path: "/1/2", expected: "/{{id}}/{{id2}}", values: []string{"1", "2"}},
+		"versioned":    {path: "/v2/user-groups/3", expected: "/v2/user-groups/{{userGroupId}}", values: []string{"3"}},
+		"root":         {path: "/", expected: "/"},
+		"not an id":    {path: "/people/ada", expected: "/people/ada"},
+		"mixed digits": {path: "/reports/2024-q1", expected: "/reports/2024-q1"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			normalized, values := normalizePath(test.path)
+			assert.Equal(t, test.expected, normalized.String())
+			assert.Equal(t, test.values, values)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package harinfer
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
+	"regexp"
+	"strings"
+)
+
+var (
+	// numberPattern matches path segments that are numeric IDs
+	numberPattern = regexp.MustCompile(`^[0-9]+$`)
+
+	// uuidPattern matches path segments that are UUIDs
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// template is a normalized request path, in which IDs are replaced with path variables
+type template struct {
+	segments  []string // literal segments and the names of variables
+	variables []int    // indices of the segments that are variables
+}
+
+// normalizePath splits a request path into a template. Numeric and UUID segments become variables, which are named
+// after the segment before them, so that "/people/42" becomes "/people/{{personId}}"
+func normalizePath(path string) (template, []string) {
+	var normalized template
+	var values []string
+
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if len(segment) == 0 {
+			continue
+		}
+
+		if !numberPattern.MatchString(segment) && !uuidPattern.MatchString(segment) {
+			normalized.segments = append(normalized.segments, segment)
+			continue
+		}
+
+		name := "id"
+		if len(normalized.segments) > 0 && !normalized.isVariable(len(normalized.segments)-1) {
+			name = identifiers.MemberName(singular(normalized.segments[len(normalized.segments)-1]) + " id")
+		}
+
+		unique := name
+		for i := 2; normalized.hasVariable(unique); i++ {
+			unique = fmt.Sprintf("%s%d", name, i)
+		}
+
+		normalized.variables = append(normalized.variables, len(normalized.segments))
+		normalized.segments = append(normalized.segments, unique)
+		values = append(values, segment)
+	}
+
+	return normalized, values
+}
+
+func (t template) isVariable(index int) bool {
+	for _, variable := range t.variables {
+		if variable == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t template) hasVariable(name string) bool {
+	for _, variable := range t.variables {
+		if t.segments[variable] == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// leadingLiterals counts the segments before the first variable
+func (t template) leadingLiterals() int {
+	if len(t.variables) == 0 {
+		return len(t.segments)
+	}
+
+	return t.variables[0]
+}
+
+// String writes the template as an endpoint, such as "/people/{{personId}}"
+func (t template) String() string {
+	segments := make([]string, len(t.segments))
+	for i, segment := range t.segments {
+		if t.isVariable(i) {
+			segment = "{{" + segment + "}}"
+		}
+		segments[i] = segment
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// singular guesses the singular of a plural English word, so that variables are named after a single resource
+func singular(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case lower == "people":
+		return "person"
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && len(lower) > 1:
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
@@ -0,0 +1,118 @@
+package identifiers
+
+import (
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"strings"
+	"unicode"
+)
+
+// Kind describes what a name is used for in generated code. Each kind of name follows its own conventions
+type Kind string
+
+const (
+	Kind_TYPE     Kind = "type"     // names of entities and services, which become types
+	Kind_MEMBER   Kind = "member"   // names of endpoints, which become methods
+	Kind_PROPERTY Kind = "property" // names of properties and variables, which become fields
+)
+
+// Policy describes which identifiers are valid in a target language and how invalid names are fixed
+type Policy struct {
+	Language               string          // name of the target language
+	Reserved               map[string]bool // words that cannot be used as identifiers
+	ReservedTypes          map[string]bool // additional words that cannot be used as type names
+	ReservedMembersAllowed bool            // if true, reserved words may be used as member names
+	PropertiesQuoted       bool            // if true, properties that are not valid identifiers are quoted instead of renamed
+	DollarAllowed          bool            // if true, identifiers may contain '$'
+	EscapeSuffix           string          // appended to reserved words to escape them
+}
+
+// Sanitize makes a name valid for the given kind of identifier. Names made of invalid characters are converted to the
+// case convention of their kind, and reserved words are escaped. Returns whether the name changed
+func (policy Policy) Sanitize(kind Kind, name string) (string, bool) {
+	if kind == Kind_PROPERTY && policy.PropertiesQuoted {
+		return name, false
+	}
+
+	sanitized := name
+	if !policy.isIdentifier(sanitized) {
+		sanitized = convertCase(kind, sanitized)
+	}
+
+	// identifiers cannot be empty or start with a digit
+	if len(sanitized) == 0 || unicode.IsDigit([]rune(sanitized)[0]) {
+		sanitized = "_" + sanitized
+	}
+
+	if policy.isReserved(kind, sanitized) {
+		sanitized += policy.EscapeSuffix
+	}
+
+	return sanitized, sanitized != name
+}
+
+func (policy Policy) isReserved(kind Kind, name string) bool {
+	if kind == Kind_MEMBER && policy.ReservedMembersAllowed {
+		return false
+	}
+
+	if kind == Kind_TYPE && policy.ReservedTypes[name] {
+		return true
+	}
+
+	return policy.Reserved[name]
+}
+
+// convertCase converts a name into the case convention of its kind, which drops any separator characters
+func convertCase(kind Kind, name string) string {
+	// strip anything that cannot appear in an identifier, treating it as a word separator
+	cleaned := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return ' '
+	}, name)
+
+	cleaned = strings.TrimSpace(cleaned)
+	if kind == Kind_TYPE {
+		return strcase.ToCamel(cleaned)
+	}
+
+	return strcase.ToLowerCamel(cleaned)
+}
+
+// isIdentifier checks if a name is made up only of characters that are valid in identifiers
+func (policy Policy) isIdentifier(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	for _, r := range name {
+		if r == '$' && policy.DollarAllowed {
+			continue
+		}
+
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Rename records that a name from the specification was changed to make it a valid identifier
+type Rename struct {
+	Subject  string // what was renamed, such as "entity" or "property"
+	Scope    string // where the name was found, such as the entity that defines a property
+	Original string // the name as it is written in the specification
+	Renamed  string // the name used in generated code
+}
+
+func (rename Rename) String() string {
+	if len(rename.Scope) == 0 {
+		return fmt.Sprintf("%s '%s' was renamed to '%s'", rename.Subject, rename.Original, rename.Renamed)
+	}
+
+	return fmt.Sprintf("%s '%s' of %s was renamed to '%s'", rename.Subject, rename.Original, rename.Scope, rename.Renamed)
+}
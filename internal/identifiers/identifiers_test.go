@@ -0,0 +1,53 @@
+package identifiers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPolicy_Sanitize(t *testing.T) {
+	tests := []struct {
+		policy   Policy
+		kind     Kind
+		name     string
+		expected string
+	}{
+		{TypeScript, Kind_TYPE, "Person", "Person"},
+		{TypeScript, Kind_TYPE, "my-entity", "MyEntity"},
+		{TypeScript, Kind_TYPE, "class", "class_"},
+		{TypeScript, Kind_TYPE, "string", "string_"},
+		{TypeScript, Kind_TYPE, "2fa", "_2fa"},
+		{TypeScript, Kind_MEMBER, "default", "default"},
+		{TypeScript, Kind_MEMBER, "get-all", "getAll"},
+		{TypeScript, Kind_PROPERTY, "content-type", "content-type"},
+		{Java, Kind_MEMBER, "default", "default_"},
+		{Java, Kind_PROPERTY, "content-type", "contentType"},
+		{Java, Kind_TYPE, "record", "record_"},
+		{Go, Kind_PROPERTY, "type", "type_"},
+		{Go, Kind_PROPERTY, "price$", "price"},
+	}
+
+	for _, test := range tests {
+		sanitized, changed := test.policy.Sanitize(test.kind, test.name)
+		assert.Equal(t, test.expected, sanitized, "%s %s '%s'", test.policy.Language, test.kind, test.name)
+		assert.Equal(t, test.expected != test.name, changed)
+	}
+}
+
+func TestRename_String(t *testing.T) {
+	rename := Rename{Subject: "property", Scope: "entity 'Person'", Original: "class", Renamed: "class_"}
+	assert.Equal(t, "property 'class' of entity 'Person' was renamed to 'class_'", rename.String())
+}
+
+func TestTypeName_SplitsWordsOnSeparators(t *testing.T) {
+	assert.Equal(t, "ListPeople", TypeName("List people"))
+	assert.Equal(t, "PersonAddress", TypeName("person-address"))
+	assert.Equal(t, "V2Orders", TypeName("/v2/orders/"))
+	assert.Equal(t, "", TypeName("--"))
+}
+
+func TestMemberName_SplitsWordsOnSeparators(t *testing.T) {
+	assert.Equal(t, "listPeople", MemberName("List people"))
+	assert.Equal(t, "getPeopleByPersonId", MemberName("get people by personId"))
+	assert.Equal(t, "", MemberName("?"))
+}
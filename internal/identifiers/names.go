@@ -0,0 +1,27 @@
+package identifiers
+
+import (
+	"github.com/iancoleman/strcase"
+	"regexp"
+	"strings"
+)
+
+// separatorPattern matches the characters that separate the words of a name
+var separatorPattern = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// TypeName converts a name taken from an imported document, such as "List people" or "person-address", into a
+// PascalCase type name. Anything other than ASCII letters and digits separates words
+func TypeName(name string) string {
+	return strcase.ToCamel(splitWords(name))
+}
+
+// MemberName converts a name taken from an imported document into a lowerCamelCase member name, such as "listPeople"
+// for "List people"
+func MemberName(name string) string {
+	return strcase.ToLowerCamel(splitWords(name))
+}
+
+// splitWords splits a name into words separated by single spaces
+func splitWords(name string) string {
+	return strings.TrimSpace(separatorPattern.ReplaceAllString(name, " "))
+}
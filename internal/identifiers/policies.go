@@ -0,0 +1,58 @@
+package identifiers
+
+// words creates a set of words
+func words(list ...string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, word := range list {
+		set[word] = true
+	}
+
+	return set
+}
+
+// TypeScript is the identifier policy for TypeScript. Reserved words are valid method and property names, and
+// properties with invalid characters can be quoted, so only type and method names are ever renamed
+var TypeScript = Policy{
+	Language: "TypeScript",
+	Reserved: words(
+		"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "enum",
+		"export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null",
+		"return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with",
+		"implements", "interface", "let", "package", "private", "protected", "public", "static", "yield", "await",
+	),
+	ReservedTypes: words(
+		"any", "bigint", "boolean", "never", "number", "object", "string", "symbol", "undefined", "unknown",
+	),
+	ReservedMembersAllowed: true,
+	PropertiesQuoted:       true,
+	DollarAllowed:          true,
+	EscapeSuffix:           "_",
+}
+
+// Java is the identifier policy for Java
+var Java = Policy{
+	Language: "Java",
+	Reserved: words(
+		"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const", "continue",
+		"default", "do", "double", "else", "enum", "extends", "final", "finally", "float", "for", "goto", "if",
+		"implements", "import", "instanceof", "int", "interface", "long", "native", "new", "package", "private",
+		"protected", "public", "return", "short", "static", "strictfp", "super", "switch", "synchronized", "this",
+		"throw", "throws", "transient", "try", "void", "volatile", "while", "true", "false", "null", "_",
+	),
+	ReservedTypes: words(
+		"var", "record", "yield", "sealed", "permits",
+	),
+	DollarAllowed: true,
+	EscapeSuffix:  "_",
+}
+
+// Go is the identifier policy for Go
+var Go = Policy{
+	Language: "Go",
+	Reserved: words(
+		"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func", "go",
+		"goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct", "switch", "type",
+		"var",
+	),
+	EscapeSuffix: "_",
+}
@@ -0,0 +1,198 @@
+package identifiers
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// namer sanitizes the names within a single scope, making sure that renamed names do not collide with other names
+type namer struct {
+	policy  Policy
+	kind    Kind
+	subject string
+	scope   string
+	used    map[string]bool
+	renames *[]Rename
+}
+
+func newNamer(policy Policy, kind Kind, subject, scope string, names []string, renames *[]Rename) *namer {
+	// names that are already valid keep their names, so renamed names have to work around them
+	used := make(map[string]bool)
+	for _, name := range names {
+		if _, changed := policy.Sanitize(kind, name); !changed {
+			used[name] = true
+		}
+	}
+
+	return &namer{
+		policy:  policy,
+		kind:    kind,
+		subject: subject,
+		scope:   scope,
+		used:    used,
+		renames: renames,
+	}
+}
+
+// Name gets the sanitized name of the given name, recording it if it was renamed
+func (n *namer) Name(name string) string {
+	sanitized, changed := n.policy.Sanitize(n.kind, name)
+	if !changed {
+		return name
+	}
+
+	candidate := sanitized
+	for suffix := 2; n.used[candidate]; suffix++ {
+		candidate = fmt.Sprintf("%s%d", sanitized, suffix)
+	}
+	n.used[candidate] = true
+
+	*n.renames = append(*n.renames, Rename{Subject: n.subject, Scope: n.scope, Original: name, Renamed: candidate})
+	return candidate
+}
+
+// SanitizeAPI makes every name in an API definition a valid identifier under the given policy, returning a sanitized
+// copy along with every rename that was made. References to renamed entities are updated to match, and renamed
+// properties keep sending their original names on the wire. Query variables are never renamed, as their names are
+// part of the request
+func SanitizeAPI(api types.APIDefinition, policy Policy) (types.APIDefinition, []Rename) {
+	var renames []Rename
+
+	entityNames := make([]string, 0, len(api.Entities))
+	for _, entity := range api.Entities {
+		entityNames = append(entityNames, entity.Name)
+	}
+
+	entityNamer := newNamer(policy, Kind_TYPE, "entity", "", entityNames, &renames)
+	entityRenames := make(map[string]string)
+	for _, name := range entityNames {
+		if renamed := entityNamer.Name(name); renamed != name {
+			entityRenames[name] = renamed
+		}
+	}
+
+	sanitized := api
+	sanitized.Entities = make([]types.EntitySpec, 0, len(api.Entities))
+	for _, entity := range api.Entities {
+		scope := fmt.Sprintf("entity '%s'", entity.Name)
+		entity.Name = renameType(entity.Name, entityRenames)
+		entity.Properties = sanitizeProperties(entity.Properties, policy, api.Naming, scope, entityRenames, &renames)
+		sanitized.Entities = append(sanitized.Entities, entity)
+	}
+
+	serviceNames := make([]string, 0, len(api.Services))
+	for _, service := range api.Services {
+		serviceNames = append(serviceNames, service.Name)
+	}
+
+	serviceNamer := newNamer(policy, Kind_TYPE, "service", "", serviceNames, &renames)
+	sanitized.Services = make([]types.ServiceDefinition, 0, len(api.Services))
+	for _, service := range api.Services {
+		scope := fmt.Sprintf("service '%s'", service.Name)
+		service.Name = serviceNamer.Name(service.Name)
+
+		endpointNames := make([]string, 0, len(service.Endpoints))
+		for _, endpoint := range service.Endpoints {
+			endpointNames = append(endpointNames, endpoint.Name)
+		}
+
+		endpointNamer := newNamer(policy, Kind_MEMBER, "endpoint", scope, endpointNames, &renames)
+		endpoints := make([]types.APIEndpoint, 0, len(service.Endpoints))
+		for _, endpoint := range service.Endpoints {
+			endpoints = append(endpoints, sanitizeEndpoint(endpoint, endpointNamer.Name(endpoint.Name), policy, scope, entityRenames, &renames))
+		}
+		service.Endpoints = endpoints
+
+		sanitized.Services = append(sanitized.Services, service)
+	}
+
+	return sanitized, renames
+}
+
+// sanitizeProperties sanitizes the names of properties, keeping their original wire names
+func sanitizeProperties(properties map[string]types.PropertySpec, policy Policy, naming types.NamingStrategy, scope string, entityRenames map[string]string, renames *[]Rename) map[string]types.PropertySpec {
+	names := slices.Sorted(maps.Keys(properties))
+	propertyNamer := newNamer(policy, Kind_PROPERTY, "property", scope, names, renames)
+
+	sanitized := make(map[string]types.PropertySpec, len(properties))
+	for _, name := range names {
+		propSpec := properties[name]
+		propSpec.Type = renameReferences(propSpec.Type, entityRenames)
+
+		renamed := propertyNamer.Name(name)
+		if renamed != name {
+			propSpec.WireName = propSpec.ResolveWireName(name, naming)
+		}
+
+		sanitized[renamed] = propSpec
+	}
+
+	return sanitized
+}
+
+// sanitizeEndpoint sanitizes the names of an endpoint's path variables, updating its URI template to match
+func sanitizeEndpoint(endpoint types.APIEndpoint, name string, policy Policy, scope string, entityRenames map[string]string, renames *[]Rename) types.APIEndpoint {
+	endpointScope := fmt.Sprintf("endpoint '%s' of %s", endpoint.Name, scope)
+	endpoint.Name = name
+	endpoint.RequestBody.Type = renameReferences(endpoint.RequestBody.Type, entityRenames)
+	endpoint.ResponseBody.Type = renameReferences(endpoint.ResponseBody.Type, entityRenames)
+
+	names := slices.Sorted(maps.Keys(endpoint.PathVariables))
+	variableNamer := newNamer(policy, Kind_PROPERTY, "path variable", endpointScope, names, renames)
+	pathVariables := make(map[string]types.RequestValue, len(endpoint.PathVariables))
+	for _, variable := range names {
+		value := endpoint.PathVariables[variable]
+		value.Type = renameReferences(value.Type, entityRenames)
+
+		renamed := variableNamer.Name(variable)
+		if renamed != variable {
+			endpoint.Endpoint = renameTemplateVariable(endpoint.Endpoint, variable, renamed)
+		}
+
+		pathVariables[renamed] = value
+	}
+	endpoint.PathVariables = pathVariables
+
+	queryVariables := make(map[string]types.RequestValue, len(endpoint.QueryVariables))
+	for variable, value := range endpoint.QueryVariables {
+		value.Type = renameReferences(value.Type, entityRenames)
+		queryVariables[variable] = value
+	}
+	endpoint.QueryVariables = queryVariables
+
+	return endpoint
+}
+
+// renameTemplateVariable renames a variable in a URI template
+func renameTemplateVariable(template, variable, renamed string) string {
+	variablePattern := regexp.MustCompile(`\{\{\s*` + regexp.QuoteMeta(variable) + `\s*}}`)
+	return variablePattern.ReplaceAllLiteralString(template, fmt.Sprintf("{{%s}}", renamed))
+}
+
+// renameReferences updates every reference to a renamed entity within a type
+func renameReferences(dtype types.DynamicType, entityRenames map[string]string) types.DynamicType {
+	if dtype.TypeID == types.TypeID_USER || dtype.TypeID == types.TypeID_GENERIC {
+		dtype.Reference = renameType(dtype.Reference, entityRenames)
+	}
+
+	if len(dtype.Inner) > 0 {
+		inner := make([]types.DynamicType, 0, len(dtype.Inner))
+		for _, innerTp := range dtype.Inner {
+			inner = append(inner, renameReferences(innerTp, entityRenames))
+		}
+		dtype.Inner = inner
+	}
+
+	return dtype
+}
+
+func renameType(name string, entityRenames map[string]string) string {
+	if renamed, exists := entityRenames[name]; exists {
+		return renamed
+	}
+
+	return name
+}
@@ -0,0 +1,91 @@
+package identifiers
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSanitizeAPI_RenamesEntitiesAndReferences(t *testing.T) {
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{
+				Name: "my-entity",
+				Properties: map[string]types.PropertySpec{
+					"children": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "my-entity"}}}},
+				},
+			},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Name: "Things",
+				Endpoints: []types.APIEndpoint{
+					{
+						Name:          "get-thing",
+						Endpoint:      "/things/{{ thing-id }}",
+						PathVariables: map[string]types.RequestValue{"thing-id": {Type: types.DynamicType{TypeID: types.TypeID_STRING}}},
+						ResponseBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "my-entity"}},
+					},
+				},
+			},
+		},
+	}
+
+	sanitized, renames := SanitizeAPI(api, Java)
+
+	assert.Equal(t, "MyEntity", sanitized.Entities[0].Name)
+	assert.Equal(t, "MyEntity", sanitized.Entities[0].Properties["children"].Type.Inner[0].Reference)
+
+	endpoint := sanitized.Services[0].Endpoints[0]
+	assert.Equal(t, "getThing", endpoint.Name)
+	assert.Equal(t, "/things/{{thingId}}", endpoint.Endpoint)
+	assert.Contains(t, endpoint.PathVariables, "thingId")
+	assert.Equal(t, "MyEntity", endpoint.ResponseBody.Type.Reference)
+
+	assert.Len(t, renames, 3)
+
+	// the original definition is untouched
+	assert.Equal(t, "my-entity", api.Entities[0].Name)
+	assert.Equal(t, "get-thing", api.Services[0].Endpoints[0].Name)
+}
+
+func TestSanitizeAPI_RenamedPropertiesKeepWireNames(t *testing.T) {
+	api := types.APIDefinition{
+		Naming: types.NamingStrategy_SNAKE,
+		Entities: []types.EntitySpec{
+			{
+				Name: "Person",
+				Properties: map[string]types.PropertySpec{
+					"class":  {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+					"class_": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+				},
+			},
+		},
+	}
+
+	sanitized, renames := SanitizeAPI(api, Java)
+
+	properties := sanitized.Entities[0].Properties
+	assert.Len(t, properties, 2)
+	assert.Equal(t, "class", properties["class_2"].WireName)
+	assert.Empty(t, properties["class_"].WireName)
+	assert.Equal(t, []Rename{{Subject: "property", Scope: "entity 'Person'", Original: "class", Renamed: "class_2"}}, renames)
+}
+
+func TestSanitizeAPI_QuotedPropertiesAreNotRenamed(t *testing.T) {
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{
+				Name: "Headers",
+				Properties: map[string]types.PropertySpec{
+					"content-type": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+				},
+			},
+		},
+	}
+
+	sanitized, renames := SanitizeAPI(api, TypeScript)
+
+	assert.Contains(t, sanitized.Entities[0].Properties, "content-type")
+	assert.Empty(t, renames)
+}
@@ -0,0 +1,438 @@
+package jscodegen
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"slices"
+)
+
+// CodecDef defines the functions that convert an entity between its wire and in-memory representations. JSON has no
+// date type, so dates are sent as ISO-8601 strings and revived into Date objects when received. Properties are also
+// renamed when their wire names differ from their names in code
+type CodecDef struct {
+	EntityName     string             // name of the entity being converted
+	ReviverName    string             // name of the function that revives a received entity
+	SerializerName string             // name of the function that serializes an entity to send
+	ReviveParam    string             // optional function that revives the argument of a parameterised entity
+	SerializeParam string             // optional function that serializes the argument of a parameterised entity
+	Revived        []CodecPropertyDef // every property of the revived entity, sorted by name
+	Serialized     []CodecPropertyDef // every property of the serialized entity, sorted by name
+}
+
+// CodecPropertyDef defines how a single property is converted
+type CodecPropertyDef struct {
+	Key        string // object key of the converted property
+	Expression string // expression that converts the property
+}
+
+// names of the functions that convert the argument of a parameterised entity
+const (
+	reviveParam    = "reviveParam"
+	serializeParam = "serializeParam"
+)
+
+// reviverName gets the name of the function that revives the named entity
+func reviverName(entityName string) string {
+	return fmt.Sprintf("revive%s", entityName)
+}
+
+// serializerName gets the name of the function that serializes the named entity
+func serializerName(entityName string) string {
+	return fmt.Sprintf("serialize%s", entityName)
+}
+
+// parameterisedEntities finds every entity that is used as a generic. Entities do not declare type parameters, so the
+// values of a generic entity that are typed as any are the ones holding its argument. The codecs of these entities take
+// a function that converts that argument
+func parameterisedEntities(api types.APIDefinition) map[string]bool {
+	entities := make(map[string]types.EntitySpec, len(api.Entities))
+	for _, entity := range api.Entities {
+		entities[entity.Name] = entity
+	}
+
+	parameterised := make(map[string]bool)
+	for _, dtype := range apiTypes(api) {
+		walkType(dtype, func(dtype types.DynamicType) {
+			entity, isEntity := entities[dtype.Reference]
+			if dtype.TypeID == types.TypeID_GENERIC && len(dtype.Inner) > 0 && isEntity && holdsAny(entity) {
+				parameterised[entity.Name] = true
+			}
+		})
+	}
+
+	return parameterised
+}
+
+// checkGenericArguments makes sure that every generic entity whose arguments need conversion can convert them. Codecs
+// only take a single argument, as there is no telling which values of an entity hold which of several arguments
+func checkGenericArguments(api types.APIDefinition, converted, parameterised map[string]bool) error {
+	var err error
+	for _, dtype := range apiTypes(api) {
+		walkType(dtype, func(dtype types.DynamicType) {
+			if err != nil || !parameterised[dtype.Reference] || len(dtype.Inner) < 2 {
+				return
+			}
+
+			for _, inner := range dtype.Inner {
+				if needsConversion(inner, converted) {
+					err = fmt.Errorf("the arguments of generic entity '%s' cannot be converted, as it takes more than one", dtype.Reference)
+					return
+				}
+			}
+		})
+	}
+
+	return err
+}
+
+// convertedEntities finds every entity that has to be converted when it is sent or received. This is any entity that
+// holds dates or renamed properties, either directly or through the entities it references, as well as every
+// parameterised entity
+func convertedEntities(entities []types.EntitySpec, naming types.NamingStrategy, parameterised map[string]bool) map[string]bool {
+	converted := maps.Clone(parameterised)
+	if converted == nil {
+		converted = make(map[string]bool)
+	}
+
+	// keep propagating until no more entities are found, as entities may reference each other in any order
+	for changed := true; changed; {
+		changed = false
+		for _, entity := range entities {
+			if converted[entity.Name] {
+				continue
+			}
+
+			for propName, propSpec := range entity.Properties {
+				renamed := codeName(propName, naming) != propSpec.ResolveWireName(propName, naming)
+				if renamed || needsConversion(propSpec.Type, converted) {
+					converted[entity.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return converted
+}
+
+// needsConversion checks if values of a type have to be converted, given the set of entities known to need conversion.
+// This matches the types that codecs convert
+func needsConversion(dtype types.DynamicType, converted map[string]bool) bool {
+	switch {
+	case dtype.IsTemporal():
+		return true
+	case dtype.TypeID == types.TypeID_ARRAY && len(dtype.Inner) > 0:
+		return needsConversion(dtype.ArrayElementTp(), converted)
+	case dtype.TypeID == types.TypeID_GENERIC && isCollection(dtype):
+		return needsConversion(dtype.Inner[len(dtype.Inner)-1], converted)
+	case dtype.TypeID == types.TypeID_USER || dtype.TypeID == types.TypeID_GENERIC:
+		return converted[dtype.Reference]
+	default:
+		return false
+	}
+}
+
+// isCollection checks if a generic is sent as a plain JSON array or object, whose elements are converted in place.
+// Arrays and sets are sent as arrays, and records as objects whose values are converted
+func isCollection(dtype types.DynamicType) bool {
+	switch dtype.Reference {
+	case "Array", "Set":
+		return len(dtype.Inner) == 1
+	case "Record":
+		return len(dtype.Inner) == 2
+	default:
+		return false
+	}
+}
+
+// holdsAny checks if any value of an entity is typed as any
+func holdsAny(entity types.EntitySpec) bool {
+	for _, propSpec := range entity.Properties {
+		found := false
+		walkType(propSpec.Type, func(dtype types.DynamicType) {
+			found = found || dtype.TypeID == types.TypeID_ANY
+		})
+
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// apiTypes gets the type of every property and request value of an API
+func apiTypes(api types.APIDefinition) []types.DynamicType {
+	var dtypes []types.DynamicType
+	for _, entity := range api.Entities {
+		for _, propSpec := range entity.Properties {
+			dtypes = append(dtypes, propSpec.Type)
+		}
+	}
+
+	for _, service := range api.Services {
+		for _, endpoint := range service.Endpoints {
+			dtypes = append(dtypes, endpoint.RequestBody.Type, endpoint.ResponseBody.Type)
+			for _, value := range endpoint.PathVariables {
+				dtypes = append(dtypes, value.Type)
+			}
+			for _, value := range endpoint.QueryVariables {
+				dtypes = append(dtypes, value.Type)
+			}
+		}
+	}
+
+	return dtypes
+}
+
+// checkElementTypes makes sure that every array of an API declares the type of its elements
+func checkElementTypes(api types.APIDefinition) error {
+	for _, entity := range api.Entities {
+		for _, propName := range slices.Sorted(maps.Keys(entity.Properties)) {
+			if err := checkElementType(entity.Properties[propName].Type); err != nil {
+				return fmt.Errorf("invalid type of property '%s' of entity '%s': %w", propName, entity.Name, err)
+			}
+		}
+	}
+
+	for _, service := range api.Services {
+		for _, endpoint := range service.Endpoints {
+			values := map[string]types.RequestValue{"request body": endpoint.RequestBody, "response body": endpoint.ResponseBody}
+			for name, value := range endpoint.PathVariables {
+				values[fmt.Sprintf("path variable '%s'", name)] = value
+			}
+			for name, value := range endpoint.QueryVariables {
+				values[fmt.Sprintf("query variable '%s'", name)] = value
+			}
+
+			for _, what := range slices.Sorted(maps.Keys(values)) {
+				if err := checkElementType(values[what].Type); err != nil {
+					return fmt.Errorf("invalid type of %s of endpoint '%s.%s': %w", what, service.Name, endpoint.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkElementType makes sure that every array within a type declares the type of its elements
+func checkElementType(dtype types.DynamicType) error {
+	var err error
+	walkType(dtype, func(dtype types.DynamicType) {
+		if err == nil && dtype.TypeID == types.TypeID_ARRAY && len(dtype.Inner) == 0 {
+			err = fmt.Errorf("array type does not have an element type")
+		}
+	})
+
+	return err
+}
+
+// walkType visits a type and every type nested in it
+func walkType(dtype types.DynamicType, visit func(dtype types.DynamicType)) {
+	visit(dtype)
+	for _, inner := range dtype.Inner {
+		walkType(inner, visit)
+	}
+}
+
+// codecMapper creates expressions that convert values between their wire and in-memory representations. Every
+// entity whose codec an expression calls is recorded so that it can be imported
+type codecMapper struct {
+	converted      map[string]bool
+	parameterised  map[string]bool
+	referenced     map[string]map[string]bool // entity name -> codec functions used
+	reviveParam    string                     // optional function that revives values typed as any
+	serializeParam string                     // optional function that serializes values typed as any
+}
+
+func newCodecMapper(converted, parameterised map[string]bool) *codecMapper {
+	return &codecMapper{
+		converted:     converted,
+		parameterised: parameterised,
+		referenced:    make(map[string]map[string]bool),
+	}
+}
+
+// Revive creates an expression that revives a received value. Returns false if the value does not need reviving
+func (mapper *codecMapper) Revive(dtype types.DynamicType, accessor string) (string, bool) {
+	expression, ok := mapper.revive(dtype, accessor, 0)
+	if !ok {
+		return "", false
+	}
+
+	return nullSafe(accessor, expression), true
+}
+
+// Serialize creates an expression that serializes a value to send. Returns false if the value does not need
+// serializing
+func (mapper *codecMapper) Serialize(dtype types.DynamicType, accessor string) (string, bool) {
+	expression, ok := mapper.serialize(dtype, accessor, 0)
+	if !ok {
+		return "", false
+	}
+
+	return nullSafe(accessor, expression), true
+}
+
+func (mapper *codecMapper) revive(dtype types.DynamicType, accessor string, depth int) (string, bool) {
+	switch {
+	case dtype.IsTemporal():
+		return fmt.Sprintf("new Date(%s)", accessor), true
+	case dtype.TypeID == types.TypeID_ANY && len(mapper.reviveParam) > 0:
+		return fmt.Sprintf("%s(%s)", mapper.reviveParam, accessor), true
+	case dtype.TypeID == types.TypeID_ARRAY || (dtype.TypeID == types.TypeID_GENERIC && isCollection(dtype)):
+		return mapper.collection(dtype, accessor, depth, false, mapper.revive)
+	case (dtype.TypeID == types.TypeID_USER || dtype.TypeID == types.TypeID_GENERIC) && mapper.converted[dtype.Reference]:
+		return mapper.entity(dtype, accessor, depth, reviverName(dtype.Reference), mapper.revive), true
+	default:
+		return "", false
+	}
+}
+
+func (mapper *codecMapper) serialize(dtype types.DynamicType, accessor string, depth int) (string, bool) {
+	switch {
+	case dtype.TypeID == types.TypeID_TIMESTAMP:
+		return fmt.Sprintf("%s.toISOString()", accessor), true
+	case dtype.TypeID == types.TypeID_DATE:
+		// dates are revived at midnight UTC, so take the calendar date in UTC as well
+		return fmt.Sprintf("%s.toISOString().slice(0, 10)", accessor), true
+	case dtype.TypeID == types.TypeID_ANY && len(mapper.serializeParam) > 0:
+		return fmt.Sprintf("%s(%s)", mapper.serializeParam, accessor), true
+	case dtype.TypeID == types.TypeID_ARRAY || (dtype.TypeID == types.TypeID_GENERIC && isCollection(dtype)):
+		return mapper.collection(dtype, accessor, depth, true, mapper.serialize)
+	case (dtype.TypeID == types.TypeID_USER || dtype.TypeID == types.TypeID_GENERIC) && mapper.converted[dtype.Reference]:
+		return mapper.entity(dtype, accessor, depth, serializerName(dtype.Reference), mapper.serialize), true
+	default:
+		return "", false
+	}
+}
+
+// collection converts the elements of an array or set, or the values of a record. Sets are received as arrays, but
+// have to be turned into arrays before they are sent
+func (mapper *codecMapper) collection(dtype types.DynamicType, accessor string, depth int, serializing bool, convert func(types.DynamicType, string, int) (string, bool)) (string, bool) {
+	if len(dtype.Inner) == 0 {
+		return "", false
+	}
+
+	item := fmt.Sprintf("item%d", depth)
+	inner, ok := convert(dtype.Inner[len(dtype.Inner)-1], item, depth+1)
+	if !ok {
+		return "", false
+	}
+
+	switch {
+	case dtype.Reference == "Record":
+		key := fmt.Sprintf("key%d", depth)
+		return fmt.Sprintf("Object.fromEntries(Object.entries(%s).map(([%s, %s]: [string, any]) => [%s, %s]))", accessor, key, item, key, inner), true
+	case dtype.Reference == "Set" && serializing:
+		return fmt.Sprintf("Array.from(%s, (%s: any) => %s)", accessor, item, inner), true
+	default:
+		return fmt.Sprintf("%s.map((%s: any) => %s)", accessor, item, inner), true
+	}
+}
+
+// entity converts a value with the codec of its entity. The argument of a parameterised entity is converted by a
+// function that is passed to its codec
+func (mapper *codecMapper) entity(dtype types.DynamicType, accessor string, depth int, functionName string, convert func(types.DynamicType, string, int) (string, bool)) string {
+	function := mapper.use(dtype.Reference, functionName)
+	if dtype.TypeID != types.TypeID_GENERIC || !mapper.parameterised[dtype.Reference] || len(dtype.Inner) != 1 {
+		return fmt.Sprintf("%s(%s)", function, accessor)
+	}
+
+	item := fmt.Sprintf("item%d", depth)
+	inner, ok := convert(dtype.Inner[0], item, depth+1)
+	if !ok {
+		return fmt.Sprintf("%s(%s)", function, accessor)
+	}
+
+	return fmt.Sprintf("%s(%s, (%s: any) => %s)", function, accessor, item, nullSafe(item, inner))
+}
+
+// use records that a codec function of an entity is used
+func (mapper *codecMapper) use(entityName, functionName string) string {
+	if _, exists := mapper.referenced[entityName]; !exists {
+		mapper.referenced[entityName] = make(map[string]bool)
+	}
+
+	mapper.referenced[entityName][functionName] = true
+	return functionName
+}
+
+// Imports gets imports for every codec function used by created expressions, except for those of the given entity
+func (mapper *codecMapper) Imports(except string, resolver imports.ImportManager) ([]imports.GenericImport, error) {
+	var codecImports []imports.GenericImport
+	for _, referenced := range slices.Sorted(maps.Keys(mapper.referenced)) {
+		if referenced == except {
+			continue
+		}
+
+		entityImport, err := resolver.GetImportForType(referenced)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import codec for '%s': %w", referenced, err)
+		}
+
+		codecImports = append(codecImports, &TSImport{
+			File:          entityImport.Provider(),
+			ProvidedTypes: slices.Sorted(maps.Keys(mapper.referenced[referenced])),
+		})
+	}
+
+	return codecImports, nil
+}
+
+// nullSafe guards a conversion so that missing values are passed through untouched
+func nullSafe(accessor, expression string) string {
+	return fmt.Sprintf("%s == null ? %s : %s", accessor, accessor, expression)
+}
+
+// createCodec creates the codec of an entity, along with the imports it needs. Returns nil if the entity does not
+// need to be converted
+func createCodec(entity types.EntitySpec, converted, parameterised map[string]bool, naming types.NamingStrategy, resolver imports.ImportManager) (*CodecDef, []imports.GenericImport, error) {
+	if !converted[entity.Name] {
+		return nil, nil, nil
+	}
+
+	mapper := newCodecMapper(converted, parameterised)
+	codec := &CodecDef{
+		EntityName:     entity.Name,
+		ReviverName:    reviverName(entity.Name),
+		SerializerName: serializerName(entity.Name),
+	}
+
+	// the values of a parameterised entity that are typed as any are converted by the functions its codec is passed
+	if parameterised[entity.Name] {
+		codec.ReviveParam, mapper.reviveParam = reviveParam, reviveParam
+		codec.SerializeParam, mapper.serializeParam = serializeParam, serializeParam
+	}
+
+	for _, propName := range slices.Sorted(maps.Keys(entity.Properties)) {
+		propSpec := entity.Properties[propName]
+		propCodeName := codeName(propName, naming)
+		propWireName := propSpec.ResolveWireName(propName, naming)
+
+		wireAccessor := propertyAccess("value", propWireName)
+		revived, ok := mapper.Revive(propSpec.Type, wireAccessor)
+		if !ok {
+			revived = wireAccessor
+		}
+		codec.Revived = append(codec.Revived, CodecPropertyDef{Key: objectKey(propCodeName), Expression: revived})
+
+		codeAccessor := propertyAccess("value", propCodeName)
+		serialized, ok := mapper.Serialize(propSpec.Type, codeAccessor)
+		if !ok {
+			serialized = codeAccessor
+		}
+		codec.Serialized = append(codec.Serialized, CodecPropertyDef{Key: objectKey(propWireName), Expression: serialized})
+	}
+
+	codecImports, err := mapper.Imports(entity.Name, resolver)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return codec, codecImports, nil
+}
@@ -0,0 +1,370 @@
+package jscodegen
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var timestampType = types.DynamicType{TypeID: types.TypeID_TIMESTAMP}
+
+func TestConvertedEntities_FollowsReferences(t *testing.T) {
+	entities := []types.EntitySpec{
+		{
+			Name: "Team",
+			Properties: map[string]types.PropertySpec{
+				"members": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}},
+			},
+		},
+		{
+			Name: "Person",
+			Properties: map[string]types.PropertySpec{
+				"born": {Type: timestampType},
+			},
+		},
+		{
+			Name: "Tag",
+			Properties: map[string]types.PropertySpec{
+				"label": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+			},
+		},
+	}
+
+	converted := convertedEntities(entities, types.NamingStrategy_PRESERVE, nil)
+
+	assert.Equal(t, map[string]bool{"Person": true, "Team": true}, converted)
+}
+
+func TestCreateCodec_ConvertsDates(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./person.model.gen", "Person")
+
+	entity := types.EntitySpec{
+		Name: "Event",
+		Properties: map[string]types.PropertySpec{
+			"day":    {Type: types.DynamicType{TypeID: types.TypeID_DATE}},
+			"host":   {Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}},
+			"name":   {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+			"starts": {Type: timestampType},
+		},
+	}
+
+	codec, codecImports, err := createCodec(entity, map[string]bool{"Event": true, "Person": true}, nil, types.NamingStrategy_PRESERVE, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: "day", Expression: "value.day == null ? value.day : new Date(value.day)"},
+		{Key: "host", Expression: "value.host == null ? value.host : revivePerson(value.host)"},
+		{Key: "name", Expression: "value.name"},
+		{Key: "starts", Expression: "value.starts == null ? value.starts : new Date(value.starts)"},
+	}, codec.Revived)
+
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: "day", Expression: "value.day == null ? value.day : value.day.toISOString().slice(0, 10)"},
+		{Key: "host", Expression: "value.host == null ? value.host : serializePerson(value.host)"},
+		{Key: "name", Expression: "value.name"},
+		{Key: "starts", Expression: "value.starts == null ? value.starts : value.starts.toISOString()"},
+	}, codec.Serialized)
+
+	assert.Len(t, codecImports, 1)
+	assert.Equal(t, []string{"revivePerson", "serializePerson"}, codecImports[0].ProvidedEntities())
+}
+
+func TestCreateCodec_SkipsEntitiesWithoutDates(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Tag",
+		Properties: map[string]types.PropertySpec{
+			"label": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+		},
+	}
+
+	codec, codecImports, err := createCodec(entity, nil, nil, types.NamingStrategy_PRESERVE, &importManager)
+	assert.NoError(t, err)
+	assert.Nil(t, codec)
+	assert.Empty(t, codecImports)
+}
+
+func TestConvertedEntities_IncludesRenamedProperties(t *testing.T) {
+	entities := []types.EntitySpec{
+		{
+			Name: "Person",
+			Properties: map[string]types.PropertySpec{
+				"firstName": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+			},
+		},
+		{
+			Name: "Tag",
+			Properties: map[string]types.PropertySpec{
+				"label": {Type: types.DynamicType{TypeID: types.TypeID_STRING}, WireName: "tag_label"},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]bool{"Tag": true}, convertedEntities(entities, types.NamingStrategy_PRESERVE, nil))
+	assert.Equal(t, map[string]bool{"Person": true, "Tag": true}, convertedEntities(entities, types.NamingStrategy_SNAKE, nil))
+}
+
+func TestCreateCodec_TranslatesWireNames(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Person",
+		Properties: map[string]types.PropertySpec{
+			"first_name": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+			"lastName":   {Type: types.DynamicType{TypeID: types.TypeID_STRING}, WireName: "surname"},
+		},
+	}
+
+	codec, _, err := createCodec(entity, map[string]bool{"Person": true}, nil, types.NamingStrategy_KEBAB, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: "firstName", Expression: `value["first-name"]`},
+		{Key: "lastName", Expression: "value.surname"},
+	}, codec.Revived)
+
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: `"first-name"`, Expression: "value.firstName"},
+		{Key: "surname", Expression: "value.lastName"},
+	}, codec.Serialized)
+}
+
+func TestNGServiceGenerator_PrepareAPI_RejectsUnknownNaming(t *testing.T) {
+	err := NewNGServiceGenerator().PrepareAPI(types.APIDefinition{Naming: "shouting"})
+	assert.ErrorContains(t, err, "unknown naming strategy 'shouting'")
+}
+
+func TestNGServiceGenerator_GenerateEntity_UsesCodeNames(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Person",
+		Properties: map[string]types.PropertySpec{
+			"first_name": {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+		},
+	}
+
+	generator := NewNGServiceGenerator()
+	assert.NoError(t, generator.PrepareAPI(types.APIDefinition{Naming: types.NamingStrategy_SNAKE, Entities: []types.EntitySpec{entity}}))
+
+	var output bytes.Buffer
+	err := generator.GenerateEntity(&output, entity, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "firstName: string;")
+	assert.Contains(t, output.String(), "firstName: value.first_name,")
+	assert.Contains(t, output.String(), "first_name: value.firstName,")
+}
+
+func TestNGServiceGenerator_GenerateService_ConvertsDates(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./person.model.gen", "Person")
+	importManager.RegisterType("./api-config.config.gen", "APIConfig")
+
+	personType := types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{Name: "Person", Properties: map[string]types.PropertySpec{"born": {Type: timestampType}}},
+		},
+	}
+
+	service := types.ServiceDefinition{
+		Name: "Person",
+		Endpoints: []types.APIEndpoint{
+			{
+				Name:     "create",
+				Endpoint: "/people/{{day}}",
+				Method:   "POST",
+				PathVariables: map[string]types.RequestValue{
+					"day": {Type: types.DynamicType{TypeID: types.TypeID_DATE}, Required: true},
+				},
+				QueryVariables: map[string]types.RequestValue{
+					"since": {Type: timestampType},
+				},
+				RequestBody:  types.RequestValue{Type: personType, Required: true},
+				ResponseBody: types.RequestValue{Type: personType},
+			},
+		},
+	}
+
+	generator := NewNGServiceGenerator()
+	assert.NoError(t, generator.PrepareAPI(api))
+
+	var output bytes.Buffer
+	err := generator.GenerateService(&output, service, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), `import { HttpClient, HttpParams } from "@angular/common/http";`)
+	assert.Contains(t, output.String(), "import { Person,revivePerson,serializePerson, } from './person.model.gen';")
+	assert.Contains(t, output.String(), "since?: Date;")
+	assert.Contains(t, output.String(), `params = params.set("since", input.since.toISOString());`)
+	assert.Contains(t, output.String(), "/people/${input.day.toISOString().slice(0, 10)}`, input.body == null ? input.body : serializePerson(input.body), { params })")
+	assert.Contains(t, output.String(), ".pipe(map(response => response == null ? response : revivePerson(response)));")
+}
+
+func TestCreateCodec_ConvertsDatesInGenericArguments(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./page.model.gen", "Page")
+	importManager.RegisterType("./person.model.gen", "Person")
+
+	personType := types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+	entity := types.EntitySpec{
+		Name: "Event",
+		Properties: map[string]types.PropertySpec{
+			"attendees": {Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Set", Inner: []types.DynamicType{personType}}},
+			"page":      {Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{personType}}},
+			"schedule":  {Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Record", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}, timestampType}}},
+		},
+	}
+
+	converted := map[string]bool{"Event": true, "Page": true, "Person": true}
+	codec, codecImports, err := createCodec(entity, converted, map[string]bool{"Page": true}, types.NamingStrategy_PRESERVE, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: "attendees", Expression: "value.attendees == null ? value.attendees : value.attendees.map((item0: any) => revivePerson(item0))"},
+		{Key: "page", Expression: "value.page == null ? value.page : revivePage(value.page, (item0: any) => item0 == null ? item0 : revivePerson(item0))"},
+		{Key: "schedule", Expression: "value.schedule == null ? value.schedule : Object.fromEntries(Object.entries(value.schedule).map(([key0, item0]: [string, any]) => [key0, new Date(item0)]))"},
+	}, codec.Revived)
+
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: "attendees", Expression: "value.attendees == null ? value.attendees : Array.from(value.attendees, (item0: any) => serializePerson(item0))"},
+		{Key: "page", Expression: "value.page == null ? value.page : serializePage(value.page, (item0: any) => item0 == null ? item0 : serializePerson(item0))"},
+		{Key: "schedule", Expression: "value.schedule == null ? value.schedule : Object.fromEntries(Object.entries(value.schedule).map(([key0, item0]: [string, any]) => [key0, item0.toISOString()]))"},
+	}, codec.Serialized)
+
+	assert.Len(t, codecImports, 2)
+}
+
+func TestCreateCodec_ConvertsArgumentOfParameterisedEntity(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Page",
+		Properties: map[string]types.PropertySpec{
+			"content": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}},
+			"total":   {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}},
+		},
+	}
+
+	codec, _, err := createCodec(entity, map[string]bool{"Page": true}, map[string]bool{"Page": true}, types.NamingStrategy_PRESERVE, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "reviveParam", codec.ReviveParam)
+	assert.Equal(t, "serializeParam", codec.SerializeParam)
+	assert.Equal(t, []CodecPropertyDef{
+		{Key: "content", Expression: "value.content == null ? value.content : value.content.map((item0: any) => reviveParam(item0))"},
+		{Key: "total", Expression: "value.total"},
+	}, codec.Revived)
+}
+
+func TestParameterisedEntities_FindsEntitiesUsedAsGenerics(t *testing.T) {
+	personType := types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{Name: "Page", Properties: map[string]types.PropertySpec{"content": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}}}},
+			{Name: "Person", Properties: map[string]types.PropertySpec{"born": {Type: timestampType}}},
+			{Name: "Wrapper", Properties: map[string]types.PropertySpec{"label": {Type: types.DynamicType{TypeID: types.TypeID_STRING}}}},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Name: "People",
+				Endpoints: []types.APIEndpoint{
+					{Name: "list", ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{personType}}}},
+					{Name: "wrapped", ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Wrapper", Inner: []types.DynamicType{personType}}}},
+				},
+			},
+		},
+	}
+
+	parameterised := parameterisedEntities(api)
+	assert.Equal(t, map[string]bool{"Page": true}, parameterised)
+	assert.Equal(t, map[string]bool{"Page": true, "Person": true}, convertedEntities(api.Entities, types.NamingStrategy_PRESERVE, parameterised))
+}
+
+func TestNGServiceGenerator_PrepareAPI_RejectsSeveralConvertedGenericArguments(t *testing.T) {
+	personType := types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{Name: "Pair", Properties: map[string]types.PropertySpec{"left": {Type: types.DynamicType{TypeID: types.TypeID_ANY}}}},
+			{Name: "Person", Properties: map[string]types.PropertySpec{"born": {Type: timestampType}}},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Name: "People",
+				Endpoints: []types.APIEndpoint{
+					{Name: "pair", ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Pair", Inner: []types.DynamicType{personType, personType}}}},
+				},
+			},
+		},
+	}
+
+	err := NewNGServiceGenerator().PrepareAPI(api)
+	assert.ErrorContains(t, err, "the arguments of generic entity 'Pair' cannot be converted, as it takes more than one")
+}
+
+func TestNGServiceGenerator_GenerateService_RevivesGenericEntities(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./page.model.gen", "Page")
+	importManager.RegisterType("./person.model.gen", "Person")
+	importManager.RegisterType("./api-config.config.gen", "APIConfig")
+
+	pageType := types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}
+	service := types.ServiceDefinition{
+		Name: "People",
+		Endpoints: []types.APIEndpoint{
+			{Name: "list", Endpoint: "/people", Method: "GET", RequestBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}}, ResponseBody: types.RequestValue{Type: pageType}},
+		},
+	}
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{Name: "Page", Properties: map[string]types.PropertySpec{"content": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}}}},
+			{Name: "Person", Properties: map[string]types.PropertySpec{"born": {Type: timestampType}}},
+		},
+		Services: []types.ServiceDefinition{service},
+	}
+
+	generator := NewNGServiceGenerator()
+	assert.NoError(t, generator.PrepareAPI(api))
+
+	var output bytes.Buffer
+	err := generator.GenerateService(&output, service, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), ".pipe(map(response => response == null ? response : revivePage(response, (item0: any) => item0 == null ? item0 : revivePerson(item0))));")
+
+	output.Reset()
+	err = generator.GenerateEntity(&output, api.Entities[0], &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "export function revivePage(value: any, reviveParam: (value: any) => any = (value: any) => value): Page {")
+	assert.Contains(t, output.String(), "content: value.content == null ? value.content : value.content.map((item0: any) => reviveParam(item0)),")
+}
+
+func TestNGServiceGenerator_PrepareAPI_RejectsArraysWithoutElementTypes(t *testing.T) {
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{Name: "Team", Properties: map[string]types.PropertySpec{"members": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY}}}},
+		},
+	}
+
+	err := NewNGServiceGenerator().PrepareAPI(api)
+	assert.EqualError(t, err, "invalid type of property 'members' of entity 'Team': array type does not have an element type")
+
+	api = types.APIDefinition{
+		Services: []types.ServiceDefinition{
+			{
+				Name: "Teams",
+				Endpoints: []types.APIEndpoint{
+					{Name: "find", QueryVariables: map[string]types.RequestValue{"ids": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY}}}},
+				},
+			},
+		},
+	}
+
+	err = NewNGServiceGenerator().PrepareAPI(api)
+	assert.EqualError(t, err, "invalid type of query variable 'ids' of endpoint 'Teams.find': array type does not have an element type")
+}
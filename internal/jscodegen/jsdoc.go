@@ -0,0 +1,64 @@
+package jscodegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"strings"
+)
+
+// FormatJSDoc renders documentation as a JSDoc comment. The comment is followed by a newline and the given indent, so
+// it can be placed directly in front of the declaration it documents. Empty documentation renders as nothing.
+func FormatJSDoc(doc types.Documentation, indent string) (string, error) {
+	if doc.IsEmpty() {
+		return "", nil
+	}
+
+	var lines []string
+	if len(doc.Summary) > 0 {
+		lines = append(lines, splitLines(doc.Summary)...)
+	}
+
+	if len(doc.Description) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, splitLines(doc.Description)...)
+	}
+
+	if doc.Example != nil {
+		example, err := json.MarshalIndent(doc.Example, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to format example: %w", err)
+		}
+
+		lines = append(lines, "@example")
+		lines = append(lines, splitLines(string(example))...)
+	}
+
+	if doc.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString("/**\n")
+	for _, line := range lines {
+		builder.WriteString(indent)
+		builder.WriteString(strings.TrimRight(" * "+escapeJSDoc(line), " "))
+		builder.WriteString("\n")
+	}
+	builder.WriteString(indent)
+	builder.WriteString(" */\n")
+	builder.WriteString(indent)
+
+	return builder.String(), nil
+}
+
+func splitLines(text string) []string {
+	return strings.Split(strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")), "\n")
+}
+
+// escapeJSDoc makes sure text cannot terminate the comment it is placed in
+func escapeJSDoc(text string) string {
+	return strings.ReplaceAll(text, "*/", "*\\/")
+}
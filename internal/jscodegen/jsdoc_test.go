@@ -0,0 +1,71 @@
+package jscodegen
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatJSDoc_EmptyDocumentation(t *testing.T) {
+	result, err := FormatJSDoc(types.Documentation{}, "    ")
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestFormatJSDoc_FormatsAllFields(t *testing.T) {
+	doc := types.Documentation{
+		Summary:     "Gets a person",
+		Description: "Looks up a person\nby their id",
+		Example:     map[string]any{"id": "abc"},
+		Deprecated:  true,
+	}
+
+	result, err := FormatJSDoc(doc, "  ")
+	assert.NoError(t, err)
+
+	expected := "/**\n" +
+		"   * Gets a person\n" +
+		"   *\n" +
+		"   * Looks up a person\n" +
+		"   * by their id\n" +
+		"   * @example\n" +
+		"   * {\n" +
+		"   *   \"id\": \"abc\"\n" +
+		"   * }\n" +
+		"   * @deprecated\n" +
+		"   */\n" +
+		"  "
+
+	assert.Equal(t, expected, result)
+}
+
+func TestFormatJSDoc_EscapesCommentTerminator(t *testing.T) {
+	result, err := FormatJSDoc(types.Documentation{Summary: "ends */ early"}, "")
+	assert.NoError(t, err)
+	assert.Contains(t, result, `ends *\/ early`)
+}
+
+func TestNGServiceGenerator_GenerateEntity_RendersDocumentation(t *testing.T) {
+	generator := NewNGServiceGenerator()
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Documentation: types.Documentation{Summary: "A person"},
+		Name:          "Person",
+		Properties: map[string]types.PropertySpec{
+			"name": {
+				Documentation: types.Documentation{Deprecated: true},
+				Type:          types.DynamicType{TypeID: types.TypeID_STRING},
+				Required:      true,
+			},
+		},
+	}
+
+	var output bytes.Buffer
+	err := generator.GenerateEntity(&output, entity, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "/**\n * A person\n */\nexport interface Person {")
+	assert.Contains(t, output.String(), "    /**\n     * @deprecated\n     */\n    name: string;")
+}
@@ -0,0 +1,72 @@
+package jscodegen
+
+import (
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// codeName gets the name a property has in generated code. Generated code is camelCase, so names are converted
+// whenever the API uses a naming strategy other than preserve
+func codeName(name string, naming types.NamingStrategy) string {
+	if naming.IsPreserve() {
+		return name
+	}
+
+	return strcase.ToLowerCamel(name)
+}
+
+// codeEntity renames the properties of an entity to their code names. Fails if several properties end up with the same
+// wire name or code name, as generated code could only hold one of them
+func codeEntity(spec types.EntitySpec, naming types.NamingStrategy) (types.EntitySpec, error) {
+	sentAs := make(map[string]string, len(spec.Properties))
+	for _, name := range slices.Sorted(maps.Keys(spec.Properties)) {
+		wireName := spec.Properties[name].ResolveWireName(name, naming)
+		if existing, exists := sentAs[wireName]; exists {
+			return types.EntitySpec{}, fmt.Errorf("properties '%s' and '%s' of entity '%s' are both sent as '%s'", existing, name, spec.Name, wireName)
+		}
+		sentAs[wireName] = name
+	}
+
+	if naming.IsPreserve() {
+		return spec, nil
+	}
+
+	renamed := spec
+	renamed.Properties = make(map[string]types.PropertySpec, len(spec.Properties))
+	renamedFrom := make(map[string]string, len(spec.Properties))
+	for _, name := range slices.Sorted(maps.Keys(spec.Properties)) {
+		propCodeName := codeName(name, naming)
+		if existing, exists := renamedFrom[propCodeName]; exists {
+			return types.EntitySpec{}, fmt.Errorf("properties '%s' and '%s' of entity '%s' are both named '%s' in code", existing, name, spec.Name, propCodeName)
+		}
+
+		renamedFrom[propCodeName] = name
+		renamed.Properties[propCodeName] = spec.Properties[name]
+	}
+
+	return renamed, nil
+}
+
+// objectKey formats a name as the key of an object literal, quoting it if it is not a valid identifier
+func objectKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+
+	return jsString(name)
+}
+
+// propertyAccess formats an expression that reads a property of an object
+func propertyAccess(object, name string) string {
+	if identifierPattern.MatchString(name) {
+		return object + "." + name
+	}
+
+	return object + "[" + jsString(name) + "]"
+}
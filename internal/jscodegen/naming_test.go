@@ -0,0 +1,75 @@
+package jscodegen
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestObjectKey_QuotesInvalidIdentifiers(t *testing.T) {
+	assert.Equal(t, "delete", objectKey("delete"))
+	assert.Equal(t, `"content-type"`, objectKey("content-type"))
+}
+
+func TestPropertyAccess_UsesBracketsForInvalidIdentifiers(t *testing.T) {
+	assert.Equal(t, "value.class", propertyAccess("value", "class"))
+	assert.Equal(t, `value["content-type"]`, propertyAccess("value", "content-type"))
+}
+
+func TestNGServiceGenerator_GenerateEntity_QuotesPropertyNames(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Headers",
+		Properties: map[string]types.PropertySpec{
+			"content-type": {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+		},
+	}
+
+	var output bytes.Buffer
+	err := NewNGServiceGeneratorWithOptions(NGOptions{Validators: true}).GenerateEntity(&output, entity, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), `"content-type": string;`)
+	assert.Contains(t, output.String(), `if (value["content-type"] === undefined || value["content-type"] === null)`)
+}
+
+func TestCodeEntity_RejectsPropertiesWithTheSameCodeName(t *testing.T) {
+	entity := types.EntitySpec{
+		Name: "A",
+		Properties: map[string]types.PropertySpec{
+			"first_name": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+			"firstName":  {Type: types.DynamicType{TypeID: types.TypeID_STRING}, WireName: "given_name"},
+		},
+	}
+
+	_, err := codeEntity(entity, types.NamingStrategy_SNAKE)
+	assert.EqualError(t, err, "properties 'firstName' and 'first_name' of entity 'A' are both named 'firstName' in code")
+}
+
+func TestCodeEntity_RejectsPropertiesWithTheSameWireName(t *testing.T) {
+	entity := types.EntitySpec{
+		Name: "A",
+		Properties: map[string]types.PropertySpec{
+			"first_name": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+			"firstName":  {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+		},
+	}
+
+	_, err := codeEntity(entity, types.NamingStrategy_SNAKE)
+	assert.EqualError(t, err, "properties 'firstName' and 'first_name' of entity 'A' are both sent as 'first_name'")
+
+	var output bytes.Buffer
+	importManager := NewTSImportManager()
+	generator := NewNGServiceGenerator()
+	assert.NoError(t, generator.PrepareAPI(types.APIDefinition{Naming: types.NamingStrategy_SNAKE, Entities: []types.EntitySpec{entity}}))
+	err = generator.GenerateEntity(&output, entity, &importManager)
+	assert.ErrorContains(t, err, "are both sent as 'first_name'")
+
+	_, err = codeEntity(types.EntitySpec{Name: "B", Properties: map[string]types.PropertySpec{
+		"given":  {WireName: "name"},
+		"family": {WireName: "name"},
+	}}, types.NamingStrategy_PRESERVE)
+	assert.EqualError(t, err, "properties 'family' and 'given' of entity 'B' are both sent as 'name'")
+}
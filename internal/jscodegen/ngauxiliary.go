@@ -0,0 +1,190 @@
+package jscodegen
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"io"
+	"slices"
+	"strings"
+)
+
+//go:embed ng-index.tmpl
+var indexTemplateText string
+
+//go:embed ng-providers.tmpl
+var providersTemplateText string
+
+const (
+	indexOutputName     = "index.ts"
+	providersOutputName = "providers.gen.ts"
+
+	configProviderFunction = "provideAPIConfiguration"
+	configTokenName        = "API_CONFIG" // injection token of the API config, as its interface cannot be injected
+)
+
+// ProvidersDef defines the template for the environment providers function of an API
+type ProvidersDef struct {
+	APIName      string                  // the name of the API being provided
+	FunctionName string                  // the name of the providers function
+	Imports      []imports.GenericImport // imports used by the providers function
+}
+
+func (generator *NGServiceGenerator) AuxiliaryOutputs(api types.APIDefinition) []string {
+	auxOutputs := []string{providersOutputName, indexOutputName}
+	if generator.options.Package {
+		auxOutputs = append(auxOutputs, publicAPIOutputName, packageJsonOutputName, ngPackageJsonOutputName, tsconfigOutputName)
+	}
+
+	return auxOutputs
+}
+
+func (generator *NGServiceGenerator) GenerateAuxiliary(writer io.Writer, name string, api types.APIDefinition, generated []outputs.GeneratedOutput, resolver imports.ImportManager) error {
+	if generator.options.Package {
+		handled, err := generator.generatePackageOutput(writer, name, api)
+		if handled {
+			return err
+		}
+	}
+
+	switch name {
+	case providersOutputName:
+		providersDef, err := translateProviders(api, resolver)
+		if err != nil {
+			return fmt.Errorf("failed to translate providers: %w", err)
+		}
+
+		return generator.ngProvidersTemplate.Execute(writer, providersDef)
+
+	case indexOutputName:
+		exports, err := generator.createIndexExports(api, generated, resolver)
+		if err != nil {
+			return fmt.Errorf("failed to create index exports: %w", err)
+		}
+
+		return generator.ngIndexTemplate.Execute(writer, exports)
+
+	default:
+		return fmt.Errorf("unknown auxiliary output '%s'", name)
+	}
+}
+
+// providersFunctionName gets the name of the function that provides everything for the given API
+func providersFunctionName(api types.APIDefinition) string {
+	return fmt.Sprintf("provide%sApi", strcase.ToCamel(api.Name))
+}
+
+func translateProviders(api types.APIDefinition, resolver imports.ImportManager) (ProvidersDef, error) {
+	configImport, err := resolver.GetImportForType("APIConfig")
+	if err != nil {
+		return ProvidersDef{}, fmt.Errorf("failed to get api config import: %w", err)
+	}
+
+	return ProvidersDef{
+		APIName:      api.Name,
+		FunctionName: providersFunctionName(api),
+		Imports: []imports.GenericImport{
+			&TSImport{
+				File:          configImport.Provider(),
+				ProvidedTypes: append(configImport.ProvidedEntities(), configProviderFunction),
+			},
+		},
+	}, nil
+}
+
+// IndexExport is what the barrel file re-exports from one output. Types are re-exported with "export type", so that
+// the barrel compiles under isolatedModules
+type IndexExport struct {
+	File   string   // specifier of the output that is re-exported
+	Types  []string // names that only exist as types, such as interfaces
+	Values []string // names that exist at runtime, such as classes, functions and constants
+}
+
+// createIndexExports works out what the barrel file re-exports from each generated output. Fails if two outputs export
+// the same name, as the barrel file could only export one of them
+func (generator *NGServiceGenerator) createIndexExports(api types.APIDefinition, generated []outputs.GeneratedOutput, resolver imports.ImportManager) ([]IndexExport, error) {
+	var exports []IndexExport
+	exporters := make(map[string]string)
+	for _, output := range generated {
+		var typeNames, valueNames []string
+		switch output.Type {
+		case outputs.OutputType_CONFIG:
+			typeNames = []string{output.Name}
+			valueNames = []string{configTokenName, configProviderFunction}
+		case outputs.OutputType_MODEL:
+			typeNames = []string{output.Name}
+			valueNames = generator.entityValueExports(output.Name)
+		case outputs.OutputType_SERVICE:
+			valueNames = []string{serviceClassName(output.Name)}
+		case outputs.OutputType_AUXILIARY:
+			if output.Name == providersOutputName {
+				valueNames = []string{providersFunctionName(api)}
+			}
+		}
+
+		if len(typeNames) == 0 && len(valueNames) == 0 {
+			continue
+		}
+
+		exportPath := importPath(resolver, output.Location.Location())
+		for _, exportedName := range slices.Concat(typeNames, valueNames) {
+			if exporter, exists := exporters[exportedName]; exists {
+				return nil, fmt.Errorf("'%s' is exported by both '%s' and '%s'", exportedName, exporter, exportPath)
+			}
+			exporters[exportedName] = exportPath
+		}
+
+		exports = append(exports, IndexExport{
+			File:   exportPath,
+			Types:  typeNames,
+			Values: valueNames,
+		})
+	}
+
+	return exports, nil
+}
+
+// importPath gets the specifier that the output being generated uses to import the output at the given location. If
+// the resolver does not know where outputs are located, the output is imported relative to the root of the outputs
+func importPath(resolver imports.ImportManager, location string) string {
+	if located, ok := resolver.(imports.LocatedImportManager); ok {
+		return located.ImportPath(location)
+	}
+
+	return strings.TrimSuffix(outputs.RelativeLocation("", location), ".ts")
+}
+
+// serviceClassName gets the name of the class generated for the named service
+func serviceClassName(serviceName string) string {
+	return fmt.Sprintf("%sService", serviceName)
+}
+
+// entityExports gets every name exported from the model output of the named entity
+func (generator *NGServiceGenerator) entityExports(entityName string) []string {
+	return append([]string{entityName}, generator.entityValueExports(entityName)...)
+}
+
+// entityValueExports gets the names exported from the model output of the named entity that exist at runtime, such as
+// its revive and serialize functions, validator and schema
+func (generator *NGServiceGenerator) entityValueExports(entityName string) []string {
+	var exportedNames []string
+	if generator.converted[entityName] {
+		exportedNames = append(exportedNames, reviverName(entityName), serializerName(entityName))
+	}
+
+	if generator.options.Validators {
+		exportedNames = append(exportedNames, validatorFunctionName(entityName))
+	}
+
+	if generator.options.Zod {
+		exportedNames = append(exportedNames, schemaName(entityName))
+		if generator.parameterised[entityName] {
+			exportedNames = append(exportedNames, schemaFactoryName(entityName))
+		}
+	}
+
+	return exportedNames
+}
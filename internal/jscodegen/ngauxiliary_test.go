@@ -0,0 +1,84 @@
+package jscodegen
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreateIndexExports_ExportsEveryOutput(t *testing.T) {
+	api := types.APIDefinition{Name: "people"}
+	generated := []outputs.GeneratedOutput{
+		{Location: outputs.MemoryCompilerOutputLocation("api-config.config.gen.ts"), Type: outputs.OutputType_CONFIG, Name: "APIConfig"},
+		{Location: outputs.MemoryCompilerOutputLocation("person.model.gen.ts"), Type: outputs.OutputType_MODEL, Name: "Person"},
+		{Location: outputs.MemoryCompilerOutputLocation("person.service.gen.ts"), Type: outputs.OutputType_SERVICE, Name: "Person"},
+		{Location: outputs.MemoryCompilerOutputLocation(providersOutputName), Type: outputs.OutputType_AUXILIARY, Name: providersOutputName},
+	}
+
+	importManager := NewTSImportManager()
+	exports, err := NewNGServiceGenerator().createIndexExports(api, generated, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []IndexExport{
+		{File: "./api-config.config.gen", Types: []string{"APIConfig"}, Values: []string{"API_CONFIG", "provideAPIConfiguration"}},
+		{File: "./person.model.gen", Types: []string{"Person"}},
+		{File: "./person.service.gen", Values: []string{"PersonService"}},
+		{File: "./providers.gen", Values: []string{"providePeopleApi"}},
+	}, exports)
+}
+
+func TestNGServiceGenerator_GenerateAuxiliary_ExportsTypesWithExportType(t *testing.T) {
+	generator := NewNGServiceGenerator()
+	importManager := NewTSImportManager()
+	generated := []outputs.GeneratedOutput{
+		{Location: outputs.MemoryCompilerOutputLocation("api-config.config.gen.ts"), Type: outputs.OutputType_CONFIG, Name: "APIConfig"},
+		{Location: outputs.MemoryCompilerOutputLocation("person.model.gen.ts"), Type: outputs.OutputType_MODEL, Name: "Person"},
+		{Location: outputs.MemoryCompilerOutputLocation("person.service.gen.ts"), Type: outputs.OutputType_SERVICE, Name: "Person"},
+	}
+
+	var output bytes.Buffer
+	err := generator.GenerateAuxiliary(&output, indexOutputName, types.APIDefinition{Name: "people"}, generated, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "export type { APIConfig, } from './api-config.config.gen';")
+	assert.Contains(t, output.String(), "export { API_CONFIG,provideAPIConfiguration, } from './api-config.config.gen';")
+	assert.Contains(t, output.String(), "export type { Person, } from './person.model.gen';")
+	assert.Contains(t, output.String(), "export { PersonService, } from './person.service.gen';")
+	assert.NotContains(t, output.String(), "export { Person, }")
+}
+
+func TestCreateIndexExports_FailsForNamesExportedTwice(t *testing.T) {
+	api := types.APIDefinition{Name: "people"}
+	generated := []outputs.GeneratedOutput{
+		{Location: outputs.MemoryCompilerOutputLocation("person-service.model.gen.ts"), Type: outputs.OutputType_MODEL, Name: "PersonService"},
+		{Location: outputs.MemoryCompilerOutputLocation("person.service.gen.ts"), Type: outputs.OutputType_SERVICE, Name: "Person"},
+	}
+
+	importManager := NewTSImportManager()
+	_, err := NewNGServiceGenerator().createIndexExports(api, generated, &importManager)
+	assert.ErrorContains(t, err, "'PersonService' is exported by both './person-service.model.gen' and './person.service.gen'")
+}
+
+func TestNGServiceGenerator_GenerateAuxiliary_GeneratesProviders(t *testing.T) {
+	generator := NewNGServiceGenerator()
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./api-config.config.gen", "APIConfig")
+
+	var output bytes.Buffer
+	err := generator.GenerateAuxiliary(&output, providersOutputName, types.APIDefinition{Name: "people"}, nil, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "export function providePeopleApi(config: APIConfig): EnvironmentProviders")
+	assert.Contains(t, output.String(), "from './api-config.config.gen'")
+}
+
+func TestNGServiceGenerator_GenerateAuxiliary_FailsForUnknownOutput(t *testing.T) {
+	generator := NewNGServiceGenerator()
+	importManager := NewTSImportManager()
+
+	var output bytes.Buffer
+	err := generator.GenerateAuxiliary(&output, "unknown.ts", types.APIDefinition{}, nil, &importManager)
+	assert.Error(t, err)
+}
@@ -3,19 +3,32 @@ package jscodegen
 import (
 	"github.com/softwaresale/client-gen/v2/internal/codegen"
 	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
 )
 
+// NGTargetName is the name of the angular target, which keys angular-specific mappings in the specification
+const NGTargetName = "angular"
+
 // NewNGCompiler creates a new angular API compiler that produces Angular code
 func NewNGCompiler(outputDirectory string) codegen.APICompiler {
-	ngServiceGen := NewNGServiceGenerator()
+	outputsManager := &outputs.DirectoryCompilerOutputsManager{
+		BasePath: outputDirectory,
+	}
+
+	return NewNGCompilerWithOutputs(outputsManager, outputDirectory, NGOptions{})
+}
+
+// NewNGCompilerWithOutputs creates a new angular API compiler that writes its outputs to the given outputs manager
+func NewNGCompilerWithOutputs(outputsManager outputs.CompilerOutputsManager, outputDirectory string, options NGOptions) codegen.APICompiler {
+	ngServiceGen := NewNGServiceGeneratorWithOptions(options)
 	ngImportMgr := NewTSImportManager()
 
 	return codegen.APICompiler{
-		Generator:     ngServiceGen,
-		ImportManager: &ngImportMgr,
-		OutputsManager: &outputs.DirectoryCompilerOutputsManager{
-			BasePath: outputDirectory,
-		},
-		OutputPath: outputDirectory,
+		Generator:      ngServiceGen,
+		ImportManager:  &ngImportMgr,
+		OutputsManager: outputsManager,
+		OutputPath:     outputDirectory,
+		Target:         NGTargetName,
+		Identifiers:    &identifiers.TypeScript,
 	}
 }
@@ -3,8 +3,15 @@ package jscodegen
 import (
 	"fmt"
 	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/iancoleman/strcase"
 	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/outputs"
 	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
 )
 
 type TSImport struct {
@@ -32,21 +39,38 @@ func CombineTSImports(genericImport []imports.GenericImport) imports.GenericImpo
 		uniqueProvidedEntities.Append(imp.ProvidedEntities()...)
 	}
 
+	providedTypes := uniqueProvidedEntities.ToSlice()
+	slices.Sort(providedTypes)
+
 	return &TSImport{
 		File:          name,
-		ProvidedTypes: uniqueProvidedEntities.ToSlice(),
+		ProvidedTypes: providedTypes,
 	}
 }
 
+// TSImportManager resolves imports between TypeScript outputs. Types are registered with the locations of the outputs
+// that provide them, and are imported by their path relative to the output currently being generated. Imported types
+// that collide with names declared by the output being generated are imported under an alias
 type TSImportManager struct {
 	typeFiles map[string]string             // type name -> file that provides
 	providers map[string]mapset.Set[string] // file -> types it provides
+	externals map[string]externalTSType     // type name -> module that provides it
+	importer  string                        // location of the output that imports are resolved for
+	declared  mapset.Set[string]            // names declared by the output being generated
+}
+
+// externalTSType is a type imported from a module outside of the generated outputs
+type externalTSType struct {
+	module       string // bare module specifier, such as "@acme/common-models"
+	exportedName string // name the module exports the type as
 }
 
 func NewTSImportManager() TSImportManager {
 	return TSImportManager{
 		providers: make(map[string]mapset.Set[string]),
 		typeFiles: make(map[string]string),
+		externals: make(map[string]externalTSType),
+		declared:  mapset.NewSet[string](),
 	}
 }
 
@@ -57,16 +81,147 @@ func (importManager *TSImportManager) RegisterProvider(providerName string) {
 	}
 }
 
-func (importManager *TSImportManager) RegisterType(providerName, typeName string) {
+// RegisterType adds a type to the given provider. Every type can only be provided once, as generated code could not
+// tell which of the providers it refers to
+func (importManager *TSImportManager) RegisterType(providerName, typeName string) error {
+	if existing, exists := importManager.typeFiles[typeName]; exists && existing != providerName {
+		return fmt.Errorf("type '%s' is provided by both '%s' and '%s'", typeName, existing, providerName)
+	}
+
+	if external, isExternal := importManager.externals[typeName]; isExternal {
+		return fmt.Errorf("type '%s' is provided by both module '%s' and '%s'", typeName, external.module, providerName)
+	}
+
 	provider, exists := importManager.providers[providerName]
 	if exists {
 		provider.Add(typeName)
-		return
+	} else {
+		importManager.providers[providerName] = mapset.NewSet[string](typeName)
 	}
 
-	importManager.providers[providerName] = mapset.NewSet[string](typeName)
-
 	importManager.typeFiles[typeName] = providerName
+	return nil
+}
+
+// RegisterExternalType registers a type that is imported from a module instead of from another output. If the module
+// exports the type under another name, it is imported under an alias
+func (importManager *TSImportManager) RegisterExternalType(module, exportedName, typeName string) error {
+	if providingFile, exists := importManager.typeFiles[typeName]; exists {
+		return fmt.Errorf("type '%s' is provided by both '%s' and module '%s'", typeName, providingFile, module)
+	}
+
+	external := externalTSType{
+		module:       module,
+		exportedName: exportedName,
+	}
+
+	if existing, isExternal := importManager.externals[typeName]; isExternal && existing != external {
+		return fmt.Errorf("type '%s' is provided by both module '%s' and module '%s'", typeName, existing.module, module)
+	}
+
+	importManager.externals[typeName] = external
+	return nil
+}
+
+// SetImporter sets the location of the output that imports are resolved for
+func (importManager *TSImportManager) SetImporter(location string) {
+	importManager.importer = location
+}
+
+// DeclareScope starts the scope of the output being generated with the names it declares, replacing the names
+// declared by the previous output. Imported types with the same names as declared ones are aliased. Declaring a name
+// twice fails, as the output would end up with duplicate identifiers
+func (importManager *TSImportManager) DeclareScope(names ...string) error {
+	declared := mapset.NewSet[string]()
+	for _, name := range names {
+		if !declared.Add(name) {
+			return fmt.Errorf("'%s' is declared more than once", name)
+		}
+	}
+
+	importManager.declared = declared
+	return nil
+}
+
+// LocalName gets the name the output being generated refers to a type by. This is the type's own name unless it is
+// imported and collides with a local declaration, in which case it is an alias
+func (importManager *TSImportManager) LocalName(typeName string) string {
+	provider, imported := importManager.providerOf(typeName)
+	if !imported || provider == importManager.importer || !importManager.declared.Contains(typeName) {
+		return typeName
+	}
+
+	return importManager.alias(typeName, provider)
+}
+
+// providerOf gets the output location or module that provides a type
+func (importManager *TSImportManager) providerOf(typeName string) (string, bool) {
+	if external, isExternal := importManager.externals[typeName]; isExternal {
+		return external.module, true
+	}
+
+	providingFile, exists := importManager.typeFiles[typeName]
+	return providingFile, exists
+}
+
+// alias creates a name for an imported type that collides with a local declaration. Aliases are named after whatever
+// provides the type, so User from "@acme/auth" becomes AuthUser and Observable from "observable.model.gen.ts" becomes
+// ObservableModel. Aliases never collide with declared or registered names
+func (importManager *TSImportManager) alias(typeName, provider string) string {
+	base := path.Base(provider)
+	base = strings.TrimSuffix(strings.TrimSuffix(base, ".ts"), ".gen")
+	base = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return ' '
+	}, base)
+
+	prefix := strcase.ToCamel(strings.TrimSpace(base))
+	if len(prefix) == 0 || unicode.IsDigit([]rune(prefix)[0]) {
+		prefix = "Imported"
+	}
+
+	candidate := prefix + typeName
+	if strings.HasPrefix(prefix, typeName) {
+		candidate = prefix
+	}
+
+	aliased := candidate
+	for suffix := 2; importManager.isTaken(aliased); suffix++ {
+		aliased = fmt.Sprintf("%s%d", candidate, suffix)
+	}
+
+	return aliased
+}
+
+// isTaken checks if a name is already declared locally or used by a registered type
+func (importManager *TSImportManager) isTaken(name string) bool {
+	if importManager.declared.Contains(name) {
+		return true
+	}
+
+	_, isRegistered := importManager.providerOf(name)
+	return isRegistered
+}
+
+// importSpecifier gets how a type from another output is named within an import statement, aliasing it if its local
+// name differs
+func (importManager *TSImportManager) importSpecifier(typeName string) string {
+	localName := importManager.LocalName(typeName)
+	if localName == typeName {
+		return typeName
+	}
+
+	return fmt.Sprintf("%s as %s", typeName, localName)
+}
+
+// ImportPath gets the module specifier the current importer uses to import the output at the given location. Outputs
+// are imported by relative path without their extension. If no importer is set, paths are relative to the root of the
+// outputs
+func (importManager *TSImportManager) ImportPath(location string) string {
+	return strings.TrimSuffix(outputs.RelativeLocation(importManager.importer, location), ".ts")
 }
 
 func (importManager *TSImportManager) GetEntityImports(entities ...types.EntitySpec) []imports.GenericImport {
@@ -99,14 +254,21 @@ func (importManager *TSImportManager) GetServiceImports(service types.ServiceDef
 }
 
 func (importManager *TSImportManager) GetImportForType(typeName string) (imports.GenericImport, error) {
+	if external, isExternal := importManager.externals[typeName]; isExternal {
+		return &TSImport{
+			File:          external.module,
+			ProvidedTypes: []string{external.importSpecifier(typeName, importManager.LocalName(typeName))},
+		}, nil
+	}
+
 	providingFile, exists := importManager.typeFiles[typeName]
 	if !exists {
 		return nil, fmt.Errorf("type '%s' is not registered", typeName)
 	}
 
 	return &TSImport{
-		File:          providingFile,
-		ProvidedTypes: []string{typeName},
+		File:          importManager.ImportPath(providingFile),
+		ProvidedTypes: []string{importManager.importSpecifier(typeName)},
 	}, nil
 }
 
@@ -117,25 +279,82 @@ func (importManager *TSImportManager) createImportsForReferencedTypes(referenced
 	usedFiles := mapset.NewSet[string]()
 	for _, uniqueEntity := range referencedEntities.ToSlice() {
 		providingFile, exists := importManager.typeFiles[uniqueEntity]
-		if exists {
+		if exists && providingFile != importManager.importer {
+			// outputs never need to import from themselves
 			usedFiles.Add(providingFile)
 		}
 	}
 
 	// turn into imports
-	for _, providerFile := range usedFiles.ToSlice() {
+	providerFiles := usedFiles.ToSlice()
+	slices.Sort(providerFiles)
+	for _, providerFile := range providerFiles {
 		usedEntities := importManager.providers[providerFile].Intersect(referencedEntities)
 		if usedEntities.IsEmpty() {
 			continue
 		}
 
+		var providedTypes []string
+		for _, typeName := range usedEntities.ToSlice() {
+			providedTypes = append(providedTypes, importManager.importSpecifier(typeName))
+		}
+		slices.Sort(providedTypes)
+
 		imp := TSImport{
-			File:          providerFile,
-			ProvidedTypes: usedEntities.ToSlice(),
+			File:          importManager.ImportPath(providerFile),
+			ProvidedTypes: providedTypes,
 		}
 
 		imports = append(imports, &imp)
 	}
 
-	return imports
+	return append(imports, importManager.createExternalImports(referencedEntities)...)
+}
+
+// createExternalImports creates an import for each module that provides a referenced external type
+func (importManager *TSImportManager) createExternalImports(referencedEntities mapset.Set[string]) []imports.GenericImport {
+	moduleTypes := make(map[string][]string)
+	for _, referenced := range referencedEntities.ToSlice() {
+		external, isExternal := importManager.externals[referenced]
+		if isExternal {
+			moduleTypes[external.module] = append(moduleTypes[external.module], external.importSpecifier(referenced, importManager.LocalName(referenced)))
+		}
+	}
+
+	var externalImports []imports.GenericImport
+	for _, module := range slices.Sorted(maps.Keys(moduleTypes)) {
+		providedTypes := moduleTypes[module]
+		slices.Sort(providedTypes)
+
+		externalImports = append(externalImports, &TSImport{
+			File:          module,
+			ProvidedTypes: providedTypes,
+		})
+	}
+
+	return externalImports
+}
+
+// importSpecifier gets how the type is named within an import statement, aliasing it if it is exported under
+// another name than the one it is referred to by
+func (external externalTSType) importSpecifier(typeName, localName string) string {
+	exportedName := external.exportedName
+	if len(exportedName) == 0 {
+		exportedName = typeName
+	}
+
+	if exportedName == localName {
+		return localName
+	}
+
+	return fmt.Sprintf("%s as %s", exportedName, localName)
+}
+
+// declareScope declares the names defined by the output being generated, if the resolver keeps track of them
+func declareScope(resolver imports.ImportManager, names ...string) error {
+	if scoped, ok := resolver.(imports.ScopedImportManager); ok {
+		return scoped.DeclareScope(names...)
+	}
+
+	return nil
 }
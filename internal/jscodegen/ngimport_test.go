@@ -0,0 +1,163 @@
+package jscodegen
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTSImportManager_GetImportForType_ImportsRelativeToImporter(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("/out/models/person.model.ts", "Person")
+
+	importManager.SetImporter("/out/services/person/person.service.ts")
+	personImport, err := importManager.GetImportForType("Person")
+	assert.NoError(t, err)
+	assert.Equal(t, "../../models/person.model", personImport.Provider())
+
+	importManager.SetImporter("/out/index.ts")
+	personImport, err = importManager.GetImportForType("Person")
+	assert.NoError(t, err)
+	assert.Equal(t, "./models/person.model", personImport.Provider())
+}
+
+func TestTSImportManager_GetEntityImports_SkipsImporter(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("models/person.model.ts", "Person")
+	importManager.RegisterType("models/team.model.ts", "Team")
+
+	person := types.EntitySpec{
+		Name: "Person",
+		Properties: map[string]types.PropertySpec{
+			"friends": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}},
+			"team":    {Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Team"}},
+		},
+	}
+
+	importManager.SetImporter("models/person.model.ts")
+	entityImports := importManager.GetEntityImports(person)
+
+	assert.Len(t, entityImports, 1)
+	assert.Equal(t, "./team.model", entityImports[0].Provider())
+	assert.Equal(t, []string{"Team"}, entityImports[0].ProvidedEntities())
+}
+
+func TestTSImportManager_RegisterType_RegistersEveryTypeOfAProvider(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("shared.ts", "Person")
+	importManager.RegisterType("shared.ts", "Team")
+
+	teamImport, err := importManager.GetImportForType("Team")
+	assert.NoError(t, err)
+	assert.Equal(t, "./shared", teamImport.Provider())
+}
+
+func TestTSImportManager_GetServiceImports_ImportsExternalTypesFromModules(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("person.model.gen.ts", "Person")
+	importManager.RegisterExternalType("@acme/common-models", "Money", "Money")
+	importManager.RegisterExternalType("@acme/common-models", "PageRequestDto", "PageRequest")
+	importManager.SetImporter("person.service.gen.ts")
+
+	service := types.ServiceDefinition{
+		Name: "Person",
+		Endpoints: []types.APIEndpoint{
+			{
+				Name:         "search",
+				RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "PageRequest"}},
+				ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}},
+				QueryVariables: map[string]types.RequestValue{
+					"budget": {Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Money"}},
+				},
+			},
+		},
+	}
+
+	serviceImports := importManager.GetServiceImports(service)
+	assert.Len(t, serviceImports, 2)
+	assert.Equal(t, "./person.model.gen", serviceImports[0].Provider())
+	assert.Equal(t, "@acme/common-models", serviceImports[1].Provider())
+	assert.Equal(t, []string{"Money", "PageRequestDto as PageRequest"}, serviceImports[1].ProvidedEntities())
+
+	moneyImport, err := importManager.GetImportForType("Money")
+	assert.NoError(t, err)
+	assert.Equal(t, "@acme/common-models", moneyImport.Provider())
+}
+
+func TestTSImportManager_RegisterType_FailsForTypesProvidedTwice(t *testing.T) {
+	importManager := NewTSImportManager()
+	assert.NoError(t, importManager.RegisterType("person.model.gen.ts", "Person"))
+	assert.NoError(t, importManager.RegisterType("person.model.gen.ts", "Person"))
+	assert.NoError(t, importManager.RegisterExternalType("@acme/auth", "User", "User"))
+
+	err := importManager.RegisterType("people.model.gen.ts", "Person")
+	assert.ErrorContains(t, err, "type 'Person' is provided by both 'person.model.gen.ts' and 'people.model.gen.ts'")
+
+	err = importManager.RegisterType("user.model.gen.ts", "User")
+	assert.ErrorContains(t, err, "type 'User' is provided by both module '@acme/auth' and 'user.model.gen.ts'")
+
+	err = importManager.RegisterExternalType("@acme/people", "Person", "Person")
+	assert.ErrorContains(t, err, "type 'Person' is provided by both 'person.model.gen.ts' and module '@acme/people'")
+
+	err = importManager.RegisterExternalType("@acme/users", "User", "User")
+	assert.ErrorContains(t, err, "type 'User' is provided by both module '@acme/auth' and module '@acme/users'")
+}
+
+func TestTSImportManager_DeclareScope_FailsForNamesDeclaredTwice(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	err := importManager.DeclareScope("PersonService", "GetByIdInput", "GetByIdInput")
+	assert.ErrorContains(t, err, "'GetByIdInput' is declared more than once")
+}
+
+func TestTSImportManager_LocalName_AliasesTypesThatCollideWithDeclarations(t *testing.T) {
+	importManager := NewTSImportManager()
+	assert.NoError(t, importManager.RegisterType("models/observable.model.gen.ts", "Observable"))
+	assert.NoError(t, importManager.RegisterType("models/person.model.gen.ts", "Person"))
+	assert.NoError(t, importManager.RegisterExternalType("@acme/auth", "UserDto", "User"))
+
+	importManager.SetImporter("services/person.service.gen.ts")
+	assert.NoError(t, importManager.DeclareScope("Observable", "User", "PersonService"))
+
+	assert.Equal(t, "ObservableModel", importManager.LocalName("Observable"))
+	assert.Equal(t, "AuthUser", importManager.LocalName("User"))
+	assert.Equal(t, "Person", importManager.LocalName("Person"))
+
+	service := types.ServiceDefinition{
+		Name: "Person",
+		Endpoints: []types.APIEndpoint{
+			{
+				Name:         "watch",
+				RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "User"}},
+				ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Observable"}},
+			},
+		},
+	}
+
+	serviceImports := importManager.GetServiceImports(service)
+	assert.Len(t, serviceImports, 2)
+	assert.Equal(t, []string{"Observable as ObservableModel"}, serviceImports[0].ProvidedEntities())
+	assert.Equal(t, []string{"UserDto as AuthUser"}, serviceImports[1].ProvidedEntities())
+
+	mapper := newJSTypeMapper(&importManager)
+	responseType, err := mapper.Convert(types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Observable"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ObservableModel[]", responseType)
+
+	// a new scope forgets the names declared by the previous output
+	importManager.SetImporter("models/person.model.gen.ts")
+	assert.NoError(t, importManager.DeclareScope("Person"))
+	assert.Equal(t, "Observable", importManager.LocalName("Observable"))
+	assert.Equal(t, "Person", importManager.LocalName("Person"))
+}
+
+func TestTSImportManager_LocalName_AvoidsAliasesThatAreTaken(t *testing.T) {
+	importManager := NewTSImportManager()
+	assert.NoError(t, importManager.RegisterExternalType("@acme/auth", "User", "User"))
+	assert.NoError(t, importManager.RegisterType("auth-user.model.gen.ts", "AuthUser"))
+
+	importManager.SetImporter("user.service.gen.ts")
+	assert.NoError(t, importManager.DeclareScope("User", "AuthUser2"))
+
+	assert.Equal(t, "AuthUser3", importManager.LocalName("User"))
+}
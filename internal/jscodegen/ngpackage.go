@@ -0,0 +1,183 @@
+package jscodegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	packageJsonOutputName   = "package.json"
+	ngPackageJsonOutputName = "ng-package.json"
+	tsconfigOutputName      = "tsconfig.json"
+	publicAPIOutputName     = "public-api.ts"
+
+	// AngularVersion is the range of Angular versions that generated code targets
+	AngularVersion = "^18.0.0"
+	// RxJSVersion is the range of RxJS versions that generated code targets
+	RxJSVersion = "^7.8.0"
+	// ZodVersion is the range of zod versions that generated schemas target
+	ZodVersion = "^3.23.0"
+
+	defaultPackageVersion = "0.0.0"
+)
+
+// PackageJson models the parts of an npm package.json that we generate
+type PackageJson struct {
+	Name             string            `json:"name"`
+	Version          string            `json:"version"`
+	Description      string            `json:"description,omitempty"`
+	Scripts          map[string]string `json:"scripts"`
+	PeerDependencies map[string]string `json:"peerDependencies"`
+	DevDependencies  map[string]string `json:"devDependencies"`
+	SideEffects      bool              `json:"sideEffects"`
+}
+
+// NgPackageJson models an ng-packagr configuration file
+type NgPackageJson struct {
+	Schema string            `json:"$schema"`
+	Dest   string            `json:"dest"`
+	Lib    NgPackageLibEntry `json:"lib"`
+}
+
+type NgPackageLibEntry struct {
+	EntryFile string `json:"entryFile"`
+}
+
+// TSConfig models the parts of a tsconfig.json that we generate
+type TSConfig struct {
+	CompilerOptions        map[string]any `json:"compilerOptions"`
+	AngularCompilerOptions map[string]any `json:"angularCompilerOptions"`
+	Files                  []string       `json:"files"`
+}
+
+// scopePattern matches valid npm scopes, which are lowercase and URL-safe, and cannot start with a dot or underscore
+var scopePattern = regexp.MustCompile(`^@[a-z0-9~-][a-z0-9._~-]*$`)
+
+// normalizeScope adds the leading '@' to an npm scope if it is missing, and checks that the scope is valid
+func normalizeScope(scope string) (string, error) {
+	if len(scope) == 0 {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(scope, "@") {
+		scope = "@" + scope
+	}
+
+	if !scopePattern.MatchString(scope) {
+		return "", fmt.Errorf("'%s' is not a valid npm scope", scope)
+	}
+
+	return scope, nil
+}
+
+// packageName gets the npm package name for the given API. The scope must already be normalized
+func packageName(api types.APIDefinition, scope string) string {
+	name := strcase.ToKebab(api.Name)
+	if len(scope) == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s/%s", scope, name)
+}
+
+func createPackageJson(api types.APIDefinition, scope string) PackageJson {
+	version := api.Version
+	if len(version) == 0 {
+		version = defaultPackageVersion
+	}
+
+	return PackageJson{
+		Name:        packageName(api, scope),
+		Version:     version,
+		Description: fmt.Sprintf("Generated Angular client for the %s API", api.Name),
+		Scripts: map[string]string{
+			"build": fmt.Sprintf("ng-packagr -p %s -c %s", ngPackageJsonOutputName, tsconfigOutputName),
+		},
+		PeerDependencies: map[string]string{
+			"@angular/common": AngularVersion,
+			"@angular/core":   AngularVersion,
+			"rxjs":            RxJSVersion,
+		},
+		DevDependencies: map[string]string{
+			"@angular/common":       AngularVersion,
+			"@angular/compiler":     AngularVersion,
+			"@angular/compiler-cli": AngularVersion,
+			"@angular/core":         AngularVersion,
+			"ng-packagr":            AngularVersion,
+			"rxjs":                  RxJSVersion,
+			"typescript":            "~5.4.0",
+		},
+		SideEffects: false,
+	}
+}
+
+func createNgPackageJson() NgPackageJson {
+	return NgPackageJson{
+		Schema: "./node_modules/ng-packagr/ng-package.schema.json",
+		Dest:   "dist",
+		Lib: NgPackageLibEntry{
+			EntryFile: publicAPIOutputName,
+		},
+	}
+}
+
+func createTSConfig() TSConfig {
+	return TSConfig{
+		CompilerOptions: map[string]any{
+			"target":           "ES2022",
+			"module":           "ES2022",
+			"moduleResolution": "bundler",
+			"lib":              []string{"ES2022", "dom"},
+			"strict":           true,
+			"declaration":      true,
+			"declarationMap":   true,
+			"inlineSources":    true,
+			"sourceMap":        true,
+			"skipLibCheck":     true,
+			"outDir":           "./out-tsc",
+		},
+		AngularCompilerOptions: map[string]any{
+			"compilationMode":            "partial",
+			"strictInjectionParameters":  true,
+			"strictInputAccessModifiers": true,
+			"strictTemplates":            true,
+		},
+		Files: []string{publicAPIOutputName},
+	}
+}
+
+// writeJson writes a pretty-printed JSON document
+func writeJson(writer io.Writer, value any) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// generatePackageOutput generates one of the package scaffolding outputs. Returns false if the name is not a package
+// scaffolding output
+func (generator *NGServiceGenerator) generatePackageOutput(writer io.Writer, name string, api types.APIDefinition) (bool, error) {
+	switch name {
+	case packageJsonOutputName:
+		packageJson := createPackageJson(api, generator.options.PackageScope)
+		if generator.options.Zod {
+			packageJson.PeerDependencies[zodProvider] = ZodVersion
+			packageJson.DevDependencies[zodProvider] = ZodVersion
+		}
+
+		return true, writeJson(writer, packageJson)
+	case ngPackageJsonOutputName:
+		return true, writeJson(writer, createNgPackageJson())
+	case tsconfigOutputName:
+		return true, writeJson(writer, createTSConfig())
+	case publicAPIOutputName:
+		_, err := fmt.Fprintf(writer, "/*\n    This file was auto-generated. Do not modify by hand\n*/\nexport * from './%s';\n", strings.TrimSuffix(indexOutputName, ".ts"))
+		return true, err
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,81 @@
+package jscodegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreatePackageJson_UsesNameAndVersion(t *testing.T) {
+	api := types.APIDefinition{Name: "PeopleApi", Version: "1.2.3"}
+
+	packageJson := createPackageJson(api, "@acme")
+
+	assert.Equal(t, "@acme/people-api", packageJson.Name)
+	assert.Equal(t, "1.2.3", packageJson.Version)
+	assert.Equal(t, AngularVersion, packageJson.PeerDependencies["@angular/core"])
+}
+
+func TestCreatePackageJson_DefaultsVersion(t *testing.T) {
+	packageJson := createPackageJson(types.APIDefinition{Name: "people"}, "")
+
+	assert.Equal(t, "people", packageJson.Name)
+	assert.Equal(t, defaultPackageVersion, packageJson.Version)
+}
+
+func TestNGServiceGenerator_AuxiliaryOutputs_IncludesPackageWhenEnabled(t *testing.T) {
+	withoutPackage := NewNGServiceGenerator().AuxiliaryOutputs(types.APIDefinition{})
+	assert.NotContains(t, withoutPackage, packageJsonOutputName)
+
+	withPackage := NewNGServiceGeneratorWithOptions(NGOptions{Package: true}).AuxiliaryOutputs(types.APIDefinition{})
+	assert.Contains(t, withPackage, packageJsonOutputName)
+	assert.Contains(t, withPackage, ngPackageJsonOutputName)
+	assert.Contains(t, withPackage, tsconfigOutputName)
+	assert.Contains(t, withPackage, publicAPIOutputName)
+}
+
+func TestNGServiceGenerator_GenerateAuxiliary_GeneratesValidPackageJson(t *testing.T) {
+	generator := NewNGServiceGeneratorWithOptions(NGOptions{Package: true})
+	importManager := NewTSImportManager()
+
+	var output bytes.Buffer
+	err := generator.GenerateAuxiliary(&output, packageJsonOutputName, types.APIDefinition{Name: "people"}, nil, &importManager)
+	assert.NoError(t, err)
+
+	var decoded PackageJson
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, "people", decoded.Name)
+}
+
+func TestNGServiceGenerator_PrepareAPI_RequiresNameForPackage(t *testing.T) {
+	generator := NewNGServiceGeneratorWithOptions(NGOptions{Package: true, PackageScope: "@acme"})
+	err := generator.PrepareAPI(types.APIDefinition{})
+	assert.EqualError(t, err, "a package cannot be generated for an API without a name")
+
+	// without a package, the name is not needed
+	assert.NoError(t, NewNGServiceGenerator().PrepareAPI(types.APIDefinition{}))
+}
+
+func TestNGServiceGenerator_PrepareAPI_AddsMissingScopePrefix(t *testing.T) {
+	generator := NewNGServiceGeneratorWithOptions(NGOptions{Package: true, PackageScope: "acme"})
+	assert.NoError(t, generator.PrepareAPI(types.APIDefinition{Name: "people"}))
+	importManager := NewTSImportManager()
+
+	var output bytes.Buffer
+	err := generator.GenerateAuxiliary(&output, packageJsonOutputName, types.APIDefinition{Name: "people"}, nil, &importManager)
+	assert.NoError(t, err)
+
+	var decoded PackageJson
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, "@acme/people", decoded.Name)
+}
+
+func TestNGServiceGenerator_PrepareAPI_RejectsInvalidScopes(t *testing.T) {
+	for _, scope := range []string{"@", "@Acme", "@acme/tools", "@.acme", "@_acme", "acme corp", "@@acme"} {
+		generator := NewNGServiceGeneratorWithOptions(NGOptions{Package: true, PackageScope: scope})
+		err := generator.PrepareAPI(types.APIDefinition{Name: "people"})
+		assert.ErrorContains(t, err, "is not a valid npm scope", scope)
+	}
+}
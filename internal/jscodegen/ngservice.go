@@ -8,6 +8,8 @@ import (
 	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
 	"github.com/softwaresale/client-gen/v2/internal/types"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 	"text/template"
 )
@@ -27,12 +29,23 @@ var importsTemplateText string
 //go:embed ng-config.tmpl
 var configTemplateText string
 
+//go:embed ng-codec.tmpl
+var codecTemplateText string
+
+//go:embed ng-validator.tmpl
+var validatorTemplateText string
+
+//go:embed ng-schema.tmpl
+var schemaTemplateText string
+
 type HttpRequestDef struct {
 	HttpClientVar    string              // the name of the variable that defines the HTTP client in use
 	HttpMethod       string              // The HTTP method used by this request
 	ResponseType     string              // the type string of our response
 	URITemplate      codegen.URITemplate // our URI template. This gets mapped into a uri string
 	RequestBodyValue string              // The value to read the body type
+	QueryParams      []string            // statements that set each query parameter
+	ResponseMappers  []string            // expressions that map the received response, in order
 }
 
 func hasRequestBody(def string) bool {
@@ -40,11 +53,12 @@ func hasRequestBody(def string) bool {
 }
 
 type RequestMethodDef struct {
-	RequestName      string         // The name of this request
-	InputVarName     string         // The variable name of the input payload type
-	RequestInputType string         // the type string of the input payload
-	ResponseType     string         // The type string of the response
-	HttpRequest      HttpRequestDef // The http request that should be called in this endpoint
+	Documentation    types.Documentation // documentation of the endpoint this method calls
+	RequestName      string              // The name of this request
+	InputVarName     string              // The variable name of the input payload type
+	RequestInputType string              // the type string of the input payload
+	ResponseType     string              // The type string of the response
+	HttpRequest      HttpRequestDef      // The http request that should be called in this endpoint
 }
 
 func (def RequestMethodDef) HasInput() bool {
@@ -53,18 +67,25 @@ func (def RequestMethodDef) HasInput() bool {
 
 // EntityDef defines the template for a standalone entity file
 type EntityDef struct {
-	Entity  types.EntitySpec        // the entity we are generating
-	Imports []imports.GenericImport // imports used by this entity
+	Entity    types.EntitySpec        // the entity we are generating
+	Imports   []imports.GenericImport // imports used by this entity
+	Codec     *CodecDef               // converts dates in this entity to and from the wire, if it holds any
+	Validator *ValidatorDef           // optional runtime validator for this entity
+	Schema    *SchemaDef              // optional zod schema for this entity
 }
 
 type ServiceDef struct {
-	ServiceName   string
-	HttpClientVar string
-	APIConfigType string
-	APIConfigVar  string
-	InputTypes    []types.EntitySpec
-	Methods       []RequestMethodDef
-	Imports       []imports.GenericImport
+	Documentation   types.Documentation
+	ServiceName     string
+	HttpClientVar   string
+	APIConfigType   string
+	APIConfigToken  string // injection token the API config is provided with
+	APIConfigVar    string
+	InputTypes      []types.EntitySpec
+	Methods         []RequestMethodDef
+	Imports         []imports.GenericImport
+	MapsResponses   bool // if true, at least one response is mapped after it is received
+	UsesQueryParams bool // if true, at least one request sends query parameters
 }
 
 // ConfigDef defines what we need to model for our API configuration providers
@@ -74,18 +95,51 @@ type ConfigDef struct {
 	ConfigInit   types.EntityInitializer // how to configure the default configuration
 }
 
+// serviceFrameworkNames are the names that service outputs import from Angular and RxJS
+var serviceFrameworkNames = []string{"HttpClient", "HttpParams", "inject", "Injectable", "map", "Observable"}
+
 func mapHttpEndpoint(method string) string {
 	return strings.ToLower(method)
 }
 
+// httpMethodTakesBody checks if the HttpClient method for the given HTTP method requires a body argument
+func httpMethodTakesBody(method string) bool {
+	switch mapHttpEndpoint(method) {
+	case "post", "put", "patch":
+		return true
+	default:
+		return false
+	}
+}
+
+// NGOptions configures optional features of the generated Angular code
+type NGOptions struct {
+	Package      bool   // emit a publishable npm library scaffold alongside the generated sources
+	PackageScope string // optional npm scope of the generated package, such as "@acme"
+	Validators   bool   // emit a runtime validator function for each entity
+	Zod          bool   // emit a zod schema for each entity and parse responses with them
+}
+
 type NGServiceGenerator struct {
-	ngServiceTemplate *template.Template
-	ngEntityTemplate  *template.Template
-	ngConfigTemplate  *template.Template
+	options             NGOptions
+	naming              types.NamingStrategy // how property names are written on the wire
+	converted           map[string]bool      // entities that must be converted when they are sent or received
+	parameterised       map[string]bool      // generic entities whose codecs convert their argument
+	external            map[string]bool      // types that are imported from other modules instead of generated
+	ngServiceTemplate   *template.Template
+	ngEntityTemplate    *template.Template
+	ngConfigTemplate    *template.Template
+	ngIndexTemplate     *template.Template
+	ngProvidersTemplate *template.Template
 }
 
 // NewNGServiceGenerator creates a new NGService generator, which can be used to generate services
 func NewNGServiceGenerator() *NGServiceGenerator {
+	return NewNGServiceGeneratorWithOptions(NGOptions{})
+}
+
+// NewNGServiceGeneratorWithOptions creates a new NGService generator with optional features configured
+func NewNGServiceGeneratorWithOptions(options NGOptions) *NGServiceGenerator {
 
 	typeMapper := JSTypeMapper{}
 	valueMapper := JSValueMapper{}
@@ -95,6 +149,8 @@ func NewNGServiceGenerator() *NGServiceGenerator {
 		"ParseTemplate":  codegen.FormatTemplate,
 		"ConvertType":    typeMapper.Convert,
 		"ConvertValue":   valueMapper.Convert,
+		"JSDoc":          FormatJSDoc,
+		"PropertyKey":    objectKey,
 	}
 
 	serviceTmpl := template.Must(template.New("NGService").Funcs(funcMap).Parse(templateText))
@@ -103,56 +159,156 @@ func NewNGServiceGenerator() *NGServiceGenerator {
 
 	entityTmpl := template.Must(template.New("NGEntity").Funcs(funcMap).Parse(entityTemplateText))
 	entityTmpl = template.Must(entityTmpl.Parse(importsTemplateText))
+	entityTmpl = template.Must(entityTmpl.Parse(codecTemplateText))
+	entityTmpl = template.Must(entityTmpl.Parse(validatorTemplateText))
+	entityTmpl = template.Must(entityTmpl.Parse(schemaTemplateText))
 	entityTmpl = template.Must(entityTmpl.Parse(standaloneEntityTemplateText))
 
 	configTmpl := template.Must(template.New("NGConfig").Funcs(funcMap).Parse(configTemplateText))
 	configTmpl = template.Must(configTmpl.Parse(entityTemplateText))
 
+	indexTmpl := template.Must(template.New("NGIndex").Funcs(funcMap).Parse(indexTemplateText))
+
+	providersTmpl := template.Must(template.New("NGProviders").Funcs(funcMap).Parse(providersTemplateText))
+	providersTmpl = template.Must(providersTmpl.Parse(importsTemplateText))
+
 	return &NGServiceGenerator{
-		ngServiceTemplate: serviceTmpl,
-		ngEntityTemplate:  entityTmpl,
-		ngConfigTemplate:  configTmpl,
+		options:             options,
+		ngServiceTemplate:   serviceTmpl,
+		ngEntityTemplate:    entityTmpl,
+		ngConfigTemplate:    configTmpl,
+		ngIndexTemplate:     indexTmpl,
+		ngProvidersTemplate: providersTmpl,
+	}
+}
+
+// PrepareAPI works out which entities hold dates or renamed properties, so that they can be converted when they are
+// sent or received, and which types are external
+func (generator *NGServiceGenerator) PrepareAPI(api types.APIDefinition) error {
+	if err := api.Naming.Validate(); err != nil {
+		return err
+	}
+
+	// npm rejects packages without a name, so catch it before anything is generated
+	if generator.options.Package && len(packageName(api, "")) == 0 {
+		return fmt.Errorf("a package cannot be generated for an API without a name")
+	}
+
+	scope, err := normalizeScope(generator.options.PackageScope)
+	if err != nil {
+		return err
 	}
+	generator.options.PackageScope = scope
+
+	// arrays without element types cannot be converted, validated or typed
+	if err := checkElementTypes(api); err != nil {
+		return err
+	}
+
+	generator.naming = api.Naming
+	generator.parameterised = parameterisedEntities(api)
+	generator.converted = convertedEntities(api.Entities, api.Naming, generator.parameterised)
+	if err := checkGenericArguments(api, generator.converted, generator.parameterised); err != nil {
+		return err
+	}
+
+	generator.external = make(map[string]bool)
+	for _, external := range api.ExternalTypes {
+		generator.external[external.Name] = true
+	}
+
+	return nil
 }
 
 func (generator *NGServiceGenerator) GenerateService(writer io.Writer, def types.ServiceDefinition, resolver imports.ImportManager) error {
-	translatedDef, err := translateService(def, resolver)
+	translatedDef, err := generator.translateService(def, resolver)
 	if err != nil {
 		return fmt.Errorf("failed to translateService service definition: %w", err)
 	}
 
-	return generator.ngServiceTemplate.Execute(writer, translatedDef)
+	serviceTemplate, err := withTypeMapper(generator.ngServiceTemplate, newJSTypeMapper(resolver))
+	if err != nil {
+		return err
+	}
+
+	return serviceTemplate.Execute(writer, translatedDef)
 }
 
-func translateService(service types.ServiceDefinition, importResolver imports.ImportManager) (ServiceDef, error) {
+// withTypeMapper gets a copy of a template that converts types with the given mapper, so that the types it writes are
+// named the way the output being generated refers to them
+func withTypeMapper(tmpl *template.Template, typeMapper JSTypeMapper) (*template.Template, error) {
+	cloned, err := tmpl.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone template: %w", err)
+	}
 
-	typeMapper := JSTypeMapper{}
+	return cloned.Funcs(template.FuncMap{"ConvertType": typeMapper.Convert}), nil
+}
+
+func (generator *NGServiceGenerator) translateService(service types.ServiceDefinition, importResolver imports.ImportManager) (ServiceDef, error) {
+
+	typeMapper := newJSTypeMapper(importResolver)
+	schemaMapper := newZodSchemaMapper(importResolver, generator.external, generator.parameterised, false)
+	codecs := newCodecMapper(generator.converted, generator.parameterised)
 	httpClientVar := "http"
 	configVar := "config"
 	configTp := "APIConfig"
 	baseURLProperty := "baseURL"
 
-	var methods []RequestMethodDef
-	var inputs []types.EntitySpec
-	for _, endpoint := range service.Endpoints {
+	inputVarName := "input"
+	bodyPropertyName := "body"
 
-		inputVarName := "input"
-		bodyPropertyName := "body"
-
-		requestInputDef, err := createInputType(endpoint, bodyPropertyName)
+	// input types are declared alongside the service, so they have to be known before any type is named
+	var inputs []types.EntitySpec
+	inputTypeNames := make([]string, len(service.Endpoints))
+	for idx, endpoint := range service.Endpoints {
+		requestInputDef, err := generator.createInputType(endpoint, bodyPropertyName)
 		if err != nil {
 			return ServiceDef{}, err
 		}
 
-		inputTypeName := ""
 		if requestInputDef.IsValid() {
-			inputTypeName = requestInputDef.Name
+			inputTypeNames[idx] = requestInputDef.Name
 			inputs = append(inputs, *requestInputDef)
 		}
+	}
+
+	localNames := slices.Concat(serviceFrameworkNames, []string{serviceClassName(service.Name)})
+	for _, input := range inputs {
+		localNames = append(localNames, input.Name)
+	}
+
+	if generator.options.Zod {
+		localNames = append(localNames, "z")
+	}
+
+	err := declareScope(importResolver, localNames...)
+	if err != nil {
+		return ServiceDef{}, fmt.Errorf("failed to declare names of service '%s': %w", service.Name, err)
+	}
+
+	var methods []RequestMethodDef
+	mapsResponses := false
+	usesQueryParams := false
+	for idx, endpoint := range service.Endpoints {
+		inputTypeName := inputTypeNames[idx]
 
 		requestBodyValue := ""
 		if !endpoint.RequestBody.Type.IsVoid() {
 			requestBodyValue = fmt.Sprintf("%s.%s", inputVarName, bodyPropertyName)
+			if serialized, ok := codecs.Serialize(endpoint.RequestBody.Type, requestBodyValue); ok {
+				requestBodyValue = serialized
+			}
+		}
+
+		queryParams, err := generator.createQueryParams(endpoint, inputVarName, codecs)
+		if err != nil {
+			return ServiceDef{}, fmt.Errorf("failed to create query parameters of '%s': %w", endpoint.Name, err)
+		}
+
+		// the body comes before the request options, so it has to be given for the query parameters to be passed
+		if len(queryParams) > 0 && len(requestBodyValue) == 0 && httpMethodTakesBody(endpoint.Method) {
+			requestBodyValue = "null"
 		}
 
 		responseType, err := typeMapper.Convert(endpoint.ResponseBody.Type)
@@ -160,7 +316,23 @@ func translateService(service types.ServiceDefinition, importResolver imports.Im
 			return ServiceDef{}, err
 		}
 
+		// responses are parsed as they are received, then the validated value is revived
+		var responseMappers []string
+		if generator.options.Zod && !endpoint.ResponseBody.Type.IsVoid() {
+			responseSchema, err := schemaMapper.Convert(endpoint.ResponseBody.Type)
+			if err != nil {
+				return ServiceDef{}, fmt.Errorf("failed to create response schema: %w", err)
+			}
+
+			responseMappers = append(responseMappers, fmt.Sprintf("%s.parse(response)", responseSchema))
+		}
+
+		if revived, ok := codecs.Revive(endpoint.ResponseBody.Type, "response"); ok {
+			responseMappers = append(responseMappers, revived)
+		}
+
 		methodDef := RequestMethodDef{
+			Documentation:    endpoint.Documentation,
 			RequestName:      endpoint.Name,
 			InputVarName:     inputVarName,
 			RequestInputType: inputTypeName,
@@ -172,15 +344,24 @@ func translateService(service types.ServiceDefinition, importResolver imports.Im
 				URITemplate: codegen.URITemplate{
 					Template: endpoint.Endpoint,
 					VarMapper: func(pathVar string) (string, error) {
-						return fmt.Sprintf("${%s.%s}", inputVarName, pathVar), nil
+						accessor := propertyAccess(inputVarName, codeName(pathVar, generator.naming))
+						if serialized, ok := codecs.serialize(endpoint.PathVariables[pathVar].Type, accessor, 0); ok {
+							accessor = serialized
+						}
+
+						return fmt.Sprintf("${%s}", accessor), nil
 					},
 					Prefix: fmt.Sprintf("${this.%s.%s}", configVar, baseURLProperty),
 				},
 				RequestBodyValue: requestBodyValue,
+				QueryParams:      queryParams,
+				ResponseMappers:  responseMappers,
 			},
 		}
 
 		methods = append(methods, methodDef)
+		mapsResponses = mapsResponses || len(responseMappers) > 0
+		usesQueryParams = usesQueryParams || len(queryParams) > 0
 	}
 
 	inputImportMap := importResolver.GetEntityImports(inputs...)
@@ -190,36 +371,66 @@ func translateService(service types.ServiceDefinition, importResolver imports.Im
 		return ServiceDef{}, fmt.Errorf("failed to get api config import: %w", err)
 	}
 
-	importMap := imports.UnionImports(CombineTSImports, inputImportMap, serviceImportMap, []imports.GenericImport{apiConfigImport})
+	// add an import for our API config, along with the token it is injected with
+	configTokenImport := &TSImport{File: apiConfigImport.Provider(), ProvidedTypes: []string{configTokenName}}
+	importMap := imports.UnionImports(CombineTSImports, inputImportMap, serviceImportMap, []imports.GenericImport{apiConfigImport, configTokenImport})
+
+	if generator.options.Zod {
+		schemaImports, err := schemaMapper.Imports("")
+		if err != nil {
+			return ServiceDef{}, fmt.Errorf("failed to import response schemas: %w", err)
+		}
 
-	// add an import for our API config
-	importMap = append(importMap)
+		importMap = imports.UnionImports(CombineTSImports, importMap, schemaImports)
+	}
+
+	codecImports, err := codecs.Imports("", importResolver)
+	if err != nil {
+		return ServiceDef{}, fmt.Errorf("failed to import codecs: %w", err)
+	}
+	importMap = imports.UnionImports(CombineTSImports, importMap, codecImports)
 
 	return ServiceDef{
-		ServiceName:   service.Name,
-		HttpClientVar: httpClientVar,
-		APIConfigVar:  configVar,
-		APIConfigType: configTp,
-		Methods:       methods,
-		InputTypes:    inputs,
-		Imports:       importMap,
+		Documentation:   service.Documentation,
+		ServiceName:     service.Name,
+		HttpClientVar:   httpClientVar,
+		APIConfigVar:    configVar,
+		APIConfigType:   typeMapper.typeName(configTp),
+		APIConfigToken:  configTokenName,
+		Methods:         methods,
+		InputTypes:      inputs,
+		Imports:         importMap,
+		MapsResponses:   mapsResponses,
+		UsesQueryParams: usesQueryParams,
 	}, nil
 }
 
-func createInputType(endpoint types.APIEndpoint, bodyPropertyName string) (*types.EntitySpec, error) {
+// createInputType creates the type that holds every input of an endpoint. Path and query variables are named as they
+// are in code, while query variables are sent using the naming strategy
+func (generator *NGServiceGenerator) createInputType(endpoint types.APIEndpoint, bodyPropertyName string) (*types.EntitySpec, error) {
 	inputTypeName := strcase.ToCamel(fmt.Sprintf("%sInput", endpoint.Name))
 	properties := make(map[string]types.PropertySpec)
 	for prop, tp := range endpoint.PathVariables {
-		properties[prop] = types.PropertySpec{
-			Type:     tp.Type,
-			Required: tp.Required,
+		properties[codeName(prop, generator.naming)] = types.PropertySpec{
+			Documentation: tp.Documentation,
+			Type:          tp.Type,
+			Required:      tp.Required,
+		}
+	}
+
+	for prop, tp := range endpoint.QueryVariables {
+		properties[codeName(prop, generator.naming)] = types.PropertySpec{
+			Documentation: tp.Documentation,
+			Type:          tp.Type,
+			Required:      tp.Required,
 		}
 	}
 
 	if !endpoint.RequestBody.Type.IsVoid() {
 		properties[bodyPropertyName] = types.PropertySpec{
-			Type:     endpoint.RequestBody.Type,
-			Required: endpoint.RequestBody.Required,
+			Documentation: endpoint.RequestBody.Documentation,
+			Type:          endpoint.RequestBody.Type,
+			Required:      endpoint.RequestBody.Required,
 		}
 	}
 
@@ -229,19 +440,128 @@ func createInputType(endpoint types.APIEndpoint, bodyPropertyName string) (*type
 	}, nil
 }
 
+// createQueryParams creates statements that set each query parameter of an endpoint that has a value
+func (generator *NGServiceGenerator) createQueryParams(endpoint types.APIEndpoint, inputVarName string, codecs *codecMapper) ([]string, error) {
+	var statements []string
+	for _, name := range slices.Sorted(maps.Keys(endpoint.QueryVariables)) {
+		dtype := endpoint.QueryVariables[name].Type
+		accessor := propertyAccess(inputVarName, codeName(name, generator.naming))
+		wireName := jsString(generator.naming.Apply(name))
+
+		var statement string
+		if dtype.TypeID == types.TypeID_ARRAY {
+			if len(dtype.Inner) == 0 {
+				return nil, fmt.Errorf("query parameter '%s' is an array without an element type", name)
+			}
+
+			// repeat the parameter once for each item
+			value, err := queryParamValue(dtype.ArrayElementTp(), "item", codecs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to map query parameter '%s': %w", name, err)
+			}
+			statement = fmt.Sprintf("for (const item of %s) {\n                params = params.append(%s, %s);\n            }", accessor, wireName, value)
+		} else {
+			value, err := queryParamValue(dtype, accessor, codecs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to map query parameter '%s': %w", name, err)
+			}
+			statement = fmt.Sprintf("params = params.set(%s, %s);", wireName, value)
+		}
+
+		statements = append(statements,
+			fmt.Sprintf("if (%s !== undefined && %s !== null) {\n            %s\n        }", accessor, accessor, statement))
+	}
+
+	return statements, nil
+}
+
+// queryParamValue creates an expression that formats a value as a query parameter
+func queryParamValue(dtype types.DynamicType, accessor string, codecs *codecMapper) (string, error) {
+	switch dtype.TypeID {
+	case types.TypeID_STRING, types.TypeID_INTEGER, types.TypeID_FLOAT, types.TypeID_BOOLEAN:
+		return accessor, nil
+	case types.TypeID_TIMESTAMP, types.TypeID_DATE:
+		serialized, _ := codecs.serialize(dtype, accessor, 0)
+		return serialized, nil
+	case types.TypeID_USER, types.TypeID_GENERIC, types.TypeID_ANY:
+		if serialized, ok := codecs.serialize(dtype, accessor, 0); ok {
+			accessor = serialized
+		}
+		return fmt.Sprintf("JSON.stringify(%s)", accessor), nil
+	default:
+		return "", fmt.Errorf("type %s cannot be sent as a query parameter", dtype.TypeID)
+	}
+}
+
 func (generator *NGServiceGenerator) GenerateEntity(writer io.Writer, def types.EntitySpec, resolver imports.ImportManager) error {
-	entity := translateEntity(def, resolver)
-	return generator.ngEntityTemplate.Execute(writer, entity)
+	entity, err := generator.translateEntity(def, resolver)
+	if err != nil {
+		return fmt.Errorf("failed to translate entity: %w", err)
+	}
+
+	entityTemplate, err := withTypeMapper(generator.ngEntityTemplate, newJSTypeMapper(resolver))
+	if err != nil {
+		return err
+	}
+
+	return entityTemplate.Execute(writer, entity)
 }
 
-func translateEntity(spec types.EntitySpec, importResolver imports.ImportManager) EntityDef {
+func (generator *NGServiceGenerator) translateEntity(spec types.EntitySpec, importResolver imports.ImportManager) (EntityDef, error) {
+
+	localNames := generator.entityExports(spec.Name)
+	if generator.options.Zod {
+		localNames = append(localNames, "z")
+	}
+
+	err := declareScope(importResolver, localNames...)
+	if err != nil {
+		return EntityDef{}, fmt.Errorf("failed to declare names of entity '%s': %w", spec.Name, err)
+	}
 
 	entityImports := importResolver.GetEntityImports(spec)
 
-	return EntityDef{
-		Entity:  spec,
-		Imports: entityImports,
+	// validators check values in code, so they use the names properties have in code
+	codeSpec, err := codeEntity(spec, generator.naming)
+	if err != nil {
+		return EntityDef{}, err
+	}
+
+	codec, codecImports, err := createCodec(spec, generator.converted, generator.parameterised, generator.naming, importResolver)
+	if err != nil {
+		return EntityDef{}, fmt.Errorf("failed to create codec: %w", err)
 	}
+	entityImports = imports.UnionImports(CombineTSImports, entityImports, codecImports)
+
+	var validator *ValidatorDef
+	if generator.options.Validators {
+		var validatorImports []imports.GenericImport
+		validator, validatorImports, err = createValidator(codeSpec, generator.external, importResolver)
+		if err != nil {
+			return EntityDef{}, fmt.Errorf("failed to create validator: %w", err)
+		}
+
+		entityImports = imports.UnionImports(CombineTSImports, entityImports, validatorImports)
+	}
+
+	var schema *SchemaDef
+	if generator.options.Zod {
+		var schemaImports []imports.GenericImport
+		schema, schemaImports, err = createSchema(spec, generator.naming, generator.converted, generator.parameterised, generator.external, importResolver)
+		if err != nil {
+			return EntityDef{}, fmt.Errorf("failed to create schema: %w", err)
+		}
+
+		entityImports = imports.UnionImports(CombineTSImports, entityImports, schemaImports)
+	}
+
+	return EntityDef{
+		Entity:    codeSpec,
+		Imports:   entityImports,
+		Codec:     codec,
+		Validator: validator,
+		Schema:    schema,
+	}, nil
 }
 
 func (generator *NGServiceGenerator) GenerateConfig(writer io.Writer, config types.APIConfig, resolver imports.ImportManager) error {
@@ -2,11 +2,33 @@ package jscodegen
 
 import (
 	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
 	"github.com/softwaresale/client-gen/v2/internal/types"
 	"strings"
 )
 
+// JSTypeMapper converts types into TypeScript types. If LocalName is set, referenced types are named by it, so that
+// types imported under an alias are referred to by their aliases
 type JSTypeMapper struct {
+	LocalName func(typeName string) string
+}
+
+// newJSTypeMapper creates a type mapper that names types the way the output being generated refers to them
+func newJSTypeMapper(resolver imports.ImportManager) JSTypeMapper {
+	if scoped, ok := resolver.(imports.ScopedImportManager); ok {
+		return JSTypeMapper{LocalName: scoped.LocalName}
+	}
+
+	return JSTypeMapper{}
+}
+
+// typeName gets the name a referenced type is known by
+func (mapper JSTypeMapper) typeName(reference string) string {
+	if mapper.LocalName == nil {
+		return reference
+	}
+
+	return mapper.LocalName(reference)
 }
 
 func (mapper JSTypeMapper) Convert(dtype types.DynamicType) (string, error) {
@@ -23,12 +45,16 @@ func (mapper JSTypeMapper) Convert(dtype types.DynamicType) (string, error) {
 	case types.TypeID_BOOLEAN:
 		typeStr = "boolean"
 	case types.TypeID_USER:
-		typeStr = dtype.Reference
-	case types.TypeID_TIMESTAMP:
+		typeStr = mapper.typeName(dtype.Reference)
+	case types.TypeID_TIMESTAMP, types.TypeID_DATE:
 		typeStr = "Date"
 	case types.TypeID_ANY:
 		typeStr = "any"
 	case types.TypeID_ARRAY:
+		if len(dtype.Inner) == 0 {
+			return "", fmt.Errorf("array type does not have an element type")
+		}
+
 		// get the inner type
 		innerTypeStr, err := mapper.Convert(dtype.ArrayElementTp())
 		if err != nil {
@@ -46,7 +72,7 @@ func (mapper JSTypeMapper) Convert(dtype types.DynamicType) (string, error) {
 
 			genericParams = append(genericParams, innerTypeStr)
 		}
-		typeStr = fmt.Sprintf("%s<%s>", dtype.Reference, strings.Join(genericParams, ", "))
+		typeStr = fmt.Sprintf("%s<%s>", mapper.typeName(dtype.Reference), strings.Join(genericParams, ", "))
 
 	default:
 		return "", fmt.Errorf("unknown type ID %s", dtype.TypeID)
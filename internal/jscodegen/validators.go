@@ -0,0 +1,164 @@
+package jscodegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ValidatorDef defines a runtime validator function for an entity
+type ValidatorDef struct {
+	FunctionName string   // name of the validator function
+	EntityName   string   // name of the entity being validated
+	Checks       []string // statements that push problems onto the errors array
+}
+
+var formatPatterns = map[string]string{
+	types.Format_EMAIL: `/^[^\s@]+@[^\s@]+\.[^\s@]+$/`,
+	types.Format_UUID:  `/^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i`,
+}
+
+// validatorFunctionName gets the name of the function that validates the named entity
+func validatorFunctionName(entityName string) string {
+	return fmt.Sprintf("validate%s", entityName)
+}
+
+// createValidator creates a validator for an entity. Validators for referenced entities are called to validate nested
+// values, and the imports for them are returned alongside the validator. External types have no validators, so values
+// of them are not checked
+func createValidator(entity types.EntitySpec, external map[string]bool, resolver imports.ImportManager) (*ValidatorDef, []imports.GenericImport, error) {
+	validator := &ValidatorDef{
+		FunctionName: validatorFunctionName(entity.Name),
+		EntityName:   entity.Name,
+	}
+
+	nestedValidators := make(map[string]bool)
+	for _, propName := range slices.Sorted(maps.Keys(entity.Properties)) {
+		propSpec := entity.Properties[propName]
+		accessor := propertyAccess("value", propName)
+
+		if propSpec.Required {
+			validator.Checks = append(validator.Checks,
+				fmt.Sprintf("if (%s === undefined || %s === null) { errors.push(%s); }", accessor, accessor, jsString(propName+" is required")))
+		}
+
+		checks, err := createValueChecks(accessor, propName, propSpec.Type, propSpec.PropertyConstraints, nestedValidators, external)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create checks for property '%s': %w", propName, err)
+		}
+
+		if len(checks) == 0 {
+			continue
+		}
+
+		validator.Checks = append(validator.Checks,
+			fmt.Sprintf("if (%s !== undefined && %s !== null) {\n        %s\n    }", accessor, accessor, strings.Join(checks, "\n        ")))
+	}
+
+	// import the validators of referenced entities
+	var validatorImports []imports.GenericImport
+	for _, referenced := range slices.Sorted(maps.Keys(nestedValidators)) {
+		if referenced == entity.Name {
+			continue
+		}
+
+		entityImport, err := resolver.GetImportForType(referenced)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to import validator for '%s': %w", referenced, err)
+		}
+
+		validatorImports = append(validatorImports, &TSImport{
+			File:          entityImport.Provider(),
+			ProvidedTypes: []string{validatorFunctionName(referenced)},
+		})
+	}
+
+	return validator, validatorImports, nil
+}
+
+// createValueChecks creates statements that check a defined value against its type's constraints
+func createValueChecks(accessor, path string, dtype types.DynamicType, constraints types.PropertyConstraints, nestedValidators map[string]bool, external map[string]bool) ([]string, error) {
+	var checks []string
+	check := func(condition, problem string) {
+		checks = append(checks, fmt.Sprintf("if (%s) { errors.push(%s); }", condition, jsString(fmt.Sprintf("%s %s", path, problem))))
+	}
+
+	if constraints.Minimum != nil {
+		check(fmt.Sprintf("%s < %v", accessor, *constraints.Minimum), fmt.Sprintf("must be at least %v", *constraints.Minimum))
+	}
+
+	if constraints.Maximum != nil {
+		check(fmt.Sprintf("%s > %v", accessor, *constraints.Maximum), fmt.Sprintf("must be at most %v", *constraints.Maximum))
+	}
+
+	if constraints.MinLength != nil {
+		check(fmt.Sprintf("%s.length < %d", accessor, *constraints.MinLength), fmt.Sprintf("must be at least %d characters long", *constraints.MinLength))
+	}
+
+	if constraints.MaxLength != nil {
+		check(fmt.Sprintf("%s.length > %d", accessor, *constraints.MaxLength), fmt.Sprintf("must be at most %d characters long", *constraints.MaxLength))
+	}
+
+	if len(constraints.Pattern) > 0 {
+		check(fmt.Sprintf("!new RegExp(%s).test(%s)", jsString(constraints.Pattern), accessor), fmt.Sprintf("must match %s", constraints.Pattern))
+	}
+
+	if len(constraints.Format) > 0 {
+		if constraints.Format == types.Format_URI {
+			check(fmt.Sprintf("!URL.canParse(%s)", accessor), "must be a valid URI")
+		} else if pattern, ok := formatPatterns[constraints.Format]; ok {
+			check(fmt.Sprintf("!%s.test(%s)", pattern, accessor), fmt.Sprintf("must be a valid %s", constraints.Format))
+		} else {
+			return nil, fmt.Errorf("unknown format '%s'", constraints.Format)
+		}
+	}
+
+	if len(constraints.Enum) > 0 {
+		enumValues, err := json.Marshal(constraints.Enum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode enum values: %w", err)
+		}
+
+		check(fmt.Sprintf("!(%s as unknown[]).includes(%s)", string(enumValues), accessor), fmt.Sprintf("must be one of %s", string(enumValues)))
+	}
+
+	if constraints.MinItems != nil {
+		check(fmt.Sprintf("%s.length < %d", accessor, *constraints.MinItems), fmt.Sprintf("must have at least %d items", *constraints.MinItems))
+	}
+
+	if constraints.MaxItems != nil {
+		check(fmt.Sprintf("%s.length > %d", accessor, *constraints.MaxItems), fmt.Sprintf("must have at most %d items", *constraints.MaxItems))
+	}
+
+	switch dtype.TypeID {
+	case types.TypeID_USER:
+		if external[dtype.Reference] {
+			break
+		}
+
+		nestedValidators[dtype.Reference] = true
+		checks = append(checks, fmt.Sprintf("errors.push(...%s(%s).map(problem => %s + problem));", validatorFunctionName(dtype.Reference), accessor, jsString(path+".")))
+	case types.TypeID_ARRAY:
+		if len(dtype.Inner) == 0 {
+			return nil, fmt.Errorf("array type does not have an element type")
+		}
+
+		elementTp := dtype.ArrayElementTp()
+		if elementTp.TypeID == types.TypeID_USER && !external[elementTp.Reference] {
+			nestedValidators[elementTp.Reference] = true
+			checks = append(checks, fmt.Sprintf("%s.forEach((item, idx) => errors.push(...%s(item).map(problem => `%s[${idx}].` + problem)));", accessor, validatorFunctionName(elementTp.Reference), path))
+		}
+	}
+
+	return checks, nil
+}
+
+// jsString quotes text as a javascript string literal
+func jsString(text string) string {
+	quoted, _ := json.Marshal(text)
+	return string(quoted)
+}
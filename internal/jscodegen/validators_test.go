@@ -0,0 +1,122 @@
+package jscodegen
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+func TestCreateValidator_ChecksConstraints(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Person",
+		Properties: map[string]types.PropertySpec{
+			"age": {
+				Type:                types.DynamicType{TypeID: types.TypeID_INTEGER},
+				PropertyConstraints: types.PropertyConstraints{Minimum: floatPtr(0), Maximum: floatPtr(150)},
+			},
+			"email": {
+				Type:                types.DynamicType{TypeID: types.TypeID_STRING},
+				Required:            true,
+				PropertyConstraints: types.PropertyConstraints{MaxLength: intPtr(100), Format: types.Format_EMAIL},
+			},
+			"status": {
+				Type:                types.DynamicType{TypeID: types.TypeID_STRING},
+				PropertyConstraints: types.PropertyConstraints{Enum: []any{"active", "retired"}},
+			},
+		},
+	}
+
+	validator, validatorImports, err := createValidator(entity, nil, &importManager)
+	assert.NoError(t, err)
+	assert.Empty(t, validatorImports)
+
+	assert.Equal(t, "validatePerson", validator.FunctionName)
+	assert.Len(t, validator.Checks, 4)
+	assert.Contains(t, validator.Checks[0], `if (value.age < 0) { errors.push("age must be at least 0"); }`)
+	assert.Contains(t, validator.Checks[0], `if (value.age > 150) { errors.push("age must be at most 150"); }`)
+	assert.Equal(t, `if (value.email === undefined || value.email === null) { errors.push("email is required"); }`, validator.Checks[1])
+	assert.Contains(t, validator.Checks[2], "value.email.length > 100")
+	assert.Contains(t, validator.Checks[2], "email must be a valid email")
+	assert.Contains(t, validator.Checks[3], `(["active","retired"] as unknown[]).includes(value.status)`)
+}
+
+func TestCreateValidator_ValidatesNestedEntities(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("person.model.gen.ts", "Person")
+
+	entity := types.EntitySpec{
+		Name: "Team",
+		Properties: map[string]types.PropertySpec{
+			"members": {
+				Type: types.DynamicType{
+					TypeID: types.TypeID_ARRAY,
+					Inner:  []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}},
+				},
+				PropertyConstraints: types.PropertyConstraints{MinItems: intPtr(1)},
+			},
+		},
+	}
+
+	validator, validatorImports, err := createValidator(entity, nil, &importManager)
+	assert.NoError(t, err)
+
+	assert.Len(t, validatorImports, 1)
+	assert.Equal(t, "./person.model.gen", validatorImports[0].Provider())
+	assert.Equal(t, []string{"validatePerson"}, validatorImports[0].ProvidedEntities())
+
+	assert.Contains(t, validator.Checks[0], `if (value.members.length < 1) { errors.push("members must have at least 1 items"); }`)
+	assert.Contains(t, validator.Checks[0], "validatePerson(item)")
+}
+
+func TestNGServiceGenerator_GenerateEntity_RendersValidatorWhenEnabled(t *testing.T) {
+	entity := types.EntitySpec{
+		Name: "Person",
+		Properties: map[string]types.PropertySpec{
+			"name": {
+				Type:                types.DynamicType{TypeID: types.TypeID_STRING},
+				PropertyConstraints: types.PropertyConstraints{Pattern: "^[A-Z]"},
+			},
+		},
+	}
+
+	importManager := NewTSImportManager()
+	var withoutValidators bytes.Buffer
+	err := NewNGServiceGenerator().GenerateEntity(&withoutValidators, entity, &importManager)
+	assert.NoError(t, err)
+	assert.NotContains(t, withoutValidators.String(), "validatePerson")
+
+	var withValidators bytes.Buffer
+	err = NewNGServiceGeneratorWithOptions(NGOptions{Validators: true}).GenerateEntity(&withValidators, entity, &importManager)
+	assert.NoError(t, err)
+	assert.Contains(t, withValidators.String(), "export function validatePerson(value: Person): string[] {")
+	assert.Contains(t, withValidators.String(), `if (!new RegExp("^[A-Z]").test(value.name)) { errors.push("name must match ^[A-Z]"); }`)
+}
+
+func TestCreateValidator_SkipsExternalTypes(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterExternalType("@acme/common-models", "Money", "Money")
+
+	entity := types.EntitySpec{
+		Name: "Order",
+		Properties: map[string]types.PropertySpec{
+			"total": {Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Money"}, Required: true},
+		},
+	}
+
+	validator, validatorImports, err := createValidator(entity, map[string]bool{"Money": true}, &importManager)
+	assert.NoError(t, err)
+	assert.Empty(t, validatorImports)
+	assert.Len(t, validator.Checks, 1)
+	assert.NotContains(t, validator.Checks[0], "validateMoney")
+}
@@ -0,0 +1,355 @@
+package jscodegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/codegen/imports"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// zodProvider is the module that provides zod
+const zodProvider = "zod"
+
+// SchemaDef defines a zod schema that parses an entity as it is sent on the wire
+type SchemaDef struct {
+	SchemaName  string              // name of the schema constant
+	SchemaType  string              // type of the schema constant
+	FactoryName string              // optional function that creates the schema of a parameterised entity
+	Param       string              // name of the schema that the factory is passed for the argument of the entity
+	EntityName  string              // name of the entity the schema parses
+	Properties  []SchemaPropertyDef // schemas of each property, sorted by wire name
+}
+
+// SchemaPropertyDef defines the schema of a single entity property
+type SchemaPropertyDef struct {
+	Key    string // object key of the property
+	Schema string // zod expression that parses the property
+}
+
+// schemaName gets the name of the zod schema for the named entity
+func schemaName(entityName string) string {
+	return fmt.Sprintf("%sSchema", entityName)
+}
+
+// schemaFactoryName gets the name of the function that creates the zod schema of the named parameterised entity
+func schemaFactoryName(entityName string) string {
+	return fmt.Sprintf("create%sSchema", entityName)
+}
+
+// schemaParam is the name of the schema that the factory of a parameterised entity is passed for its argument
+const schemaParam = "param"
+
+// ZodSchemaMapper maps dynamic types into zod schema expressions. Every entity schema that an expression refers to is
+// recorded so that it can be imported
+type ZodSchemaMapper struct {
+	resolver      imports.ImportManager
+	types         JSTypeMapper               // names the types of values that are accepted as-is
+	referenced    map[string]map[string]bool // entity name -> schemas and factories used
+	external      map[string]bool            // external types, which have no schemas and are accepted as-is
+	parameterised map[string]bool            // generic entities whose schemas are created for their argument
+	param         string                     // optional schema of values typed as any
+	lazy          bool                       // if true, entity schemas are referenced lazily
+}
+
+// newZodSchemaMapper creates a schema mapper. Schemas that are defined alongside other schemas should reference
+// entities lazily so that recursive and mutually dependent entities can be parsed
+func newZodSchemaMapper(resolver imports.ImportManager, external, parameterised map[string]bool, lazy bool) *ZodSchemaMapper {
+	return &ZodSchemaMapper{
+		resolver:      resolver,
+		types:         newJSTypeMapper(resolver),
+		referenced:    make(map[string]map[string]bool),
+		external:      external,
+		parameterised: parameterised,
+		lazy:          lazy,
+	}
+}
+
+// Convert maps a type into a zod schema expression
+func (mapper *ZodSchemaMapper) Convert(dtype types.DynamicType) (string, error) {
+	switch dtype.TypeID {
+	case types.TypeID_VOID:
+		return "z.void()", nil
+	case types.TypeID_STRING:
+		return "z.string()", nil
+	case types.TypeID_INTEGER:
+		return "z.number().int()", nil
+	case types.TypeID_FLOAT:
+		return "z.number()", nil
+	case types.TypeID_BOOLEAN:
+		return "z.boolean()", nil
+	case types.TypeID_TIMESTAMP:
+		// timestamps are sent as ISO-8601 strings. Schemas parse the wire format, so they are only turned into dates
+		// when the response is revived
+		return "z.string().datetime({ offset: true })", nil
+	case types.TypeID_DATE:
+		return "z.string().date()", nil
+	case types.TypeID_ANY:
+		if len(mapper.param) > 0 {
+			return mapper.param, nil
+		}
+
+		return "z.any()", nil
+	case types.TypeID_USER:
+		if mapper.external[dtype.Reference] {
+			return fmt.Sprintf("z.custom<%s>()", mapper.types.typeName(dtype.Reference)), nil
+		}
+
+		return mapper.entityReference(dtype.Reference), nil
+	case types.TypeID_ARRAY:
+		if len(dtype.Inner) == 0 {
+			return "", fmt.Errorf("array type does not have an element type")
+		}
+
+		elementSchema, err := mapper.Convert(dtype.ArrayElementTp())
+		if err != nil {
+			return "", fmt.Errorf("failed to map array inner type: %w", err)
+		}
+
+		return fmt.Sprintf("z.array(%s)", elementSchema), nil
+	case types.TypeID_GENERIC:
+		return mapper.convertGeneric(dtype)
+	default:
+		return "", fmt.Errorf("unknown type ID %s", dtype.TypeID)
+	}
+}
+
+// ConvertProperty maps a property into a zod schema expression, including its constraints
+func (mapper *ZodSchemaMapper) ConvertProperty(prop types.PropertySpec) (string, error) {
+	schema, err := mapper.Convert(prop.Type)
+	if err != nil {
+		return "", err
+	}
+
+	refinements, err := zodRefinements(prop.PropertyConstraints)
+	if err != nil {
+		return "", err
+	}
+
+	schema += strings.Join(refinements, "")
+	if !prop.Required {
+		// optional properties may be sent as null as well as left out, just like revivers and validators accept
+		schema += ".nullish()"
+	}
+
+	return schema, nil
+}
+
+// Imports gets imports for every entity schema referenced by converted expressions, except for the given entity
+func (mapper *ZodSchemaMapper) Imports(except string) ([]imports.GenericImport, error) {
+	schemaImports := []imports.GenericImport{
+		&TSImport{File: zodProvider, ProvidedTypes: []string{"z"}},
+	}
+
+	for _, referenced := range slices.Sorted(maps.Keys(mapper.referenced)) {
+		if referenced == except {
+			continue
+		}
+
+		entityImport, err := mapper.resolver.GetImportForType(referenced)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import schema for '%s': %w", referenced, err)
+		}
+
+		schemaImports = append(schemaImports, &TSImport{
+			File:          entityImport.Provider(),
+			ProvidedTypes: slices.Sorted(maps.Keys(mapper.referenced[referenced])),
+		})
+	}
+
+	return schemaImports, nil
+}
+
+// entityReference refers to the schema of an entity
+func (mapper *ZodSchemaMapper) entityReference(entityName string) string {
+	return mapper.reference(entityName, schemaName(entityName), schemaName(entityName))
+}
+
+// factoryReference creates the schema of a parameterised entity for its argument
+func (mapper *ZodSchemaMapper) factoryReference(entityName, param string) string {
+	factoryName := schemaFactoryName(entityName)
+	return mapper.reference(entityName, factoryName, fmt.Sprintf("%s(%s)", factoryName, param))
+}
+
+// reference records that the named schema or factory of an entity is used by an expression
+func (mapper *ZodSchemaMapper) reference(entityName, name, expression string) string {
+	if _, exists := mapper.referenced[entityName]; !exists {
+		mapper.referenced[entityName] = make(map[string]bool)
+	}
+	mapper.referenced[entityName][name] = true
+
+	if !mapper.lazy {
+		return expression
+	}
+
+	return fmt.Sprintf("z.lazy(() => %s)", expression)
+}
+
+// convertGeneric maps generic types. Well-known collection types are mapped onto the JSON values they are sent as.
+// Parameterised entities have their schemas created for their argument, while other generic entities are parsed by
+// their own schema, whose values typed as any accept their arguments. Anything else is accepted as-is
+func (mapper *ZodSchemaMapper) convertGeneric(dtype types.DynamicType) (string, error) {
+	switch {
+	case (dtype.Reference == "Array" || dtype.Reference == "Set") && len(dtype.Inner) == 1:
+		// sets are sent as arrays
+		params, err := mapper.convertParams(dtype)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("z.array(%s)", params[0]), nil
+	case dtype.Reference == "Record" && len(dtype.Inner) == 2:
+		params, err := mapper.convertParams(dtype)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("z.record(%s, %s)", params[0], params[1]), nil
+	case dtype.Reference == "Map" && len(dtype.Inner) == 2:
+		params, err := mapper.convertParams(dtype)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("z.map(%s, %s)", params[0], params[1]), nil
+	}
+
+	if _, err := mapper.resolver.GetImportForType(dtype.Reference); err == nil && !mapper.external[dtype.Reference] {
+		if !mapper.parameterised[dtype.Reference] || len(dtype.Inner) != 1 {
+			return mapper.entityReference(dtype.Reference), nil
+		}
+
+		params, err := mapper.convertParams(dtype)
+		if err != nil {
+			return "", err
+		}
+
+		return mapper.factoryReference(dtype.Reference, params[0]), nil
+	}
+
+	tsType, err := mapper.types.Convert(dtype)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("z.custom<%s>()", tsType), nil
+}
+
+// convertParams maps the arguments of a generic type
+func (mapper *ZodSchemaMapper) convertParams(dtype types.DynamicType) ([]string, error) {
+	var params []string
+	for genericIdx, inner := range dtype.Inner {
+		innerSchema, err := mapper.Convert(inner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map generic inner type at index %d: %w", genericIdx, err)
+		}
+
+		params = append(params, innerSchema)
+	}
+
+	return params, nil
+}
+
+// zodRefinements maps property constraints onto zod refinements
+func zodRefinements(constraints types.PropertyConstraints) ([]string, error) {
+	var refinements []string
+	if constraints.Minimum != nil {
+		refinements = append(refinements, fmt.Sprintf(".min(%v)", *constraints.Minimum))
+	}
+
+	if constraints.Maximum != nil {
+		refinements = append(refinements, fmt.Sprintf(".max(%v)", *constraints.Maximum))
+	}
+
+	if constraints.MinLength != nil {
+		refinements = append(refinements, fmt.Sprintf(".min(%d)", *constraints.MinLength))
+	}
+
+	if constraints.MaxLength != nil {
+		refinements = append(refinements, fmt.Sprintf(".max(%d)", *constraints.MaxLength))
+	}
+
+	if len(constraints.Pattern) > 0 {
+		refinements = append(refinements, fmt.Sprintf(".regex(new RegExp(%s))", jsString(constraints.Pattern)))
+	}
+
+	switch constraints.Format {
+	case "":
+	case types.Format_EMAIL:
+		refinements = append(refinements, ".email()")
+	case types.Format_UUID:
+		refinements = append(refinements, ".uuid()")
+	case types.Format_URI:
+		refinements = append(refinements, ".url()")
+	default:
+		return nil, fmt.Errorf("unknown format '%s'", constraints.Format)
+	}
+
+	if constraints.MinItems != nil {
+		refinements = append(refinements, fmt.Sprintf(".min(%d)", *constraints.MinItems))
+	}
+
+	if constraints.MaxItems != nil {
+		refinements = append(refinements, fmt.Sprintf(".max(%d)", *constraints.MaxItems))
+	}
+
+	if len(constraints.Enum) > 0 {
+		enumValues, err := json.Marshal(constraints.Enum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode enum values: %w", err)
+		}
+
+		refinements = append(refinements, fmt.Sprintf(".refine(value => (%s as unknown[]).includes(value), { message: %s })",
+			string(enumValues), jsString(fmt.Sprintf("must be one of %s", string(enumValues)))))
+	}
+
+	return refinements, nil
+}
+
+// createSchema creates a zod schema for an entity, along with the imports it needs. Schemas parse entities as they are
+// sent on the wire, before they are revived, so they use wire names. Schemas of entities that are converted are not
+// typed as the entity, as they parse values that still have to be revived
+func createSchema(entity types.EntitySpec, naming types.NamingStrategy, converted, parameterised, external map[string]bool, resolver imports.ImportManager) (*SchemaDef, []imports.GenericImport, error) {
+	mapper := newZodSchemaMapper(resolver, external, parameterised, true)
+	schema := &SchemaDef{
+		SchemaName: schemaName(entity.Name),
+		SchemaType: fmt.Sprintf("z.ZodType<%s>", entity.Name),
+		EntityName: entity.Name,
+	}
+
+	if converted[entity.Name] {
+		schema.SchemaType = "z.ZodTypeAny"
+	}
+
+	if parameterised[entity.Name] {
+		schema.FactoryName = schemaFactoryName(entity.Name)
+		schema.Param, mapper.param = schemaParam, schemaParam
+	}
+
+	wireNames := make(map[string]string, len(entity.Properties))
+	for propName, propSpec := range entity.Properties {
+		wireNames[propSpec.ResolveWireName(propName, naming)] = propName
+	}
+
+	for _, wireName := range slices.Sorted(maps.Keys(wireNames)) {
+		propName := wireNames[wireName]
+		propSchema, err := mapper.ConvertProperty(entity.Properties[propName])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create schema for property '%s': %w", propName, err)
+		}
+
+		schema.Properties = append(schema.Properties, SchemaPropertyDef{
+			Key:    objectKey(wireName),
+			Schema: propSchema,
+		})
+	}
+
+	schemaImports, err := mapper.Imports(entity.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schema, schemaImports, nil
+}
@@ -0,0 +1,244 @@
+package jscodegen
+
+import (
+	"bytes"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestZodSchemaMapper_Convert(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./page.model.gen", "Page")
+	mapper := newZodSchemaMapper(&importManager, nil, nil, true)
+
+	tests := []struct {
+		dtype    types.DynamicType
+		expected string
+	}{
+		{types.DynamicType{TypeID: types.TypeID_STRING}, "z.string()"},
+		{types.DynamicType{TypeID: types.TypeID_INTEGER}, "z.number().int()"},
+		{types.DynamicType{TypeID: types.TypeID_TIMESTAMP}, "z.string().datetime({ offset: true })"},
+		{types.DynamicType{TypeID: types.TypeID_DATE}, "z.string().date()"},
+		{types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, "z.lazy(() => PersonSchema)"},
+		{
+			types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_BOOLEAN}}},
+			"z.array(z.boolean())",
+		},
+		{
+			types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Record", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}, {TypeID: types.TypeID_FLOAT}}},
+			"z.record(z.string(), z.number())",
+		},
+		{
+			types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}}},
+			"z.lazy(() => PageSchema)",
+		},
+		{
+			types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Unknown", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}}},
+			"z.custom<Unknown<string>>()",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := mapper.Convert(test.dtype)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+}
+
+func TestCreateSchema_AppliesConstraintsAndImportsReferences(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./person.model.gen", "Person")
+	importManager.RegisterType("./team.model.gen", "Team")
+
+	entity := types.EntitySpec{
+		Name: "Team",
+		Properties: map[string]types.PropertySpec{
+			"lead": {
+				Type:     types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"},
+				Required: true,
+			},
+			"name": {
+				Type:                types.DynamicType{TypeID: types.TypeID_STRING},
+				PropertyConstraints: types.PropertyConstraints{MaxLength: intPtr(20)},
+			},
+			"parent": {
+				Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Team"},
+			},
+		},
+	}
+
+	schema, schemaImports, err := createSchema(entity, types.NamingStrategy_PRESERVE, nil, nil, nil, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TeamSchema", schema.SchemaName)
+	assert.Equal(t, []SchemaPropertyDef{
+		{Key: "lead", Schema: "z.lazy(() => PersonSchema)"},
+		{Key: "name", Schema: "z.string().max(20).nullish()"},
+		{Key: "parent", Schema: "z.lazy(() => TeamSchema).nullish()"},
+	}, schema.Properties)
+
+	assert.Len(t, schemaImports, 2)
+	assert.Equal(t, zodProvider, schemaImports[0].Provider())
+	assert.Equal(t, "./person.model.gen", schemaImports[1].Provider())
+	assert.Equal(t, []string{"PersonSchema"}, schemaImports[1].ProvidedEntities())
+}
+
+func TestNGServiceGenerator_GenerateService_ParsesResponsesWithZod(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./person.model.gen", "Person")
+	importManager.RegisterType("./api-config.config.gen", "APIConfig")
+
+	service := types.ServiceDefinition{
+		Name: "Person",
+		Endpoints: []types.APIEndpoint{
+			{
+				Name:         "getAll",
+				Endpoint:     "/people",
+				Method:       "GET",
+				RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+				ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}},
+			},
+		},
+	}
+
+	var output bytes.Buffer
+	err := NewNGServiceGeneratorWithOptions(NGOptions{Zod: true}).GenerateService(&output, service, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), `import { map, Observable } from "rxjs";`)
+	assert.Contains(t, output.String(), "import { Person,PersonSchema, } from './person.model.gen';")
+	assert.Contains(t, output.String(), ".pipe(map(response => z.array(PersonSchema).parse(response)));")
+}
+
+func TestZodSchemaMapper_Convert_AcceptsExternalTypesAsIs(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterExternalType("@acme/common-models", "Money", "Money")
+	mapper := newZodSchemaMapper(&importManager, map[string]bool{"Money": true}, nil, true)
+
+	result, err := mapper.Convert(types.DynamicType{TypeID: types.TypeID_USER, Reference: "Money"})
+	assert.NoError(t, err)
+	assert.Equal(t, "z.custom<Money>()", result)
+
+	schemaImports, err := mapper.Imports("")
+	assert.NoError(t, err)
+	assert.Len(t, schemaImports, 1)
+}
+
+func TestZodSchemaMapper_Convert_CreatesSchemasOfParameterisedEntities(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./page.model.gen", "Page")
+	importManager.RegisterType("./person.model.gen", "Person")
+	importManager.RegisterType("./wrapper.model.gen", "Wrapper")
+	mapper := newZodSchemaMapper(&importManager, nil, map[string]bool{"Page": true}, false)
+
+	personType := types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+	result, err := mapper.Convert(types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{personType}})
+	assert.NoError(t, err)
+	assert.Equal(t, "createPageSchema(PersonSchema)", result)
+
+	// the arguments of entities that are not parameterised are never parsed, so their schemas are not imported
+	result, err = mapper.Convert(types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Wrapper", Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Tag"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "WrapperSchema", result)
+
+	schemaImports, err := mapper.Imports("")
+	assert.NoError(t, err)
+	assert.Len(t, schemaImports, 4)
+	assert.Equal(t, []string{"createPageSchema"}, schemaImports[1].ProvidedEntities())
+	assert.Equal(t, []string{"PersonSchema"}, schemaImports[2].ProvidedEntities())
+	assert.Equal(t, []string{"WrapperSchema"}, schemaImports[3].ProvidedEntities())
+}
+
+func TestCreateSchema_ParsesWireFormat(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Page",
+		Properties: map[string]types.PropertySpec{
+			"content":    {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}, Required: true},
+			"totalPages": {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true},
+		},
+	}
+
+	converted := map[string]bool{"Page": true}
+	schema, _, err := createSchema(entity, types.NamingStrategy_SNAKE, converted, converted, nil, &importManager)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "z.ZodTypeAny", schema.SchemaType)
+	assert.Equal(t, "createPageSchema", schema.FactoryName)
+	assert.Equal(t, []SchemaPropertyDef{
+		{Key: "content", Schema: "z.array(param)"},
+		{Key: "total_pages", Schema: "z.number().int()"},
+	}, schema.Properties)
+}
+
+func TestNGServiceGenerator_GenerateService_ParsesResponsesBeforeRevivingThem(t *testing.T) {
+	importManager := NewTSImportManager()
+	importManager.RegisterType("./page.model.gen", "Page")
+	importManager.RegisterType("./person.model.gen", "Person")
+	importManager.RegisterType("./api-config.config.gen", "APIConfig")
+
+	pageType := types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}
+	service := types.ServiceDefinition{
+		Name: "People",
+		Endpoints: []types.APIEndpoint{
+			{Name: "list", Endpoint: "/people", Method: "GET", RequestBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}}, ResponseBody: types.RequestValue{Type: pageType}},
+		},
+	}
+	api := types.APIDefinition{
+		Entities: []types.EntitySpec{
+			{Name: "Page", Properties: map[string]types.PropertySpec{"content": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}}}},
+			{Name: "Person", Properties: map[string]types.PropertySpec{"born": {Type: timestampType}}},
+		},
+		Services: []types.ServiceDefinition{service},
+	}
+
+	generator := NewNGServiceGeneratorWithOptions(NGOptions{Zod: true})
+	assert.NoError(t, generator.PrepareAPI(api))
+
+	var output bytes.Buffer
+	err := generator.GenerateService(&output, service, &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "import { Page,createPageSchema,revivePage, } from './page.model.gen';")
+	assert.Contains(t, output.String(), "import { Person,PersonSchema,revivePerson, } from './person.model.gen';")
+	assert.Contains(t, output.String(), ".pipe(map(response => createPageSchema(PersonSchema).parse(response)), "+
+		"map(response => response == null ? response : revivePage(response, (item0: any) => item0 == null ? item0 : revivePerson(item0))));")
+
+	output.Reset()
+	err = generator.GenerateEntity(&output, api.Entities[0], &importManager)
+	assert.NoError(t, err)
+
+	assert.Contains(t, output.String(), "export const createPageSchema = (param: z.ZodTypeAny): z.ZodTypeAny => z.object({")
+	assert.Contains(t, output.String(), "content: z.array(param).nullish(),")
+	assert.Contains(t, output.String(), "export const PageSchema: z.ZodTypeAny = createPageSchema(z.any());")
+}
+
+func TestCreateSchema_RejectsNullRequiredDatesAndAcceptsMissingOptionalOnes(t *testing.T) {
+	importManager := NewTSImportManager()
+
+	entity := types.EntitySpec{
+		Name: "Event",
+		Properties: map[string]types.PropertySpec{
+			"starts":   {Type: timestampType, Required: true},
+			"ends":     {Type: timestampType},
+			"day":      {Type: types.DynamicType{TypeID: types.TypeID_DATE}, Required: true},
+			"holiday":  {Type: types.DynamicType{TypeID: types.TypeID_DATE}},
+			"location": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+		},
+	}
+
+	converted := map[string]bool{"Event": true}
+	schema, _, err := createSchema(entity, types.NamingStrategy_CAMEL, converted, nil, nil, &importManager)
+	assert.NoError(t, err)
+
+	// dates are not coerced, which would turn null into the epoch, and optional values may be null or missing
+	assert.Equal(t, []SchemaPropertyDef{
+		{Key: "day", Schema: "z.string().date()"},
+		{Key: "ends", Schema: "z.string().datetime({ offset: true }).nullish()"},
+		{Key: "holiday", Schema: "z.string().date().nullish()"},
+		{Key: "location", Schema: "z.string().nullish()"},
+		{Key: "starts", Schema: "z.string().datetime({ offset: true })"},
+	}, schema.Properties)
+}
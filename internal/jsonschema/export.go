@@ -0,0 +1,202 @@
+package jsonschema
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"slices"
+)
+
+// ExportOptions configures how entities are exported
+type ExportOptions struct {
+	ID   string // optional $id of the document
+	Root string // optional entity that the document itself describes. Every entity is still declared under $defs
+}
+
+// Export builds a JSON Schema document that declares every entity of the API under $defs. Properties are named the
+// way they are sent on the wire, so the document validates the payloads that the API exchanges
+func Export(apiDef types.APIDefinition, opts ExportOptions) (*Schema, error) {
+	exporter := exporter{
+		apiDef:   apiDef,
+		entities: make(map[string]bool),
+	}
+	for _, entity := range apiDef.Entities {
+		exporter.entities[entity.Name] = true
+	}
+
+	document := &Schema{
+		Schema: Draft,
+		ID:     opts.ID,
+		Title:  apiDef.Name,
+		Defs:   make(map[string]*Schema, len(apiDef.Entities)),
+	}
+
+	for _, entity := range apiDef.Entities {
+		entitySchema, err := exporter.EntitySchema(entity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export entity '%s': %w", entity.Name, err)
+		}
+
+		document.Defs[entity.Name] = entitySchema
+	}
+
+	if len(opts.Root) > 0 {
+		if !exporter.entities[opts.Root] {
+			return nil, fmt.Errorf("unknown entity '%s'", opts.Root)
+		}
+
+		document.Ref = defRef(opts.Root)
+	}
+
+	return document, nil
+}
+
+// exporter converts entities and types into schemas
+type exporter struct {
+	apiDef   types.APIDefinition
+	entities map[string]bool // names of the entities that are declared under $defs
+}
+
+// EntitySchema converts an entity into an object schema. Its required list holds the properties that are required
+func (exporter exporter) EntitySchema(entity types.EntitySpec) (*Schema, error) {
+	schema := &Schema{
+		Type:       Types{"object"},
+		Properties: make(map[string]*Schema, len(entity.Properties)),
+	}
+	document(schema, entity.Documentation)
+
+	for name, property := range entity.Properties {
+		wireName := property.ResolveWireName(name, exporter.apiDef.Naming)
+
+		propertySchema, err := exporter.PropertySchema(property)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export property '%s': %w", name, err)
+		}
+
+		schema.Properties[wireName] = propertySchema
+		if property.Required {
+			schema.Required = append(schema.Required, wireName)
+		}
+	}
+	slices.Sort(schema.Required)
+
+	return schema, nil
+}
+
+// PropertySchema converts a property into a schema of its type, along with its constraints and documentation
+func (exporter exporter) PropertySchema(property types.PropertySpec) (*Schema, error) {
+	schema, err := exporter.TypeSchema(property.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := property.PropertyConstraints
+	if !constraints.IsEmpty() && len(schema.Ref) > 0 {
+		// keywords next to a $ref apply as well, but keep the reference on its own to make the document easier to read
+		schema = &Schema{AllOf: []*Schema{schema}}
+	}
+
+	schema.Minimum, schema.Maximum = constraints.Minimum, constraints.Maximum
+	schema.MinLength, schema.MaxLength = constraints.MinLength, constraints.MaxLength
+	schema.MinItems, schema.MaxItems = constraints.MinItems, constraints.MaxItems
+	schema.Pattern = constraints.Pattern
+	schema.Enum = constraints.Enum
+	if len(constraints.Format) > 0 {
+		schema.Format = constraints.Format
+	}
+
+	document(schema, property.Documentation)
+	return schema, nil
+}
+
+// TypeSchema converts a dynamic type into a schema. Entities are referenced from $defs
+func (exporter exporter) TypeSchema(dtype types.DynamicType) (*Schema, error) {
+	switch dtype.TypeID {
+	case types.TypeID_STRING:
+		return &Schema{Type: Types{"string"}}, nil
+	case types.TypeID_INTEGER:
+		return &Schema{Type: Types{"integer"}}, nil
+	case types.TypeID_FLOAT:
+		return &Schema{Type: Types{"number"}}, nil
+	case types.TypeID_BOOLEAN:
+		return &Schema{Type: Types{"boolean"}}, nil
+	case types.TypeID_TIMESTAMP:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}, nil
+	case types.TypeID_DATE:
+		return &Schema{Type: Types{"string"}, Format: "date"}, nil
+	case types.TypeID_ANY:
+		return &Schema{}, nil
+	case types.TypeID_VOID:
+		return &Schema{Type: Types{"null"}}, nil
+
+	case types.TypeID_ARRAY:
+		if len(dtype.Inner) == 0 {
+			return nil, fmt.Errorf("array type does not have an element type")
+		}
+
+		items, err := exporter.TypeSchema(dtype.ArrayElementTp())
+		if err != nil {
+			return nil, fmt.Errorf("failed to export array element type: %w", err)
+		}
+
+		return &Schema{Type: Types{"array"}, Items: items}, nil
+
+	case types.TypeID_USER:
+		if exporter.entities[dtype.Reference] {
+			return &Schema{Ref: defRef(dtype.Reference)}, nil
+		}
+
+		if exporter.apiDef.IsExternal(dtype.Reference) {
+			return &Schema{Comment: fmt.Sprintf("external type '%s'", dtype.Reference)}, nil
+		}
+
+		return nil, fmt.Errorf("unknown entity '%s'", dtype.Reference)
+
+	case types.TypeID_GENERIC:
+		return exporter.genericSchema(dtype)
+
+	default:
+		return nil, fmt.Errorf("unknown type ID %s", dtype.TypeID)
+	}
+}
+
+// genericSchema converts well-known generic types, such as Record<string, V>. Generic entities are referenced from
+// $defs, and other generic types are described by their name only, since JSON Schema has no generics
+func (exporter exporter) genericSchema(dtype types.DynamicType) (*Schema, error) {
+	params := make([]*Schema, 0, len(dtype.Inner))
+	for genericIdx, inner := range dtype.Inner {
+		param, err := exporter.TypeSchema(inner)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export generic inner type at index %d: %w", genericIdx, err)
+		}
+
+		params = append(params, param)
+	}
+
+	switch {
+	case dtype.Reference == "Array" && len(params) == 1:
+		return &Schema{Type: Types{"array"}, Items: params[0]}, nil
+	case dtype.Reference == "Set" && len(params) == 1:
+		return &Schema{Type: Types{"array"}, Items: params[0], UniqueItems: true}, nil
+	case (dtype.Reference == "Record" || dtype.Reference == "Map") && len(params) == 2:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: params[1]}, nil
+	case exporter.entities[dtype.Reference]:
+		return &Schema{Ref: defRef(dtype.Reference), Comment: fmt.Sprintf("generic type '%s'", dtype)}, nil
+	default:
+		return &Schema{Comment: fmt.Sprintf("generic type '%s'", dtype.Reference)}, nil
+	}
+}
+
+// document adds documentation to a schema
+func document(schema *Schema, doc types.Documentation) {
+	schema.Title = doc.Summary
+	schema.Description = doc.Description
+	schema.Deprecated = doc.Deprecated
+	if doc.Example != nil {
+		schema.Examples = []any{doc.Example}
+	}
+}
+
+// defRef refers to a schema that is declared under $defs
+func defRef(name string) string {
+	return "#/$defs/" + name
+}
@@ -0,0 +1,486 @@
+package jsonschema
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Import converts the object schemas that a document declares under $defs, or definitions in older drafts, into
+// entities. If the document itself describes an object, it becomes an entity named after its title. Objects that are
+// declared inline become entities named after the property that holds them. Other declarations, such as enums, are
+// inlined wherever they are referenced
+func Import(document *Schema) ([]types.EntitySpec, error) {
+	importer := &importer{
+		defs:      make(map[string]*Schema),
+		entities:  make(map[string]bool),
+		resolving: make(map[string]bool),
+	}
+	for name, def := range document.Definitions {
+		importer.defs[name] = def
+	}
+	for name, def := range document.Defs {
+		importer.defs[name] = def
+	}
+
+	names := make([]string, 0, len(importer.defs))
+	for name := range importer.defs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if importer.defs[name] == nil {
+			return nil, fmt.Errorf("definition '%s' has no schema", name)
+		}
+	}
+
+	// reserve the names of every entity first, so that references can be resolved in any order
+	var declared []string
+	if isObject(document) && len(document.Properties) > 0 {
+		rootName := identifiers.TypeName(document.Title)
+		if len(rootName) == 0 {
+			rootName = "Root"
+		}
+		rootName = importer.uniqueName(rootName)
+		importer.entities[rootName] = true
+		declared = append(declared, rootName)
+	}
+	for _, name := range names {
+		if isObject(importer.defs[name]) {
+			importer.entities[name] = true
+			declared = append(declared, name)
+		}
+	}
+
+	if len(declared) == 0 {
+		return nil, fmt.Errorf("the document does not declare any object schemas")
+	}
+
+	for _, name := range declared {
+		def := document
+		if _, isDef := importer.defs[name]; isDef {
+			def = importer.defs[name]
+		}
+
+		_, err := importer.declare(name, def)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import '%s': %w", name, err)
+		}
+	}
+
+	return importer.declared, nil
+}
+
+// importer converts schemas into entities and types
+type importer struct {
+	defs      map[string]*Schema // schemas declared by the document, by name
+	entities  map[string]bool    // names of every entity, including those that are still being declared
+	resolving map[string]bool    // definitions that are being inlined, to detect references to themselves
+	declared  []types.EntitySpec
+}
+
+// declare converts an object schema into an entity with the given name
+func (importer *importer) declare(name string, schema *Schema) (types.EntitySpec, error) {
+	properties, required, err := importer.collectProperties(schema, nil)
+	if err != nil {
+		return types.EntitySpec{}, err
+	}
+
+	entity := types.EntitySpec{
+		Documentation: documentation(schema),
+		Name:          name,
+		Properties:    make(map[string]types.PropertySpec, len(properties)),
+	}
+
+	index := len(importer.declared)
+	importer.declared = append(importer.declared, entity)
+
+	propertyNames := make([]string, 0, len(properties))
+	for propertyName := range properties {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	slices.Sort(propertyNames)
+
+	for _, propertyName := range propertyNames {
+		property, err := importer.property(name+identifiers.TypeName(propertyName), properties[propertyName], slices.Contains(required, propertyName))
+		if err != nil {
+			return types.EntitySpec{}, fmt.Errorf("failed to import property '%s': %w", propertyName, err)
+		}
+
+		entity.Properties[propertyName] = property
+	}
+
+	importer.declared[index] = entity
+	return entity, nil
+}
+
+// collectProperties gets the properties of an object schema, including those of the schemas it combines with allOf
+func (importer *importer) collectProperties(schema *Schema, visited []*Schema) (map[string]*Schema, []string, error) {
+	if slices.Contains(visited, schema) {
+		return nil, nil, fmt.Errorf("allOf refers to itself")
+	}
+	visited = append(visited, schema)
+
+	properties := make(map[string]*Schema)
+	var required []string
+	for idx, part := range schema.AllOf {
+		if part == nil {
+			return nil, nil, fmt.Errorf("allOf entry %d has no schema", idx)
+		}
+
+		part, err := importer.deref(part)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		partProperties, partRequired, err := importer.collectProperties(part, visited)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for name, property := range partProperties {
+			properties[name] = property
+		}
+		required = append(required, partRequired...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+		if schema.Properties[name] == nil {
+			return nil, nil, fmt.Errorf("property '%s' has no schema", name)
+		}
+
+		properties[name] = schema.Properties[name]
+	}
+	required = append(required, schema.Required...)
+
+	return properties, required, nil
+}
+
+// property converts the schema of a property. Properties that may be null are optional, since the spec has no other
+// way to describe them
+func (importer *importer) property(inlineName string, schema *Schema, required bool) (types.PropertySpec, error) {
+	dtype, constraints, nullable, err := importer.convert(inlineName, schema)
+	if err != nil {
+		return types.PropertySpec{}, err
+	}
+
+	return types.PropertySpec{
+		Documentation:       documentation(schema),
+		PropertyConstraints: constraints,
+		Type:                dtype,
+		Required:            required && !nullable,
+	}, nil
+}
+
+// convert maps a schema onto a dynamic type and the constraints it places on values. Objects that are declared inline
+// become entities with the given name
+func (importer *importer) convert(inlineName string, schema *Schema) (types.DynamicType, types.PropertyConstraints, bool, error) {
+	if len(schema.Ref) > 0 {
+		name, err := refName(schema.Ref)
+		if err != nil {
+			return types.DynamicType{}, types.PropertyConstraints{}, false, err
+		}
+
+		if importer.entities[name] {
+			return types.DynamicType{TypeID: types.TypeID_USER, Reference: name}, constraintsOf(schema), false, nil
+		}
+
+		return importer.inline(name, schema)
+	}
+
+	// a nullable value, such as oneOf [{"type": "string"}, {"type": "null"}]
+	combinations := []struct {
+		keyword      string
+		alternatives []*Schema
+	}{{"oneOf", schema.OneOf}, {"anyOf", schema.AnyOf}}
+	for _, combination := range combinations {
+		alternatives := combination.alternatives
+		if len(alternatives) == 0 {
+			continue
+		}
+
+		var nonNull []*Schema
+		for idx, alternative := range alternatives {
+			if alternative == nil {
+				return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("%s entry %d has no schema", combination.keyword, idx)
+			}
+
+			if !slices.Equal(alternative.Type, Types{"null"}) {
+				nonNull = append(nonNull, alternative)
+			}
+		}
+
+		if len(nonNull) != 1 {
+			return types.DynamicType{TypeID: types.TypeID_ANY}, constraintsOf(schema), false, nil
+		}
+
+		dtype, constraints, _, err := importer.convert(inlineName, nonNull[0])
+		return dtype, constraints, len(nonNull) < len(alternatives), err
+	}
+
+	if len(schema.AllOf) == 1 && len(schema.Properties) == 0 {
+		if schema.AllOf[0] == nil {
+			return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("allOf entry 0 has no schema")
+		}
+
+		dtype, constraints, nullable, err := importer.convert(inlineName, schema.AllOf[0])
+		return dtype, mergeConstraints(constraints, constraintsOf(schema)), nullable, err
+	}
+
+	nullable := slices.Contains(schema.Type, "null")
+	var nonNull []string
+	for _, typeName := range schema.Type {
+		if typeName != "null" {
+			nonNull = append(nonNull, typeName)
+		}
+	}
+
+	constraints := constraintsOf(schema)
+	switch {
+	case len(nonNull) > 1:
+		return types.DynamicType{TypeID: types.TypeID_ANY}, constraints, nullable, nil
+
+	case len(nonNull) == 0 && len(schema.Enum) > 0:
+		return enumType(schema.Enum), constraints, nullable, nil
+
+	case len(nonNull) == 0 && schema.Const != nil:
+		return enumType([]any{schema.Const}), constraints, nullable, nil
+
+	case len(nonNull) == 0 && (len(schema.Properties) > 0 || len(schema.AllOf) > 0):
+		nonNull = []string{"object"}
+
+	case len(nonNull) == 0 && schema.Items != nil:
+		nonNull = []string{"array"}
+
+	case len(nonNull) == 0:
+		return types.DynamicType{TypeID: types.TypeID_ANY}, constraints, nullable, nil
+	}
+
+	switch nonNull[0] {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			constraints.Format = ""
+			return types.DynamicType{TypeID: types.TypeID_TIMESTAMP}, constraints, nullable, nil
+		case "date":
+			constraints.Format = ""
+			return types.DynamicType{TypeID: types.TypeID_DATE}, constraints, nullable, nil
+		}
+		return types.DynamicType{TypeID: types.TypeID_STRING}, constraints, nullable, nil
+
+	case "integer":
+		return types.DynamicType{TypeID: types.TypeID_INTEGER}, constraints, nullable, nil
+
+	case "number":
+		return types.DynamicType{TypeID: types.TypeID_FLOAT}, constraints, nullable, nil
+
+	case "boolean":
+		return types.DynamicType{TypeID: types.TypeID_BOOLEAN}, constraints, nullable, nil
+
+	case "array":
+		element := types.DynamicType{TypeID: types.TypeID_ANY}
+		if schema.Items != nil {
+			var err error
+			element, _, _, err = importer.convert(inlineName+"Item", schema.Items)
+			if err != nil {
+				return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("failed to import array items: %w", err)
+			}
+		}
+		if schema.UniqueItems {
+			return types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Set", Inner: []types.DynamicType{element}}, constraints, nullable, nil
+		}
+		return types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{element}}, constraints, nullable, nil
+
+	case "object":
+		if len(schema.Properties) > 0 || len(schema.AllOf) > 0 {
+			name := importer.uniqueName(inlineName)
+			importer.entities[name] = true
+			_, err := importer.declare(name, schema)
+			if err != nil {
+				return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("failed to import inline object: %w", err)
+			}
+			return types.DynamicType{TypeID: types.TypeID_USER, Reference: name}, constraints, nullable, nil
+		}
+
+		value := types.DynamicType{TypeID: types.TypeID_ANY}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Not == nil {
+			var err error
+			value, _, _, err = importer.convert(inlineName+"Value", schema.AdditionalProperties)
+			if err != nil {
+				return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("failed to import additional properties: %w", err)
+			}
+		}
+		return types.DynamicType{
+			TypeID:    types.TypeID_GENERIC,
+			Reference: "Record",
+			Inner:     []types.DynamicType{{TypeID: types.TypeID_STRING}, value},
+		}, constraints, nullable, nil
+
+	case "null":
+		return types.DynamicType{TypeID: types.TypeID_ANY}, constraints, true, nil
+
+	default:
+		return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("unknown type '%s'", nonNull[0])
+	}
+}
+
+// inline converts a definition that is not an entity, such as an enum, in place of a reference to it
+func (importer *importer) inline(name string, ref *Schema) (types.DynamicType, types.PropertyConstraints, bool, error) {
+	def, exists := importer.defs[name]
+	if !exists {
+		return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("unknown definition '%s'", ref.Ref)
+	}
+
+	if importer.resolving[name] {
+		return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("definition '%s' refers to itself", name)
+	}
+	importer.resolving[name] = true
+	defer delete(importer.resolving, name)
+
+	dtype, constraints, nullable, err := importer.convert(name, def)
+	if err != nil {
+		return types.DynamicType{}, types.PropertyConstraints{}, false, fmt.Errorf("failed to import definition '%s': %w", name, err)
+	}
+
+	return dtype, mergeConstraints(constraints, constraintsOf(ref)), nullable, nil
+}
+
+// deref follows a reference to a definition
+func (importer *importer) deref(schema *Schema) (*Schema, error) {
+	if len(schema.Ref) == 0 {
+		return schema, nil
+	}
+
+	name, err := refName(schema.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	def, exists := importer.defs[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown definition '%s'", schema.Ref)
+	}
+
+	return def, nil
+}
+
+func (importer *importer) uniqueName(name string) string {
+	unique := name
+	for i := 2; importer.entities[unique] || importer.defs[unique] != nil; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+
+	return unique
+}
+
+// refName gets the name of the definition that a reference points at. Only references into the same document are
+// supported
+func refName(ref string) (string, error) {
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if name, found := strings.CutPrefix(ref, prefix); found && len(name) > 0 && !strings.Contains(name, "/") {
+			return strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~"), nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported reference '%s'. Only references to definitions of the same document are supported", ref)
+}
+
+// isObject checks if a schema describes objects with known properties
+func isObject(schema *Schema) bool {
+	if len(schema.Ref) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return false
+	}
+
+	if len(schema.Type) > 0 && !slices.Contains(schema.Type, "object") {
+		return false
+	}
+
+	return len(schema.Properties) > 0 || len(schema.AllOf) > 0 || (slices.Equal(schema.Type, Types{"object"}) && schema.AdditionalProperties == nil)
+}
+
+// enumType gets the type of enum values. Enums of strings are strings, and other enums may hold anything
+func enumType(values []any) types.DynamicType {
+	for _, value := range values {
+		if _, isString := value.(string); !isString {
+			return types.DynamicType{TypeID: types.TypeID_ANY}
+		}
+	}
+
+	return types.DynamicType{TypeID: types.TypeID_STRING}
+}
+
+// constraintsOf gets the constraints that a schema places on values
+func constraintsOf(schema *Schema) types.PropertyConstraints {
+	constraints := types.PropertyConstraints{
+		Minimum:   schema.Minimum,
+		Maximum:   schema.Maximum,
+		MinLength: schema.MinLength,
+		MaxLength: schema.MaxLength,
+		Pattern:   schema.Pattern,
+		Enum:      schema.Enum,
+		MinItems:  schema.MinItems,
+		MaxItems:  schema.MaxItems,
+	}
+
+	if schema.Const != nil && len(constraints.Enum) == 0 {
+		constraints.Enum = []any{schema.Const}
+	}
+
+	switch schema.Format {
+	case types.Format_EMAIL, types.Format_UUID, types.Format_URI:
+		constraints.Format = schema.Format
+	}
+
+	return constraints
+}
+
+// mergeConstraints adds the constraints that are set by overrides to the base constraints
+func mergeConstraints(base, overrides types.PropertyConstraints) types.PropertyConstraints {
+	if overrides.Minimum != nil {
+		base.Minimum = overrides.Minimum
+	}
+	if overrides.Maximum != nil {
+		base.Maximum = overrides.Maximum
+	}
+	if overrides.MinLength != nil {
+		base.MinLength = overrides.MinLength
+	}
+	if overrides.MaxLength != nil {
+		base.MaxLength = overrides.MaxLength
+	}
+	if len(overrides.Pattern) > 0 {
+		base.Pattern = overrides.Pattern
+	}
+	if len(overrides.Enum) > 0 {
+		base.Enum = overrides.Enum
+	}
+	if overrides.MinItems != nil {
+		base.MinItems = overrides.MinItems
+	}
+	if overrides.MaxItems != nil {
+		base.MaxItems = overrides.MaxItems
+	}
+	if len(overrides.Format) > 0 {
+		base.Format = overrides.Format
+	}
+
+	return base
+}
+
+// documentation gets the documentation of a schema
+func documentation(schema *Schema) types.Documentation {
+	doc := types.Documentation{
+		Summary:     schema.Title,
+		Description: schema.Description,
+		Deprecated:  schema.Deprecated,
+	}
+	if len(schema.Examples) > 0 {
+		doc.Example = schema.Examples[0]
+	}
+
+	return doc
+}
@@ -0,0 +1,251 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func peopleAPI() types.APIDefinition {
+	minAge := 0.0
+	maxTags := 5
+	return types.APIDefinition{
+		Name:   "people",
+		Naming: types.NamingStrategy_SNAKE,
+		Entities: []types.EntitySpec{
+			{
+				Documentation: types.Documentation{Description: "A person"},
+				Name:          "Person",
+				Properties: map[string]types.PropertySpec{
+					"firstName": {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+					"age":       {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, PropertyConstraints: types.PropertyConstraints{Minimum: &minAge}},
+					"email":     {Type: types.DynamicType{TypeID: types.TypeID_STRING}, PropertyConstraints: types.PropertyConstraints{Format: types.Format_EMAIL}},
+					"born":      {Type: types.DynamicType{TypeID: types.TypeID_DATE}},
+					"address":   {Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Address"}, Required: true},
+					"tags": {
+						Type:                types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Set", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}}},
+						PropertyConstraints: types.PropertyConstraints{MaxItems: &maxTags},
+					},
+					"scores":  {Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Record", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}, {TypeID: types.TypeID_FLOAT}}}},
+					"friends": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}},
+				},
+			},
+			{
+				Name: "Address",
+				Properties: map[string]types.PropertySpec{
+					"street": {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+				},
+			},
+		},
+	}
+}
+
+func TestExport_DeclaresEntitiesAsDefs(t *testing.T) {
+	document, err := Export(peopleAPI(), ExportOptions{ID: "https://acme.com/people.schema.json", Root: "Person"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, Draft, document.Schema)
+	assert.Equal(t, "https://acme.com/people.schema.json", document.ID)
+	assert.Equal(t, "#/$defs/Person", document.Ref)
+	assert.Len(t, document.Defs, 2)
+
+	person := document.Defs["Person"]
+	assert.Equal(t, Types{"object"}, person.Type)
+	assert.Equal(t, "A person", person.Description)
+	assert.Equal(t, []string{"address", "first_name"}, person.Required)
+
+	assert.Equal(t, &Schema{Type: Types{"string"}}, person.Properties["first_name"])
+	assert.Equal(t, 0.0, *person.Properties["age"].Minimum)
+	assert.Equal(t, "email", person.Properties["email"].Format)
+	assert.Equal(t, "date", person.Properties["born"].Format)
+	assert.Equal(t, &Schema{Ref: "#/$defs/Address"}, person.Properties["address"])
+	assert.Equal(t, &Schema{Type: Types{"array"}, Items: &Schema{Type: Types{"string"}}, UniqueItems: true, MaxItems: person.Properties["tags"].MaxItems}, person.Properties["tags"])
+	assert.Equal(t, &Schema{Type: Types{"object"}, AdditionalProperties: &Schema{Type: Types{"number"}}}, person.Properties["scores"])
+	assert.Equal(t, &Schema{Type: Types{"array"}, Items: &Schema{Ref: "#/$defs/Person"}}, person.Properties["friends"])
+}
+
+func TestExport_WrapsConstrainedReferences(t *testing.T) {
+	apiDef := peopleAPI()
+	minItems := 1
+	address := apiDef.Entities[0].Properties["address"]
+	address.Type = types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{address.Type}}
+	address.MinItems = &minItems
+	apiDef.Entities[0].Properties["address"] = address
+
+	document, err := Export(apiDef, ExportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *document.Defs["Person"].Properties["address"].MinItems)
+
+	apiDef.Entities[0].Properties["address"] = types.PropertySpec{
+		Type:                types.DynamicType{TypeID: types.TypeID_USER, Reference: "Address"},
+		PropertyConstraints: types.PropertyConstraints{Enum: []any{"home"}},
+	}
+	document, err = Export(apiDef, ExportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &Schema{AllOf: []*Schema{{Ref: "#/$defs/Address"}}, Enum: []any{"home"}}, document.Defs["Person"].Properties["address"])
+}
+
+func TestExport_ReferencesGenericEntities(t *testing.T) {
+	apiDef := peopleAPI()
+	apiDef.Entities = append(apiDef.Entities, types.EntitySpec{
+		Name: "Page",
+		Properties: map[string]types.PropertySpec{
+			"total": {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true},
+		},
+	})
+	apiDef.Entities[0].Properties["addresses"] = types.PropertySpec{
+		Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Address"}}},
+	}
+
+	document, err := Export(apiDef, ExportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &Schema{Ref: "#/$defs/Page", Comment: "generic type 'Page<Address>'"}, document.Defs["Person"].Properties["addresses"])
+}
+
+func TestExport_RejectsUnknownEntities(t *testing.T) {
+	_, err := Export(peopleAPI(), ExportOptions{Root: "Company"})
+	assert.EqualError(t, err, "unknown entity 'Company'")
+
+	apiDef := peopleAPI()
+	apiDef.Entities = apiDef.Entities[:1]
+	_, err = Export(apiDef, ExportOptions{})
+	assert.EqualError(t, err, "failed to export entity 'Person': failed to export property 'address': unknown entity 'Address'")
+}
+
+func TestExport_MarshalsSingleTypesAsStrings(t *testing.T) {
+	data, err := json.Marshal(&Schema{Type: Types{"string"}, Items: &Schema{Type: Types{"integer", "null"}}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "string", "items": {"type": ["integer", "null"]}}`, string(data))
+}
+
+func TestImport_ConvertsDefinitionsToEntities(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "library.schema.json"))
+	assert.NoError(t, err)
+	document, err := Read(data)
+	assert.NoError(t, err)
+
+	entities, err := Import(document)
+	assert.NoError(t, err)
+
+	byName := make(map[string]types.EntitySpec)
+	var names []string
+	for _, entity := range entities {
+		byName[entity.Name] = entity
+		names = append(names, entity.Name)
+	}
+	assert.Equal(t, []string{"Library", "Book", "BookAuthor"}, names)
+
+	library := byName["Library"]
+	assert.Equal(t, types.PropertySpec{
+		Type:     types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Book"}}},
+		Required: true,
+	}, library.Properties["books"])
+
+	book := byName["Book"]
+	assert.Equal(t, "A book on the shelves", book.Description)
+	assert.Equal(t, "^[0-9-]+$", book.Properties["isbn"].Pattern)
+	assert.Equal(t, "Dune", book.Properties["title"].Example)
+	assert.Equal(t, 1, *book.Properties["title"].MinLength)
+	assert.Equal(t, types.TypeID_DATE, book.Properties["published"].Type.TypeID)
+	assert.False(t, book.Properties["published"].Required)
+	assert.Equal(t, types.PropertySpec{
+		PropertyConstraints: types.PropertyConstraints{Enum: []any{"fiction", "poetry"}},
+		Type:                types.DynamicType{TypeID: types.TypeID_STRING},
+		Required:            true,
+	}, book.Properties["genre"])
+	assert.Equal(t, types.TypeID_FLOAT, book.Properties["rating"].Type.TypeID)
+	assert.False(t, book.Properties["rating"].Required, "nullable properties are optional")
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "BookAuthor"}, book.Properties["author"].Type)
+	assert.Equal(t, types.DynamicType{
+		TypeID:    types.TypeID_GENERIC,
+		Reference: "Record",
+		Inner:     []types.DynamicType{{TypeID: types.TypeID_STRING}, {TypeID: types.TypeID_INTEGER}},
+	}, book.Properties["tags"].Type)
+	assert.True(t, book.Properties["legacyId"].Deprecated)
+
+	author := byName["BookAuthor"]
+	assert.True(t, author.Properties["name"].Required)
+	assert.Equal(t, types.Format_EMAIL, author.Properties["email"].Format)
+}
+
+func TestImport_RoundTripsExportedEntities(t *testing.T) {
+	apiDef := peopleAPI()
+	apiDef.Naming = ""
+
+	document, err := Export(apiDef, ExportOptions{})
+	assert.NoError(t, err)
+
+	entities, err := Import(document)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, apiDef.Entities, entities)
+}
+
+func TestImport_RejectsUnsupportedDocuments(t *testing.T) {
+	_, err := Import(&Schema{Type: Types{"string"}})
+	assert.EqualError(t, err, "the document does not declare any object schemas")
+
+	_, err = Import(&Schema{Defs: map[string]*Schema{
+		"Person": {Type: Types{"object"}, Properties: map[string]*Schema{"address": {Ref: "address.schema.json"}}},
+	}})
+	assert.EqualError(t, err, "failed to import 'Person': failed to import property 'address': unsupported reference 'address.schema.json'. Only references to definitions of the same document are supported")
+
+	_, err = Import(&Schema{Defs: map[string]*Schema{
+		"Person": {Type: Types{"object"}, Properties: map[string]*Schema{"id": {Ref: "#/$defs/Id"}}},
+		"Id":     {Ref: "#/$defs/Id"},
+	}})
+	assert.EqualError(t, err, "failed to import 'Person': failed to import property 'id': failed to import definition 'Id': definition 'Id' refers to itself")
+}
+
+func TestImport_RejectsNullSchemas(t *testing.T) {
+	tests := map[string]struct {
+		document string
+		expected string
+	}{
+		"definition": {
+			document: `{"$defs":{"A":null}}`,
+			expected: "definition 'A' has no schema",
+		},
+		"property": {
+			document: `{"$defs":{"A":{"type":"object","properties":{"x":null}}}}`,
+			expected: "failed to import 'A': property 'x' has no schema",
+		},
+		"allOf": {
+			document: `{"$defs":{"A":{"allOf":[{"type":"object","properties":{"x":{"type":"string"}}},null]}}}`,
+			expected: "failed to import 'A': allOf entry 1 has no schema",
+		},
+		"single allOf": {
+			document: `{"$defs":{"A":{"type":"object","properties":{"x":{"allOf":[null]}}}}}`,
+			expected: "failed to import 'A': failed to import property 'x': allOf entry 0 has no schema",
+		},
+		"oneOf": {
+			document: `{"$defs":{"A":{"type":"object","properties":{"x":{"oneOf":[{"type":"null"},null]}}}}}`,
+			expected: "failed to import 'A': failed to import property 'x': oneOf entry 1 has no schema",
+		},
+		"anyOf": {
+			document: `{"$defs":{"A":{"type":"object","properties":{"x":{"anyOf":[null]}}}}}`,
+			expected: "failed to import 'A': failed to import property 'x': anyOf entry 0 has no schema",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := Read([]byte(test.document))
+			assert.NoError(t, err)
+
+			_, err = Import(document)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestImport_TreatsNullItemsAsMissing(t *testing.T) {
+	document, err := Read([]byte(`{"$defs":{"A":{"type":"object","properties":{"x":{"type":"array","items":null}}}}}`))
+	assert.NoError(t, err)
+
+	entities, err := Import(document)
+	assert.NoError(t, err)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}, entities[0].Properties["x"].Type)
+}
@@ -0,0 +1,107 @@
+// Package jsonschema converts between the entities of API definitions and JSON Schema documents, using the 2020-12
+// version of JSON Schema
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Draft identifies the 2020-12 version of JSON Schema
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Only the keywords that describe the shape of values are supported
+type Schema struct {
+	Schema  string `json:"$schema,omitempty"`
+	ID      string `json:"$id,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+	Comment string `json:"$comment,omitempty"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Examples    []any  `json:"examples,omitempty"`
+
+	Type                 Types              `json:"type,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+	Format    string   `json:"format,omitempty"`
+
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Definitions map[string]*Schema `json:"definitions,omitempty"` // the name of $defs before 2019-09. Only read
+}
+
+// UnmarshalJSON decodes a schema, which may also be a boolean. The true schema accepts anything, and the false schema
+// accepts nothing
+func (schema *Schema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if json.Unmarshal(data, &boolean) == nil {
+		*schema = Schema{}
+		if !boolean {
+			schema.Not = &Schema{}
+		}
+		return nil
+	}
+
+	type plainSchema Schema
+	return json.Unmarshal(data, (*plainSchema)(schema))
+}
+
+// Types is the type keyword of a schema, which may be a single type or a list of types
+type Types []string
+
+// MarshalJSON writes a single type as a string
+func (types Types) MarshalJSON() ([]byte, error) {
+	if len(types) == 1 {
+		return json.Marshal(types[0])
+	}
+
+	return json.Marshal([]string(types))
+}
+
+// UnmarshalJSON decodes a single type or a list of types
+func (types *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*types = Types{single}
+		return nil
+	}
+
+	var list []string
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return fmt.Errorf("expected a type to be a string or a list of strings: %w", err)
+	}
+
+	*types = list
+	return nil
+}
+
+// Read decodes a JSON Schema document
+func Read(data []byte) (*Schema, error) {
+	var schema Schema
+	err := json.Unmarshal(data, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON schema: %w", err)
+	}
+
+	return &schema, nil
+}
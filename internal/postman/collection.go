@@ -0,0 +1,308 @@
+// Package postman converts between API definitions and Postman collections, using version 2.1 of the collection format
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaURL identifies version 2.1 of the collection format
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a Postman collection
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`               // folders and requests at the top of the collection
+	Variable []Variable `json:"variable,omitempty"` // variables available to every request
+}
+
+// Info describes a collection
+type Info struct {
+	PostmanID   string      `json:"_postman_id,omitempty"`
+	Name        string      `json:"name"`
+	Description Description `json:"description,omitempty"`
+	Version     string      `json:"version,omitempty"`
+	Schema      string      `json:"schema"`
+}
+
+// Item is either a folder, which has items of its own, or a request
+type Item struct {
+	Name        string      `json:"name"`
+	Description Description `json:"description,omitempty"`
+	Item        []Item      `json:"item,omitempty"`     // items of a folder
+	Request     *Request    `json:"request,omitempty"`  // the request of a request item
+	Response    []Response  `json:"response,omitempty"` // example responses that were saved for the request
+}
+
+// IsFolder checks if this item is a folder
+func (item Item) IsFolder() bool {
+	return item.Request == nil
+}
+
+// Request is an HTTP request
+type Request struct {
+	Method      string      `json:"method"`
+	Header      []Header    `json:"header,omitempty"`
+	Body        *Body       `json:"body,omitempty"`
+	URL         URL         `json:"url"`
+	Description Description `json:"description,omitempty"`
+}
+
+// UnmarshalJSON decodes a request, which may also be written as just its URL
+func (request *Request) UnmarshalJSON(data []byte) error {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		*request = Request{Method: "GET", URL: parseURL(raw)}
+		return nil
+	}
+
+	type plainRequest Request
+	err := json.Unmarshal(data, (*plainRequest)(request))
+	if err != nil {
+		return err
+	}
+
+	if len(request.Method) == 0 {
+		request.Method = "GET"
+	}
+	request.Method = strings.ToUpper(request.Method)
+	return nil
+}
+
+// Header is an HTTP header
+type Header struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// Body is the body of a request
+type Body struct {
+	Mode    string       `json:"mode"`              // how the body is written: raw, urlencoded, formdata, file or graphql
+	Raw     string       `json:"raw,omitempty"`     // the body, for the raw mode
+	Options *BodyOptions `json:"options,omitempty"` // describes the body
+}
+
+// BodyOptions describe the body of a request
+type BodyOptions struct {
+	Raw struct {
+		Language string `json:"language,omitempty"` // the language of a raw body, such as json
+	} `json:"raw"`
+}
+
+// URL is the URL of a request. Postman keeps the raw URL along with its parts
+type URL struct {
+	Raw      string       `json:"raw"`
+	Protocol string       `json:"protocol,omitempty"`
+	Host     []string     `json:"host,omitempty"`
+	Port     string       `json:"port,omitempty"`
+	Path     []string     `json:"path,omitempty"`
+	Query    []QueryParam `json:"query,omitempty"`
+	Variable []Variable   `json:"variable,omitempty"` // values of the ":name" segments of the path
+}
+
+// UnmarshalJSON decodes a URL, which may also be written as just the raw URL
+func (url *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if json.Unmarshal(data, &raw) == nil {
+		*url = parseURL(raw)
+		return nil
+	}
+
+	type urlFields URL
+	type plainURL struct {
+		urlFields
+		Host any `json:"host,omitempty"`
+		Path any `json:"path,omitempty"`
+	}
+
+	var decoded plainURL
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*url = URL(decoded.urlFields)
+	if decoded.Host == nil && decoded.Path == nil {
+		// only the raw URL was given
+		parsed := parseURL(url.Raw)
+		url.Protocol, url.Host, url.Path = parsed.Protocol, parsed.Host, parsed.Path
+		if len(url.Query) == 0 {
+			url.Query = parsed.Query
+		}
+		return nil
+	}
+
+	// hosts may be written as a single string, which is split the same way as the segments of a path
+	url.Host, err = segments(decoded.Host, ".")
+	if err != nil {
+		return fmt.Errorf("invalid host: %w", err)
+	}
+
+	url.Path, err = segments(decoded.Path, "/")
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	return nil
+}
+
+// segments decodes the parts of a host or path, which may be a single string or a list of strings and variables
+func segments(value any, separator string) ([]string, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+
+	case string:
+		return strings.Split(strings.Trim(value, separator), separator), nil
+
+	case []any:
+		parts := make([]string, 0, len(value))
+		for _, part := range value {
+			switch part := part.(type) {
+			case string:
+				parts = append(parts, part)
+			case map[string]any:
+				// a path variable object, such as {"type": "string", "value": "id"}
+				name, _ := part["value"].(string)
+				parts = append(parts, ":"+name)
+			default:
+				return nil, fmt.Errorf("unexpected segment %v", part)
+			}
+		}
+		return parts, nil
+
+	default:
+		return nil, fmt.Errorf("expected a string or a list, found %v", value)
+	}
+}
+
+// parseURL splits a raw URL into its parts. Postman URLs often start with a variable instead of a host, such as
+// "{{baseURL}}/people", so they cannot be parsed as regular URLs
+func parseURL(raw string) URL {
+	url := URL{Raw: raw}
+
+	rest := raw
+	if index := strings.Index(rest, "#"); index >= 0 {
+		rest = rest[:index]
+	}
+
+	if index := strings.Index(rest, "?"); index >= 0 {
+		for _, pair := range strings.Split(rest[index+1:], "&") {
+			if len(pair) == 0 {
+				continue
+			}
+			key, value, _ := strings.Cut(pair, "=")
+			url.Query = append(url.Query, QueryParam{Key: key, Value: value})
+		}
+		rest = rest[:index]
+	}
+
+	if protocol, afterProtocol, found := strings.Cut(rest, "://"); found {
+		url.Protocol = protocol
+		rest = afterProtocol
+	}
+
+	host, path, _ := strings.Cut(rest, "/")
+	if len(host) > 0 {
+		url.Host = strings.Split(host, ".")
+	}
+	if len(path) > 0 {
+		url.Path = strings.Split(path, "/")
+	}
+
+	return url
+}
+
+// QueryParam is a query variable of a URL
+type QueryParam struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Disabled    bool        `json:"disabled,omitempty"`
+	Description Description `json:"description,omitempty"`
+}
+
+// Variable is a named value, such as a collection variable or the value of a path variable
+type Variable struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Type        string      `json:"type,omitempty"`
+	Description Description `json:"description,omitempty"`
+}
+
+// UnmarshalJSON decodes a variable, whose value may be any JSON value
+func (variable *Variable) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		Key         string          `json:"key"`
+		ID          string          `json:"id"`
+		Value       json.RawMessage `json:"value"`
+		Type        string          `json:"type"`
+		Description Description     `json:"description"`
+	}
+
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	*variable = Variable{Key: decoded.Key, Type: decoded.Type, Description: decoded.Description}
+	if len(variable.Key) == 0 {
+		variable.Key = decoded.ID
+	}
+
+	if len(decoded.Value) > 0 && json.Unmarshal(decoded.Value, &variable.Value) != nil {
+		// values that are not strings are kept as they are written
+		variable.Value = string(decoded.Value)
+	}
+
+	return nil
+}
+
+// Response is an example response that was saved for a request
+type Response struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status,omitempty"`
+	Code   int      `json:"code,omitempty"`
+	Header []Header `json:"header,omitempty"`
+	Body   string   `json:"body,omitempty"`
+}
+
+// Description documents an element of a collection. It may be written as a string, or as an object with the text in
+// its content
+type Description string
+
+// UnmarshalJSON decodes a description from a string or an object
+func (description *Description) UnmarshalJSON(data []byte) error {
+	var text string
+	if json.Unmarshal(data, &text) == nil {
+		*description = Description(text)
+		return nil
+	}
+
+	var object struct {
+		Content string `json:"content"`
+	}
+	err := json.Unmarshal(data, &object)
+	if err != nil {
+		return fmt.Errorf("expected a description to be a string or an object: %w", err)
+	}
+
+	*description = Description(object.Content)
+	return nil
+}
+
+// Read decodes a collection
+func Read(data []byte) (Collection, error) {
+	var collection Collection
+	err := json.Unmarshal(data, &collection)
+	if err != nil {
+		return Collection{}, fmt.Errorf("failed to decode collection: %w", err)
+	}
+
+	if len(collection.Info.Schema) > 0 && !strings.Contains(collection.Info.Schema, "v2.1") && !strings.Contains(collection.Info.Schema, "v2.0") {
+		return Collection{}, fmt.Errorf("unsupported collection schema '%s'. Collections must use version 2.1 of the format", collection.Info.Schema)
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,366 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// BaseURLVariable is the collection variable that requests of exported collections are relative to
+const BaseURLVariable = "baseURL"
+
+// pathVariablePattern matches a variable of an endpoint template, such as {{id}}
+var pathVariablePattern = regexp.MustCompile(`^\{\{([^{}]+)}}$`)
+
+// Export converts an API definition into a collection. Services become folders and endpoints become requests. Every
+// request is relative to the baseURL collection variable, which is set to the base URL of the API. Requests and the
+// example responses saved with them have sample bodies built from the entities of the API
+func Export(apiDef types.APIDefinition) (Collection, error) {
+	exporter := exporter{
+		apiDef:   apiDef,
+		entities: make(map[string]types.EntitySpec),
+	}
+	for _, entity := range apiDef.Entities {
+		exporter.entities[entity.Name] = entity
+	}
+
+	collection := Collection{
+		Info: Info{
+			Name:    apiDef.Name,
+			Version: apiDef.Version,
+			Schema:  SchemaURL,
+		},
+		Item: make([]Item, 0, len(apiDef.Services)),
+		Variable: []Variable{
+			{Key: BaseURLVariable, Value: apiDef.Config.BaseURL, Type: "string"},
+		},
+	}
+
+	for _, service := range apiDef.Services {
+		folder := Item{
+			Name:        service.Name,
+			Description: describe(service.Documentation),
+			Item:        make([]Item, 0, len(service.Endpoints)),
+		}
+
+		for _, endpoint := range service.Endpoints {
+			item, err := exporter.request(endpoint)
+			if err != nil {
+				return Collection{}, fmt.Errorf("failed to export endpoint '%s' of service '%s': %w", endpoint.Name, service.Name, err)
+			}
+
+			folder.Item = append(folder.Item, item)
+		}
+
+		collection.Item = append(collection.Item, folder)
+	}
+
+	return collection, nil
+}
+
+// exporter builds the requests of a collection
+type exporter struct {
+	apiDef   types.APIDefinition
+	entities map[string]types.EntitySpec // entities of the API, by name
+}
+
+// request converts an endpoint into a request item
+func (exporter exporter) request(endpoint types.APIEndpoint) (Item, error) {
+	request := &Request{
+		Method:      strings.ToUpper(endpoint.Method),
+		Description: describe(endpoint.Documentation),
+		URL:         exporter.url(endpoint),
+	}
+
+	if hasBody(endpoint.RequestBody.Type) {
+		body, err := exporter.body(endpoint.RequestBody.Type, endpoint.RequestBody.Example)
+		if err != nil {
+			return Item{}, fmt.Errorf("failed to build the request body: %w", err)
+		}
+
+		request.Header = append(request.Header, Header{Key: "Content-Type", Value: "application/json"})
+		request.Body = &Body{Mode: "raw", Raw: body, Options: &BodyOptions{}}
+		request.Body.Options.Raw.Language = "json"
+	}
+
+	item := Item{
+		Name:    endpoint.Name,
+		Request: request,
+	}
+
+	if hasBody(endpoint.ResponseBody.Type) {
+		body, err := exporter.body(endpoint.ResponseBody.Type, endpoint.ResponseBody.Example)
+		if err != nil {
+			return Item{}, fmt.Errorf("failed to build the response body: %w", err)
+		}
+
+		item.Response = []Response{
+			{
+				Name:   "Example response",
+				Status: "OK",
+				Code:   200,
+				Header: []Header{{Key: "Content-Type", Value: "application/json"}},
+				Body:   body,
+			},
+		}
+	}
+
+	return item, nil
+}
+
+// url builds the URL of an endpoint. Path variables become ":name" segments, and query variables that are not required
+// are disabled
+func (exporter exporter) url(endpoint types.APIEndpoint) URL {
+	url := URL{
+		Host: []string{"{{" + BaseURLVariable + "}}"},
+	}
+
+	for _, segment := range strings.Split(strings.Trim(endpoint.Endpoint, "/"), "/") {
+		if len(segment) == 0 {
+			continue
+		}
+
+		if match := pathVariablePattern.FindStringSubmatch(segment); match != nil {
+			name := strings.TrimSpace(match[1])
+			variable := endpoint.PathVariables[name]
+			url.Path = append(url.Path, ":"+name)
+			url.Variable = append(url.Variable, Variable{
+				Key:         name,
+				Value:       scalarSample(exporter.sample(variable.Type, variable.Example, nil)),
+				Description: describe(variable.Documentation),
+			})
+			continue
+		}
+
+		url.Path = append(url.Path, segment)
+	}
+
+	for _, name := range sortedKeys(endpoint.QueryVariables) {
+		variable := endpoint.QueryVariables[name]
+		url.Query = append(url.Query, QueryParam{
+			Key:         name,
+			Value:       scalarSample(exporter.sample(variable.Type, variable.Example, nil)),
+			Disabled:    !variable.Required,
+			Description: describe(variable.Documentation),
+		})
+	}
+
+	url.Raw = strings.Join(url.Host, ".")
+	if len(url.Path) > 0 {
+		url.Raw += "/" + strings.Join(url.Path, "/")
+	}
+
+	var query []string
+	for _, param := range url.Query {
+		if !param.Disabled {
+			query = append(query, param.Key+"="+param.Value)
+		}
+	}
+	if len(query) > 0 {
+		url.Raw += "?" + strings.Join(query, "&")
+	}
+
+	return url
+}
+
+// body builds a sample JSON body of the given type
+func (exporter exporter) body(dtype types.DynamicType, example any) (string, error) {
+	contents, err := json.MarshalIndent(exporter.sample(dtype, example, nil), "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}
+
+// sample builds a sample value of the given type. Examples from the specification are used when they are given.
+// Entities that are already being sampled are not sampled again, so that recursive entities end
+func (exporter exporter) sample(dtype types.DynamicType, example any, visiting []string) any {
+	if example != nil {
+		return example
+	}
+
+	switch dtype.TypeID {
+	case types.TypeID_STRING:
+		return "string"
+	case types.TypeID_INTEGER:
+		return 0
+	case types.TypeID_FLOAT:
+		return 0.0
+	case types.TypeID_BOOLEAN:
+		return false
+	case types.TypeID_TIMESTAMP:
+		return "1970-01-01T00:00:00Z"
+	case types.TypeID_DATE:
+		return "1970-01-01"
+
+	case types.TypeID_ARRAY:
+		if len(dtype.Inner) == 0 {
+			return []any{}
+		}
+		return []any{exporter.sample(dtype.Inner[0], nil, visiting)}
+
+	case types.TypeID_USER:
+		entity, exists := exporter.entities[dtype.Reference]
+		if !exists || slices.Contains(visiting, entity.Name) {
+			return map[string]any{}
+		}
+		return exporter.entitySample(entity, append(visiting, entity.Name))
+
+	case types.TypeID_GENERIC:
+		switch dtype.Reference {
+		case "Record", "Map":
+			return map[string]any{}
+		case "Array", "Set":
+			if len(dtype.Inner) == 0 {
+				return []any{}
+			}
+			return []any{exporter.sample(dtype.Inner[0], nil, visiting)}
+		}
+
+		entity, exists := exporter.entities[dtype.Reference]
+		if !exists {
+			return nil
+		}
+		if slices.Contains(visiting, entity.Name) {
+			return map[string]any{}
+		}
+		return exporter.entitySample(substituteArgument(entity, dtype.Inner), append(visiting, entity.Name))
+
+	default:
+		return nil
+	}
+}
+
+// entitySample builds a sample object of an entity, keyed by the names its properties are sent with
+func (exporter exporter) entitySample(entity types.EntitySpec, visiting []string) map[string]any {
+	object := make(map[string]any, len(entity.Properties))
+	for name, property := range entity.Properties {
+		if property.Type.TypeID == types.TypeID_USER && slices.Contains(visiting, property.Type.Reference) && !property.Required {
+			// leave out optional back references, such as the parent of a tree node
+			continue
+		}
+
+		object[property.ResolveWireName(name, exporter.apiDef.Naming)] = exporter.propertySample(property, visiting)
+	}
+
+	return object
+}
+
+// substituteArgument gets a copy of a generic entity that holds its argument. Entities do not declare type parameters,
+// so the values they type as any hold their argument. Entities with several arguments are left as they are, as there is
+// no telling which value holds which argument
+func substituteArgument(entity types.EntitySpec, arguments []types.DynamicType) types.EntitySpec {
+	if len(arguments) != 1 {
+		return entity
+	}
+
+	substituted := entity
+	substituted.Properties = make(map[string]types.PropertySpec, len(entity.Properties))
+	for name, property := range entity.Properties {
+		property.Type = substitute(property.Type, arguments[0])
+		substituted.Properties[name] = property
+	}
+
+	return substituted
+}
+
+// substitute replaces every value of a type that is typed as any with the given argument
+func substitute(dtype types.DynamicType, argument types.DynamicType) types.DynamicType {
+	if dtype.TypeID == types.TypeID_ANY {
+		return argument
+	}
+
+	if len(dtype.Inner) > 0 {
+		inner := make([]types.DynamicType, len(dtype.Inner))
+		for idx, innerType := range dtype.Inner {
+			inner[idx] = substitute(innerType, argument)
+		}
+		dtype.Inner = inner
+	}
+
+	return dtype
+}
+
+// propertySample builds a sample value of a property that satisfies its constraints
+func (exporter exporter) propertySample(property types.PropertySpec, visiting []string) any {
+	if property.Example != nil {
+		return property.Example
+	}
+
+	if len(property.Enum) > 0 {
+		return property.Enum[0]
+	}
+
+	switch property.Type.TypeID {
+	case types.TypeID_STRING:
+		switch property.Format {
+		case types.Format_EMAIL:
+			return "user@example.com"
+		case types.Format_UUID:
+			return "00000000-0000-0000-0000-000000000000"
+		case types.Format_URI:
+			return "https://example.com"
+		}
+
+	case types.TypeID_INTEGER:
+		if property.Minimum != nil {
+			return int64(*property.Minimum)
+		}
+
+	case types.TypeID_FLOAT:
+		if property.Minimum != nil {
+			return *property.Minimum
+		}
+	}
+
+	return exporter.sample(property.Type, nil, visiting)
+}
+
+// scalarSample writes a sample value the way it is written in a URL
+func scalarSample(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []any, map[string]any:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// describe joins the summary and description of an element into a single description
+func describe(doc types.Documentation) Description {
+	var parts []string
+	if len(doc.Summary) > 0 {
+		parts = append(parts, doc.Summary)
+	}
+	if len(doc.Description) > 0 {
+		parts = append(parts, doc.Description)
+	}
+	if doc.Deprecated {
+		parts = append(parts, "Deprecated.")
+	}
+
+	return Description(strings.Join(parts, "\n\n"))
+}
+
+// hasBody checks if values of the type are sent in a body
+func hasBody(dtype types.DynamicType) bool {
+	return len(dtype.TypeID) > 0 && !dtype.IsVoid()
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
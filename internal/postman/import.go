@@ -0,0 +1,352 @@
+package postman
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/identifiers"
+	"github.com/softwaresale/client-gen/v2/internal/sampleinfer"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"regexp"
+	"strings"
+)
+
+// ImportOptions configures how a collection is converted into an API definition
+type ImportOptions struct {
+	Name      string       // name of the API. Defaults to the name of the collection
+	Version   string       // version of the API. Defaults to the version of the collection
+	BaseURL   string       // base URL of the API. Defaults to the base URL of the first request
+	OnWarning func(string) // optionally receives warnings, such as bodies that are not JSON
+}
+
+// variablePattern matches a Postman variable, such as {{baseURL}}
+var variablePattern = regexp.MustCompile(`\{\{([^{}]*)}}`)
+
+// Import converts a collection into an API definition. Top-level folders become services, requests within them become
+// endpoints, and the entities that endpoints exchange are inferred from the JSON bodies of requests and of the example
+// responses saved with them. Requests outside of folders belong to a service named after the collection
+func Import(collection Collection, opts ImportOptions) (types.APIDefinition, error) {
+	importer := &importer{
+		opts:      opts,
+		variables: make(map[string]string),
+		inferrer:  sampleinfer.New(),
+		roots:     make(map[string]bool),
+		services:  make(map[string]*types.ServiceDefinition),
+	}
+	for _, variable := range collection.Variable {
+		importer.variables[variable.Key] = variable.Value
+	}
+
+	defaultService := typeName(collection.Info.Name, "API")
+	for _, item := range collection.Item {
+		if item.IsFolder() {
+			service := importer.service(typeName(item.Name, defaultService), string(item.Description))
+			importer.addFolder(service, item)
+		} else {
+			importer.addRequest(importer.service(defaultService, ""), item)
+		}
+	}
+
+	if len(importer.endpoints) == 0 {
+		return types.APIDefinition{}, fmt.Errorf("collection '%s' has no requests", collection.Info.Name)
+	}
+
+	inference := importer.inferrer.Infer()
+	for _, endpoint := range importer.endpoints {
+		endpoint.resolve(inference)
+	}
+
+	apiDef := types.APIDefinition{
+		Name:     opts.Name,
+		Version:  opts.Version,
+		Entities: inference.Entities,
+		Config:   types.APIConfig{BaseURL: opts.BaseURL},
+	}
+	if len(apiDef.Name) == 0 {
+		apiDef.Name = collection.Info.Name
+	}
+	if len(apiDef.Version) == 0 {
+		apiDef.Version = collection.Info.Version
+	}
+	if len(apiDef.Config.BaseURL) == 0 {
+		apiDef.Config.BaseURL = importer.baseURL
+	}
+
+	for _, name := range importer.order {
+		apiDef.Services = append(apiDef.Services, *importer.services[name])
+	}
+
+	return apiDef, nil
+}
+
+// importer collects the services and endpoints of a collection
+type importer struct {
+	opts      ImportOptions
+	variables map[string]string // values of the collection variables
+	inferrer  *sampleinfer.Inferrer
+	roots     map[string]bool // names that samples were added to the inferrer under
+	baseURL   string          // base URL of the first request
+
+	services  map[string]*types.ServiceDefinition
+	order     []string // names of the services, in the order they were first seen
+	endpoints []importedEndpoint
+}
+
+// importedEndpoint is an endpoint whose bodies are typed once samples of every request have been collected
+type importedEndpoint struct {
+	service  *types.ServiceDefinition
+	index    int    // index of the endpoint within its service
+	request  string // name that samples of the request body were added under, if there are any
+	response string // name that samples of the response body were added under, if there are any
+}
+
+// resolve types the bodies of the endpoint
+func (endpoint importedEndpoint) resolve(inference sampleinfer.Inference) {
+	apiEndpoint := &endpoint.service.Endpoints[endpoint.index]
+	if len(endpoint.request) > 0 {
+		apiEndpoint.RequestBody = types.RequestValue{Type: inference.Types[endpoint.request], Required: true}
+	}
+	if len(endpoint.response) > 0 {
+		apiEndpoint.ResponseBody = types.RequestValue{Type: inference.Types[endpoint.response], Required: true}
+	}
+}
+
+func (importer *importer) warn(format string, args ...any) {
+	if importer.opts.OnWarning != nil {
+		importer.opts.OnWarning(fmt.Sprintf(format, args...))
+	}
+}
+
+// service gets the service with the given name, declaring it if needed
+func (importer *importer) service(name, description string) *types.ServiceDefinition {
+	service, exists := importer.services[name]
+	if !exists {
+		service = &types.ServiceDefinition{Name: name}
+		importer.services[name] = service
+		importer.order = append(importer.order, name)
+	}
+
+	if len(service.Description) == 0 {
+		service.Description = strings.TrimSpace(description)
+	}
+
+	return service
+}
+
+// addFolder adds every request within a folder to the service, including those in nested folders
+func (importer *importer) addFolder(service *types.ServiceDefinition, folder Item) {
+	for _, item := range folder.Item {
+		if item.IsFolder() {
+			importer.addFolder(service, item)
+		} else {
+			importer.addRequest(service, item)
+		}
+	}
+}
+
+// addRequest adds a request to a service as an endpoint
+func (importer *importer) addRequest(service *types.ServiceDefinition, item Item) {
+	request := item.Request
+
+	endpoint := types.APIEndpoint{
+		Name:           importer.endpointName(service, item.Name),
+		Method:         request.Method,
+		PathVariables:  make(map[string]types.RequestValue),
+		QueryVariables: make(map[string]types.RequestValue),
+		RequestBody:    types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+		ResponseBody:   types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+	}
+	endpoint.Description = strings.TrimSpace(string(item.Description))
+	if len(endpoint.Description) == 0 {
+		endpoint.Description = strings.TrimSpace(string(request.Description))
+	}
+
+	if len(importer.baseURL) == 0 {
+		importer.baseURL = importer.resolveBaseURL(request.URL)
+	}
+
+	endpoint.Endpoint = importer.path(request.URL, endpoint.PathVariables)
+	for _, param := range request.URL.Query {
+		if len(param.Key) == 0 {
+			continue
+		}
+
+		endpoint.QueryVariables[param.Key] = types.RequestValue{
+			Documentation: types.Documentation{Description: strings.TrimSpace(string(param.Description))},
+			Type:          valueType(param.Value),
+		}
+	}
+
+	imported := importedEndpoint{service: service, index: len(service.Endpoints)}
+	bodyName := typeName(endpoint.Name, "Endpoint")
+
+	if request.Body != nil && request.Body.Mode == "raw" && len(strings.TrimSpace(request.Body.Raw)) > 0 {
+		sample, ok := importer.decodeBody(request.Body.Raw)
+		if ok {
+			imported.request = importer.root(bodyName + "Request")
+			importer.inferrer.Add(imported.request, sample)
+		} else {
+			importer.warn("the body of request '%s' is not JSON, so its type is unknown", item.Name)
+			endpoint.RequestBody = types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_ANY}, Required: true}
+		}
+	} else if request.Body != nil && len(request.Body.Mode) > 0 && request.Body.Mode != "raw" {
+		importer.warn("the %s body of request '%s' is not JSON, so its type is unknown", request.Body.Mode, item.Name)
+		endpoint.RequestBody = types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_ANY}, Required: true}
+	}
+
+	for _, response := range item.Response {
+		if response.Code != 0 && (response.Code < 200 || response.Code >= 300) {
+			continue
+		}
+
+		if len(strings.TrimSpace(response.Body)) == 0 {
+			continue
+		}
+
+		sample, ok := importer.decodeBody(response.Body)
+		if !ok {
+			importer.warn("the example response '%s' of request '%s' is not JSON, so it was skipped", response.Name, item.Name)
+			continue
+		}
+
+		if len(imported.response) == 0 {
+			imported.response = importer.root(bodyName + "Response")
+		}
+		importer.inferrer.Add(imported.response, sample)
+	}
+
+	service.Endpoints = append(service.Endpoints, endpoint)
+	importer.endpoints = append(importer.endpoints, imported)
+}
+
+// endpointName gets a name for a request that is unique within its service
+func (importer *importer) endpointName(service *types.ServiceDefinition, requestName string) string {
+	base := identifiers.MemberName(requestName)
+	if len(base) == 0 {
+		base = "request"
+	}
+
+	name := base
+	for i := 2; hasEndpoint(service, name); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+
+	return name
+}
+
+func hasEndpoint(service *types.ServiceDefinition, name string) bool {
+	for _, endpoint := range service.Endpoints {
+		if endpoint.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// root reserves a unique name to add the samples of a body under
+func (importer *importer) root(base string) string {
+	name := base
+	for i := 2; importer.roots[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	importer.roots[name] = true
+
+	return name
+}
+
+// decodeBody decodes a JSON body. Bodies often contain variables in place of values, such as {"id": {{id}}}, so if a
+// body cannot be decoded as it is, its variables are replaced with null
+func (importer *importer) decodeBody(body string) (any, bool) {
+	sample, err := sampleinfer.Decode(body)
+	if err == nil {
+		return sample, true
+	}
+
+	sample, err = sampleinfer.Decode(variablePattern.ReplaceAllString(body, "null"))
+	return sample, err == nil
+}
+
+// resolveBaseURL gets the base URL of a request, replacing collection variables with their values
+func (importer *importer) resolveBaseURL(url URL) string {
+	host := strings.Join(url.Host, ".")
+	if len(host) == 0 {
+		return ""
+	}
+
+	baseURL := host
+	if len(url.Protocol) > 0 {
+		baseURL = url.Protocol + "://" + host
+	}
+	if len(url.Port) > 0 {
+		baseURL += ":" + url.Port
+	}
+
+	return variablePattern.ReplaceAllStringFunc(baseURL, func(variable string) string {
+		name := strings.TrimSpace(variablePattern.FindStringSubmatch(variable)[1])
+		value, exists := importer.variables[name]
+		if !exists {
+			importer.warn("the base URL '%s' uses the variable '%s', which is not defined by the collection", baseURL, name)
+			return variable
+		}
+
+		return value
+	})
+}
+
+// path gets the endpoint template of a URL. Segments such as ":id" and "{{id}}" become path variables, which are typed
+// by the values the request was saved with
+func (importer *importer) path(url URL, variables map[string]types.RequestValue) string {
+	values := make(map[string]Variable)
+	for _, variable := range url.Variable {
+		values[variable.Key] = variable
+	}
+
+	addVariable := func(name string) {
+		variable := values[name]
+		variables[name] = types.RequestValue{
+			Documentation: types.Documentation{Description: strings.TrimSpace(string(variable.Description))},
+			Type:          valueType(variable.Value),
+			Required:      true,
+		}
+	}
+
+	segments := make([]string, 0, len(url.Path))
+	for _, segment := range url.Path {
+		if len(segment) == 0 {
+			continue
+		}
+
+		if name, isVariable := strings.CutPrefix(segment, ":"); isVariable && len(name) > 0 {
+			addVariable(name)
+			segments = append(segments, "{{"+name+"}}")
+			continue
+		}
+
+		segment = variablePattern.ReplaceAllStringFunc(segment, func(variable string) string {
+			name := strings.TrimSpace(variablePattern.FindStringSubmatch(variable)[1])
+			addVariable(name)
+			return "{{" + name + "}}"
+		})
+		segments = append(segments, segment)
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// valueType infers the type of a path or query variable from the value it was saved with
+func valueType(value string) types.DynamicType {
+	if len(value) == 0 || variablePattern.MatchString(value) {
+		return types.DynamicType{TypeID: types.TypeID_STRING}
+	}
+
+	return sampleinfer.ScalarType(value)
+}
+
+// typeName converts a name from a collection into a PascalCase type name
+func typeName(name, fallback string) string {
+	converted := identifiers.TypeName(name)
+	if len(converted) == 0 {
+		return fallback
+	}
+
+	return converted
+}
@@ -0,0 +1,268 @@
+package postman
+
+import (
+	"encoding/json"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readCollection(t *testing.T) Collection {
+	data, err := os.ReadFile(filepath.Join("testdata", "people.postman_collection.json"))
+	assert.NoError(t, err)
+
+	collection, err := Read(data)
+	assert.NoError(t, err)
+	return collection
+}
+
+func TestImport_ConvertsFoldersAndRequests(t *testing.T) {
+	var warnings []string
+	apiDef, err := Import(readCollection(t), ImportOptions{OnWarning: func(warning string) { warnings = append(warnings, warning) }})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "People API", apiDef.Name)
+	assert.Equal(t, "https://api.acme.com", apiDef.Config.BaseURL)
+	assert.Equal(t, []string{"the formdata body of request 'Upload avatar' is not JSON, so its type is unknown"}, warnings)
+
+	assert.Len(t, apiDef.Services, 2)
+	people, api := apiDef.Services[0], apiDef.Services[1]
+	assert.Equal(t, "People", people.Name)
+	assert.Equal(t, "Manages people", people.Description)
+	assert.Equal(t, "PeopleApi", api.Name)
+	assert.Len(t, people.Endpoints, 4)
+
+	listPeople := people.Endpoints[0]
+	assert.Equal(t, "listPeople", listPeople.Name)
+	assert.Equal(t, "GET", listPeople.Method)
+	assert.Equal(t, "/people", listPeople.Endpoint)
+	assert.Equal(t, map[string]types.RequestValue{
+		"limit": {Documentation: types.Documentation{Description: "Most people to list"}, Type: types.DynamicType{TypeID: types.TypeID_INTEGER}},
+		"q":     {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+	}, listPeople.QueryVariables)
+	assert.True(t, listPeople.RequestBody.Type.IsVoid())
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "ListPeopleResponseItem"}}}, listPeople.ResponseBody.Type)
+
+	getPerson := people.Endpoints[1]
+	assert.Equal(t, "/people/{{id}}", getPerson.Endpoint)
+	assert.Equal(t, "Gets a person by their ID", getPerson.Description)
+	assert.Equal(t, types.RequestValue{Documentation: types.Documentation{Description: "ID of the person"}, Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true}, getPerson.PathVariables["id"])
+	assert.True(t, getPerson.ResponseBody.Type.IsVoid())
+
+	createPerson := people.Endpoints[2]
+	assert.Equal(t, "POST", createPerson.Method)
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "CreatePersonRequest"}, Required: true}, createPerson.RequestBody)
+
+	uploadAvatar := people.Endpoints[3]
+	assert.Equal(t, "PUT", uploadAvatar.Method)
+	assert.Equal(t, "/people/{{personId}}/avatar", uploadAvatar.Endpoint)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_STRING}, uploadAvatar.PathVariables["personId"].Type)
+	assert.Equal(t, types.TypeID_ANY, uploadAvatar.RequestBody.Type.TypeID)
+
+	healthCheck := api.Endpoints[0]
+	assert.Equal(t, "healthCheck", healthCheck.Name)
+	assert.Equal(t, "/health", healthCheck.Endpoint)
+
+	assert.Len(t, apiDef.Entities, 3)
+	person, address, request := apiDef.Entities[0], apiDef.Entities[1], apiDef.Entities[2]
+	assert.Equal(t, "ListPeopleResponseItem", person.Name)
+	assert.True(t, person.Properties["name"].Required)
+	assert.False(t, person.Properties["email"].Required)
+	assert.Equal(t, types.Format_EMAIL, person.Properties["email"].Format)
+	assert.Equal(t, "ListPeopleResponseItemAddress", address.Name)
+	assert.False(t, address.Properties["zip"].Required)
+
+	assert.Equal(t, "CreatePersonRequest", request.Name)
+	assert.Equal(t, types.PropertySpec{Type: types.DynamicType{TypeID: types.TypeID_ANY}}, request.Properties["age"])
+	assert.Equal(t, types.TypeID_DATE, request.Properties["born"].Type.TypeID)
+}
+
+func TestImport_RequiresRequests(t *testing.T) {
+	_, err := Import(Collection{Info: Info{Name: "Empty"}, Item: []Item{{Name: "Folder"}}}, ImportOptions{})
+	assert.EqualError(t, err, "collection 'Empty' has no requests")
+}
+
+func TestRead_RejectsOtherSchemas(t *testing.T) {
+	_, err := Read([]byte(`{"info": {"name": "Old", "schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}, "item": []}`))
+	assert.ErrorContains(t, err, "unsupported collection schema")
+}
+
+func exampleAPI() types.APIDefinition {
+	minimumAge := 18.0
+
+	return types.APIDefinition{
+		Name:    "people",
+		Version: "1.0.0",
+		Naming:  types.NamingStrategy_SNAKE,
+		Config:  types.APIConfig{BaseURL: "http://localhost:8080"},
+		Entities: []types.EntitySpec{
+			{
+				Name: "Person",
+				Properties: map[string]types.PropertySpec{
+					"id":        {Type: types.DynamicType{TypeID: types.TypeID_STRING}, PropertyConstraints: types.PropertyConstraints{Format: types.Format_UUID}, Required: true},
+					"fullName":  {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true, Documentation: types.Documentation{Example: "Ada Lovelace"}},
+					"age":       {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, PropertyConstraints: types.PropertyConstraints{Minimum: &minimumAge}},
+					"status":    {Type: types.DynamicType{TypeID: types.TypeID_STRING}, PropertyConstraints: types.PropertyConstraints{Enum: []any{"active", "inactive"}}},
+					"friends":   {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}, Required: true},
+					"createdAt": {Type: types.DynamicType{TypeID: types.TypeID_TIMESTAMP}, Required: true, WireName: "created"},
+				},
+			},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Documentation: types.Documentation{Description: "Manages people"},
+				Name:          "People",
+				Endpoints: []types.APIEndpoint{
+					{
+						Documentation: types.Documentation{Summary: "Get a person"},
+						Name:          "getPerson",
+						Endpoint:      "/people/{{id}}",
+						Method:        "GET",
+						PathVariables: map[string]types.RequestValue{
+							"id": {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true},
+						},
+						QueryVariables: map[string]types.RequestValue{
+							"expand":  {Type: types.DynamicType{TypeID: types.TypeID_BOOLEAN}, Required: true},
+							"version": {Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+						},
+						RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+						ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}},
+					},
+					{
+						Name:         "createPerson",
+						Endpoint:     "/people",
+						Method:       "POST",
+						RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, Required: true},
+						ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExport_BuildsRequestsWithSampleBodies(t *testing.T) {
+	collection, err := Export(exampleAPI())
+	assert.NoError(t, err)
+
+	assert.Equal(t, Info{Name: "people", Version: "1.0.0", Schema: SchemaURL}, collection.Info)
+	assert.Equal(t, []Variable{{Key: "baseURL", Value: "http://localhost:8080", Type: "string"}}, collection.Variable)
+	assert.Len(t, collection.Item, 1)
+
+	folder := collection.Item[0]
+	assert.Equal(t, "People", folder.Name)
+	assert.Equal(t, Description("Manages people"), folder.Description)
+	assert.Len(t, folder.Item, 2)
+
+	getPerson := folder.Item[0]
+	assert.Equal(t, "getPerson", getPerson.Name)
+	assert.Equal(t, Description("Get a person"), getPerson.Request.Description)
+	assert.Nil(t, getPerson.Request.Body)
+	assert.Equal(t, URL{
+		Raw:      "{{baseURL}}/people/:id?expand=false",
+		Host:     []string{"{{baseURL}}"},
+		Path:     []string{"people", ":id"},
+		Query:    []QueryParam{{Key: "expand", Value: "false"}, {Key: "version", Value: "string", Disabled: true}},
+		Variable: []Variable{{Key: "id", Value: "0"}},
+	}, getPerson.Request.URL)
+	assert.Len(t, getPerson.Response, 1)
+	assert.Equal(t, 200, getPerson.Response[0].Code)
+
+	createPerson := folder.Item[1]
+	assert.Equal(t, "POST", createPerson.Request.Method)
+	assert.Equal(t, "json", createPerson.Request.Body.Options.Raw.Language)
+	assert.Empty(t, createPerson.Response)
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(createPerson.Request.Body.Raw), &body))
+	assert.Equal(t, map[string]any{
+		"id":        "00000000-0000-0000-0000-000000000000",
+		"full_name": "Ada Lovelace",
+		"age":       18.0,
+		"status":    "active",
+		"friends":   []any{map[string]any{}},
+		"created":   "1970-01-01T00:00:00Z",
+	}, body)
+}
+
+func TestExport_RoundTrips(t *testing.T) {
+	collection, err := Export(exampleAPI())
+	assert.NoError(t, err)
+
+	// encode the collection, so that it is read back the way Postman would read it
+	data, err := json.Marshal(collection)
+	assert.NoError(t, err)
+	decoded, err := Read(data)
+	assert.NoError(t, err)
+
+	apiDef, err := Import(decoded, ImportOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "people", apiDef.Name)
+	assert.Equal(t, "http://localhost:8080", apiDef.Config.BaseURL)
+	assert.Len(t, apiDef.Services, 1)
+
+	getPerson := apiDef.Services[0].Endpoints[0]
+	assert.Equal(t, "getPerson", getPerson.Name)
+	assert.Equal(t, "/people/{{id}}", getPerson.Endpoint)
+	assert.Equal(t, types.TypeID_INTEGER, getPerson.PathVariables["id"].Type.TypeID)
+	assert.Equal(t, types.TypeID_BOOLEAN, getPerson.QueryVariables["expand"].Type.TypeID)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "GetPersonResponse"}, getPerson.ResponseBody.Type)
+
+	createPerson := apiDef.Services[0].Endpoints[1]
+	assert.Equal(t, "POST", createPerson.Method)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "CreatePersonRequest"}, createPerson.RequestBody.Type)
+	assert.True(t, createPerson.ResponseBody.Type.IsVoid())
+}
+
+func TestExport_SamplesGenericEntities(t *testing.T) {
+	personType := types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+	apiDef := types.APIDefinition{
+		Name: "people",
+		Entities: []types.EntitySpec{
+			{
+				Name: "Page",
+				Properties: map[string]types.PropertySpec{
+					"content": {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}},
+					"total":   {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}},
+				},
+			},
+			{Name: "Person", Properties: map[string]types.PropertySpec{"name": {Type: types.DynamicType{TypeID: types.TypeID_STRING}}}},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Name: "People",
+				Endpoints: []types.APIEndpoint{
+					{
+						Name:         "replaceAll",
+						Endpoint:     "/people",
+						Method:       "PUT",
+						RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Page", Inner: []types.DynamicType{personType}}},
+						ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+					},
+					{
+						Name:         "tag",
+						Endpoint:     "/people/tags",
+						Method:       "PUT",
+						RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Unknown", Inner: []types.DynamicType{personType}}},
+						ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+					},
+				},
+			},
+		},
+	}
+
+	collection, err := Export(apiDef)
+	assert.NoError(t, err)
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(collection.Item[0].Item[0].Request.Body.Raw), &body))
+	assert.Equal(t, map[string]any{
+		"content": []any{map[string]any{"name": "string"}},
+		"total":   0.0,
+	}, body)
+
+	assert.Equal(t, "null", collection.Item[0].Item[1].Request.Body.Raw)
+}
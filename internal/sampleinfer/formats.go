@@ -0,0 +1,60 @@
+package sampleinfer
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// format is a bit set of the well-known formats that strings can have
+type format int
+
+const (
+	format_TIMESTAMP format = 1 << iota
+	format_DATE
+	format_UUID
+	format_EMAIL
+	format_URI
+
+	format_ALL = format_TIMESTAMP | format_DATE | format_UUID | format_EMAIL | format_URI
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formatsOf gets every format that the string matches
+func formatsOf(value string) format {
+	var formats format
+	if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		formats |= format_TIMESTAMP
+	}
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		formats |= format_DATE
+	}
+	if uuidPattern.MatchString(value) {
+		formats |= format_UUID
+	}
+	if address, err := mail.ParseAddress(value); err == nil && address.Address == value {
+		formats |= format_EMAIL
+	}
+	if uri, err := url.Parse(value); err == nil && len(uri.Scheme) > 0 && len(uri.Host) > 0 {
+		formats |= format_URI
+	}
+
+	return formats
+}
+
+// constraint gets the format constraint of strings that matched these formats
+func (formats format) constraint() string {
+	switch {
+	case formats&format_UUID != 0:
+		return types.Format_UUID
+	case formats&format_EMAIL != 0:
+		return types.Format_EMAIL
+	case formats&format_URI != 0:
+		return types.Format_URI
+	default:
+		return ""
+	}
+}
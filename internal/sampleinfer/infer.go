@@ -0,0 +1,307 @@
+// Package sampleinfer infers types and entities from sample JSON values, such as the bodies of recorded requests and
+// responses. Samples that are given the same name are merged, so a property is only required if every sample has it
+package sampleinfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Inferrer collects named samples and infers the types that describe them
+type Inferrer struct {
+	roots map[string]*shape // merged samples, by name
+	order []string          // names of the samples, in the order they were first added
+}
+
+// Inference is the result of inferring types from samples
+type Inference struct {
+	Types    map[string]types.DynamicType // the type of each name that samples were added under
+	Entities []types.EntitySpec           // the entities that the types reference
+}
+
+// New creates an inferrer without any samples
+func New() *Inferrer {
+	return &Inferrer{
+		roots: make(map[string]*shape),
+	}
+}
+
+// Add merges a sample into the samples that were added under the same name. Samples are values decoded from JSON.
+// Objects that samples contain become entities whose names start with the given name, so it should be a PascalCase
+// type name
+func (inferrer *Inferrer) Add(name string, sample any) {
+	root, exists := inferrer.roots[name]
+	if !exists {
+		root = &shape{}
+		inferrer.roots[name] = root
+		inferrer.order = append(inferrer.order, name)
+	}
+
+	root.add(sample)
+}
+
+// Has checks if any samples were added under the given name
+func (inferrer *Inferrer) Has(name string) bool {
+	_, exists := inferrer.roots[name]
+	return exists
+}
+
+// Infer gets the types of every name that samples were added under, along with the entities that they reference
+func (inferrer *Inferrer) Infer() Inference {
+	resolver := &resolver{
+		names: make(map[*shape]string),
+		taken: make(map[string]bool),
+	}
+
+	inference := Inference{
+		Types: make(map[string]types.DynamicType),
+	}
+	for _, name := range inferrer.order {
+		inference.Types[name] = resolver.resolve(name, inferrer.roots[name])
+	}
+	inference.Entities = resolver.entities
+
+	return inference
+}
+
+// Decode decodes a sample that was sent as JSON. Numbers are kept as they were written, so that integers can be told
+// apart from floats. Fails if anything but space follows the value
+func Decode(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	var sample any
+	err := decoder.Decode(&sample)
+	if err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+
+	return sample, nil
+}
+
+// Scalar decodes text that was not sent as JSON, such as a query variable, into the sample value it most likely
+// represents. Numbers and booleans are decoded, and everything else stays a string
+func Scalar(text string) any {
+	switch text {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if _, err := strconv.ParseFloat(text, 64); err == nil && isJSONNumber(text) {
+		return json.Number(text)
+	}
+
+	return text
+}
+
+// ScalarType infers the type of a single piece of text that was not sent as JSON
+func ScalarType(text string) types.DynamicType {
+	sample := &shape{}
+	sample.add(Scalar(text))
+	return sample.scalarType()
+}
+
+// isJSONNumber checks if the text is written the way JSON writes numbers, which rules out values such as "Inf", "0x1f"
+// and "007" that are more likely identifiers
+func isJSONNumber(text string) bool {
+	var number json.Number
+	return json.Unmarshal([]byte(text), &number) == nil
+}
+
+// kind is a bit set of the JSON kinds that a value was seen as
+type kind int
+
+const (
+	kind_NULL kind = 1 << iota
+	kind_BOOLEAN
+	kind_INTEGER
+	kind_FLOAT
+	kind_STRING
+	kind_ARRAY
+	kind_OBJECT
+)
+
+// shape merges the samples of a single value
+type shape struct {
+	kinds      kind                 // every kind that samples had
+	formats    format               // the formats that every string sample matched
+	element    *shape               // merged elements of every array sample
+	properties map[string]*property // merged properties of every object sample
+	objects    int                  // the number of object samples
+}
+
+// property is a merged property of object samples
+type property struct {
+	shape *shape
+	count int // the number of object samples that had this property
+}
+
+func (s *shape) add(sample any) {
+	switch value := sample.(type) {
+	case nil:
+		s.kinds |= kind_NULL
+
+	case bool:
+		s.kinds |= kind_BOOLEAN
+
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			s.kinds |= kind_INTEGER
+		} else {
+			s.kinds |= kind_FLOAT
+		}
+
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			s.kinds |= kind_INTEGER
+		} else {
+			s.kinds |= kind_FLOAT
+		}
+
+	case string:
+		if s.kinds&kind_STRING == 0 {
+			s.formats = format_ALL
+		}
+		s.kinds |= kind_STRING
+		s.formats &= formatsOf(value)
+
+	case []any:
+		s.kinds |= kind_ARRAY
+		if s.element == nil {
+			s.element = &shape{}
+		}
+		for _, element := range value {
+			s.element.add(element)
+		}
+
+	case map[string]any:
+		s.kinds |= kind_OBJECT
+		if s.properties == nil {
+			s.properties = make(map[string]*property)
+		}
+		s.objects++
+		for name, propertyValue := range value {
+			prop, exists := s.properties[name]
+			if !exists {
+				prop = &property{shape: &shape{}}
+				s.properties[name] = prop
+			}
+			prop.count++
+			prop.shape.add(propertyValue)
+		}
+
+	default:
+		panic(fmt.Sprintf("unsupported sample value of type %T", sample))
+	}
+}
+
+// scalarType gets the type of samples that are neither arrays nor objects
+func (s *shape) scalarType() types.DynamicType {
+	switch s.kinds &^ kind_NULL {
+	case kind_BOOLEAN:
+		return types.DynamicType{TypeID: types.TypeID_BOOLEAN}
+	case kind_INTEGER:
+		return types.DynamicType{TypeID: types.TypeID_INTEGER}
+	case kind_FLOAT, kind_INTEGER | kind_FLOAT:
+		return types.DynamicType{TypeID: types.TypeID_FLOAT}
+	case kind_STRING:
+		if s.formats&format_TIMESTAMP != 0 {
+			return types.DynamicType{TypeID: types.TypeID_TIMESTAMP}
+		}
+		if s.formats&format_DATE != 0 {
+			return types.DynamicType{TypeID: types.TypeID_DATE}
+		}
+		return types.DynamicType{TypeID: types.TypeID_STRING}
+	default:
+		return types.DynamicType{TypeID: types.TypeID_ANY}
+	}
+}
+
+// resolver turns merged samples into types, declaring an entity for every object
+type resolver struct {
+	names    map[*shape]string // names of the entities that were declared for objects
+	taken    map[string]bool   // names of every declared entity
+	entities []types.EntitySpec
+}
+
+// resolve gets the type of merged samples. Objects become entities with the given name
+func (r *resolver) resolve(name string, s *shape) types.DynamicType {
+	switch s.kinds &^ kind_NULL {
+	case kind_ARRAY:
+		element := s.element
+		if element.kinds == 0 {
+			// only empty arrays were seen
+			return types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}
+		}
+
+		return types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{r.resolve(name+"Item", element)}}
+
+	case kind_OBJECT:
+		if len(s.properties) == 0 {
+			return types.DynamicType{
+				TypeID:    types.TypeID_GENERIC,
+				Reference: "Record",
+				Inner:     []types.DynamicType{{TypeID: types.TypeID_STRING}, {TypeID: types.TypeID_ANY}},
+			}
+		}
+
+		return types.DynamicType{TypeID: types.TypeID_USER, Reference: r.declare(name, s)}
+
+	default:
+		return s.scalarType()
+	}
+}
+
+// declare declares the entity of merged object samples, and gets its name. Names are made unique by numbering them
+func (r *resolver) declare(name string, s *shape) string {
+	if declared, exists := r.names[s]; exists {
+		return declared
+	}
+
+	unique := name
+	for i := 2; r.taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	r.names[s] = unique
+	r.taken[unique] = true
+
+	// reserve the position of the entity before declaring the entities of its properties, so that entities are ordered
+	// the way they are nested
+	index := len(r.entities)
+	r.entities = append(r.entities, types.EntitySpec{Name: unique})
+
+	names := make([]string, 0, len(s.properties))
+	for propName := range s.properties {
+		names = append(names, propName)
+	}
+	slices.Sort(names)
+
+	properties := make(map[string]types.PropertySpec, len(names))
+	for _, propName := range names {
+		prop := s.properties[propName]
+		spec := types.PropertySpec{
+			Type:     r.resolve(unique+strcase.ToCamel(propName), prop.shape),
+			Required: prop.count == s.objects && prop.shape.kinds&kind_NULL == 0,
+		}
+		if spec.Type.TypeID == types.TypeID_STRING {
+			spec.Format = prop.shape.formats.constraint()
+		}
+
+		properties[propName] = spec
+	}
+
+	r.entities[index].Properties = properties
+	return unique
+}
@@ -0,0 +1,139 @@
+package sampleinfer
+
+import (
+	"encoding/json"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func decode(t *testing.T, text string) any {
+	sample, err := Decode(text)
+	assert.NoError(t, err)
+	return sample
+}
+
+func TestInferrer_MergesSamples(t *testing.T) {
+	inferrer := New()
+	inferrer.Add("Person", decode(t, `{"id": "1b4e28ba-2fa1-11d2-883f-0016d3cca427", "name": "Ada", "age": 36, "score": 1, "address": {"city": "London"}, "tags": [], "born": "1815-12-10"}`))
+	inferrer.Add("Person", decode(t, `{"id": "6fa459ea-ee8a-3ca4-894e-db77e160355e", "name": "Grace", "age": null, "score": 2.5, "address": {"city": "New York", "zip": "10001"}, "tags": ["navy"], "born": "1906-12-09", "email": "grace@navy.mil"}`))
+
+	inference := inferrer.Infer()
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, inference.Types["Person"])
+	assert.Len(t, inference.Entities, 2)
+
+	person, address := inference.Entities[0], inference.Entities[1]
+	assert.Equal(t, "Person", person.Name)
+	assert.Equal(t, map[string]types.PropertySpec{
+		"id":      {PropertyConstraints: types.PropertyConstraints{Format: types.Format_UUID}, Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+		"name":    {Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true},
+		"age":     {Type: types.DynamicType{TypeID: types.TypeID_INTEGER}},
+		"score":   {Type: types.DynamicType{TypeID: types.TypeID_FLOAT}, Required: true},
+		"address": {Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "PersonAddress"}, Required: true},
+		"tags":    {Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}}}, Required: true},
+		"born":    {Type: types.DynamicType{TypeID: types.TypeID_DATE}, Required: true},
+		"email":   {PropertyConstraints: types.PropertyConstraints{Format: types.Format_EMAIL}, Type: types.DynamicType{TypeID: types.TypeID_STRING}},
+	}, person.Properties)
+
+	assert.Equal(t, "PersonAddress", address.Name)
+	assert.True(t, address.Properties["city"].Required)
+	assert.False(t, address.Properties["zip"].Required)
+}
+
+func TestInferrer_InfersTypes(t *testing.T) {
+	tests := map[string]struct {
+		samples  []string
+		expected types.DynamicType
+		entities []string
+	}{
+		"array of objects": {
+			samples:  []string{`[{"id": 1}, {"id": 2}]`},
+			expected: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "ValueItem"}}},
+			entities: []string{"ValueItem"},
+		},
+		"empty array": {
+			samples:  []string{`[]`},
+			expected: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}},
+		},
+		"empty object": {
+			samples:  []string{`{}`},
+			expected: types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: "Record", Inner: []types.DynamicType{{TypeID: types.TypeID_STRING}, {TypeID: types.TypeID_ANY}}},
+		},
+		"mixed kinds": {
+			samples:  []string{`1`, `"one"`},
+			expected: types.DynamicType{TypeID: types.TypeID_ANY},
+		},
+		"only null": {
+			samples:  []string{`null`},
+			expected: types.DynamicType{TypeID: types.TypeID_ANY},
+		},
+		"timestamps": {
+			samples:  []string{`"2024-01-02T03:04:05Z"`, `"2024-01-02T03:04:05.123+01:00"`},
+			expected: types.DynamicType{TypeID: types.TypeID_TIMESTAMP},
+		},
+		"dates and other strings": {
+			samples:  []string{`"2024-01-02"`, `"tomorrow"`},
+			expected: types.DynamicType{TypeID: types.TypeID_STRING},
+		},
+		"nested arrays": {
+			samples:  []string{`[[true]]`},
+			expected: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_BOOLEAN}}}}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			inferrer := New()
+			for _, sample := range test.samples {
+				inferrer.Add("Value", decode(t, sample))
+			}
+
+			inference := inferrer.Infer()
+			assert.Equal(t, test.expected, inference.Types["Value"])
+
+			var entities []string
+			for _, entity := range inference.Entities {
+				entities = append(entities, entity.Name)
+			}
+			assert.Equal(t, test.entities, entities)
+		})
+	}
+}
+
+func TestInferrer_NumbersCollidingNames(t *testing.T) {
+	inferrer := New()
+	inferrer.Add("PersonAddress", map[string]any{"street": "Main"})
+	inferrer.Add("Person", map[string]any{"address": map[string]any{"city": "Springfield"}})
+
+	inference := inferrer.Infer()
+	assert.Equal(t, "PersonAddress", inference.Types["PersonAddress"].Reference)
+	assert.Equal(t, "PersonAddress2", inference.Entities[2].Name)
+	assert.Equal(t, "PersonAddress2", inference.Entities[1].Properties["address"].Type.Reference)
+}
+
+func TestScalarType(t *testing.T) {
+	tests := map[string]string{
+		"10":                   types.TypeID_INTEGER,
+		"-1.5":                 types.TypeID_FLOAT,
+		"true":                 types.TypeID_BOOLEAN,
+		"007":                  types.TypeID_STRING,
+		"Inf":                  types.TypeID_STRING,
+		"":                     types.TypeID_STRING,
+		"2024-01-02":           types.TypeID_DATE,
+		"2024-01-02T03:04:05Z": types.TypeID_TIMESTAMP,
+		"ada":                  types.TypeID_STRING,
+	}
+
+	for text, expected := range tests {
+		assert.Equal(t, expected, ScalarType(text).TypeID, text)
+	}
+}
+
+func TestDecode_KeepsNumbersAndRejectsTrailingData(t *testing.T) {
+	sample, err := Decode(`{"id": 7}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": json.Number("7")}, sample)
+
+	_, err = Decode(`{"id": 7} {"id": 8}`)
+	assert.ErrorContains(t, err, "unexpected data after the JSON value")
+}
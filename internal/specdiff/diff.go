@@ -0,0 +1,186 @@
+// Package specdiff compares two versions of an API definition, classifying every change by whether it breaks clients
+// that were generated from the older version
+package specdiff
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"slices"
+)
+
+// Severity classifies how a change affects existing clients
+type Severity string
+
+const (
+	Severity_BREAKING     Severity = "breaking"     // clients generated from the old definition may fail, or stop compiling once regenerated
+	Severity_NON_BREAKING Severity = "non-breaking" // clients generated from the old definition keep working
+)
+
+// Change is a single difference between two API definitions
+type Change struct {
+	Severity Severity `json:"severity"`
+	Location string   `json:"location"` // what changed, such as "People.getPerson" or "Person.email"
+	Message  string   `json:"message"`  // describes the change
+}
+
+// Report lists the differences between two API definitions
+type Report struct {
+	Breaking    int      `json:"breaking"`    // number of breaking changes
+	NonBreaking int      `json:"nonBreaking"` // number of non-breaking changes
+	Changes     []Change `json:"changes"`
+}
+
+// HasBreakingChanges checks if any change breaks existing clients
+func (report Report) HasBreakingChanges() bool {
+	return report.Breaking > 0
+}
+
+// Diff compares the old version of an API definition with the new one. Services, endpoints and entities are matched by
+// name. Endpoints that are missing from the new version are matched by their method and path instead, so that they are
+// reported as renamed rather than removed
+func Diff(oldAPI, newAPI types.APIDefinition) Report {
+	differ := &differ{
+		oldAPI: oldAPI,
+		newAPI: newAPI,
+		usage:  newUsage(),
+	}
+	differ.usage.add(oldAPI)
+	differ.usage.add(newAPI)
+
+	differ.services()
+	differ.entities()
+
+	report := Report{Changes: differ.changes}
+	if report.Changes == nil {
+		report.Changes = []Change{}
+	}
+	for _, change := range report.Changes {
+		if change.Severity == Severity_BREAKING {
+			report.Breaking++
+		} else {
+			report.NonBreaking++
+		}
+	}
+
+	return report
+}
+
+// differ collects the changes between two API definitions
+type differ struct {
+	oldAPI  types.APIDefinition
+	newAPI  types.APIDefinition
+	usage   usage // how either version uses its entities
+	changes []Change
+}
+
+func (differ *differ) breaking(location, message string, args ...any) {
+	differ.changes = append(differ.changes, Change{Severity: Severity_BREAKING, Location: location, Message: fmt.Sprintf(message, args...)})
+}
+
+func (differ *differ) nonBreaking(location, message string, args ...any) {
+	differ.changes = append(differ.changes, Change{Severity: Severity_NON_BREAKING, Location: location, Message: fmt.Sprintf(message, args...)})
+}
+
+// deprecation reports a value that became deprecated, which clients should stop relying on
+func (differ *differ) deprecation(location string, oldDoc, newDoc types.Documentation) {
+	if newDoc.Deprecated && !oldDoc.Deprecated {
+		differ.nonBreaking(location, "was deprecated")
+	}
+}
+
+// typeChange reports a value whose type changed
+func (differ *differ) typeChange(location, what string, oldType, newType types.DynamicType) {
+	if oldType.String() != newType.String() {
+		differ.breaking(location, "%s changed from %s to %s", what, oldType, newType)
+	}
+}
+
+// entities compares the entities of both definitions. Whether a property change breaks clients depends on whether the
+// entity is sent to the API, received from it, or both
+func (differ *differ) entities() {
+	newEntities := make(map[string]types.EntitySpec, len(differ.newAPI.Entities))
+	for _, entity := range differ.newAPI.Entities {
+		newEntities[entity.Name] = entity
+	}
+
+	for _, oldEntity := range differ.oldAPI.Entities {
+		newEntity, exists := newEntities[oldEntity.Name]
+		if !exists {
+			differ.breaking(oldEntity.Name, "entity was removed")
+			continue
+		}
+
+		differ.deprecation(oldEntity.Name, oldEntity.Documentation, newEntity.Documentation)
+		differ.properties(oldEntity, newEntity)
+	}
+
+	oldNames := make(map[string]bool, len(differ.oldAPI.Entities))
+	for _, entity := range differ.oldAPI.Entities {
+		oldNames[entity.Name] = true
+	}
+	for _, entity := range differ.newAPI.Entities {
+		if !oldNames[entity.Name] {
+			differ.nonBreaking(entity.Name, "entity was added")
+		}
+	}
+}
+
+// properties compares the properties of an entity
+func (differ *differ) properties(oldEntity, newEntity types.EntitySpec) {
+	sent := differ.usage.isSent(oldEntity.Name)
+	received := differ.usage.isReceived(oldEntity.Name)
+
+	for _, name := range sortedKeys(oldEntity.Properties) {
+		location := oldEntity.Name + "." + name
+		oldProperty := oldEntity.Properties[name]
+		newProperty, exists := newEntity.Properties[name]
+		if !exists {
+			differ.breaking(location, "property was removed")
+			continue
+		}
+
+		oldWireName := oldProperty.ResolveWireName(name, differ.oldAPI.Naming)
+		newWireName := newProperty.ResolveWireName(name, differ.newAPI.Naming)
+		if oldWireName != newWireName {
+			differ.breaking(location, "wire name changed from '%s' to '%s'", oldWireName, newWireName)
+		}
+
+		differ.typeChange(location, "type", oldProperty.Type, newProperty.Type)
+
+		switch {
+		case newProperty.Required && !oldProperty.Required && sent:
+			differ.breaking(location, "property became required, so clients that do not send it fail")
+		case newProperty.Required && !oldProperty.Required:
+			differ.nonBreaking(location, "property became required")
+		case !newProperty.Required && oldProperty.Required && received:
+			differ.breaking(location, "property became optional, so clients that expect it to be present fail")
+		case !newProperty.Required && oldProperty.Required:
+			differ.nonBreaking(location, "property became optional")
+		}
+
+		differ.deprecation(location, oldProperty.Documentation, newProperty.Documentation)
+	}
+
+	for _, name := range sortedKeys(newEntity.Properties) {
+		if _, exists := oldEntity.Properties[name]; exists {
+			continue
+		}
+
+		location := newEntity.Name + "." + name
+		if newEntity.Properties[name].Required && sent {
+			differ.breaking(location, "required property was added, so clients that do not send it fail")
+		} else {
+			differ.nonBreaking(location, "property was added")
+		}
+	}
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,184 @@
+package specdiff
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	stringType  = types.DynamicType{TypeID: types.TypeID_STRING}
+	integerType = types.DynamicType{TypeID: types.TypeID_INTEGER}
+	voidType    = types.DynamicType{TypeID: types.TypeID_VOID}
+	personType  = types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}
+)
+
+// peopleAPI builds a small API whose Person entity is received from getPerson, and whose NewPerson entity is sent to
+// createPerson
+func peopleAPI() types.APIDefinition {
+	return types.APIDefinition{
+		Name: "people",
+		Entities: []types.EntitySpec{
+			{
+				Name: "Person",
+				Properties: map[string]types.PropertySpec{
+					"id":   {Type: integerType, Required: true},
+					"name": {Type: stringType, Required: true},
+					"nick": {Type: stringType},
+				},
+			},
+			{
+				Name: "NewPerson",
+				Properties: map[string]types.PropertySpec{
+					"name": {Type: stringType, Required: true},
+					"nick": {Type: stringType},
+				},
+			},
+		},
+		Services: []types.ServiceDefinition{
+			{
+				Name: "People",
+				Endpoints: []types.APIEndpoint{
+					{
+						Name:           "getPerson",
+						Endpoint:       "/people/{{id}}",
+						Method:         "GET",
+						PathVariables:  map[string]types.RequestValue{"id": {Type: integerType, Required: true}},
+						QueryVariables: map[string]types.RequestValue{"expand": {Type: stringType}},
+						RequestBody:    types.RequestValue{Type: voidType},
+						ResponseBody:   types.RequestValue{Type: personType, Required: true},
+					},
+					{
+						Name:         "createPerson",
+						Endpoint:     "/people",
+						Method:       "POST",
+						RequestBody:  types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "NewPerson"}, Required: true},
+						ResponseBody: types.RequestValue{Type: personType, Required: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDiff_IdenticalDefinitions(t *testing.T) {
+	report := Diff(peopleAPI(), peopleAPI())
+	assert.Equal(t, Report{Changes: []Change{}}, report)
+	assert.False(t, report.HasBreakingChanges())
+}
+
+func TestDiff_ClassifiesEndpointChanges(t *testing.T) {
+	newAPI := peopleAPI()
+	getPerson := &newAPI.Services[0].Endpoints[0]
+	getPerson.Endpoint = "/people/{{personId}}"
+	getPerson.PathVariables = map[string]types.RequestValue{"personId": {Type: stringType, Required: true}}
+	getPerson.QueryVariables = map[string]types.RequestValue{"expand": {Type: stringType, Required: true}, "fields": {Type: stringType}}
+	getPerson.Deprecated = true
+
+	createPerson := &newAPI.Services[0].Endpoints[1]
+	createPerson.Method = "PUT"
+
+	newAPI.Services[0].Endpoints = append(newAPI.Services[0].Endpoints, types.APIEndpoint{
+		Name: "listPeople", Endpoint: "/people", Method: "GET", RequestBody: types.RequestValue{Type: voidType},
+		ResponseBody: types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{personType}}},
+	})
+
+	report := Diff(peopleAPI(), newAPI)
+	assert.Equal(t, []Change{
+		{Severity: Severity_BREAKING, Location: "People.getPerson", Message: "path variable 'id' was renamed to 'personId'"},
+		{Severity: Severity_BREAKING, Location: "People.getPerson", Message: "type of path variable 'personId' changed from INTEGER to STRING"},
+		{Severity: Severity_BREAKING, Location: "People.getPerson", Message: "query variable 'expand' became required"},
+		{Severity: Severity_NON_BREAKING, Location: "People.getPerson", Message: "optional query variable 'fields' was added"},
+		{Severity: Severity_NON_BREAKING, Location: "People.getPerson", Message: "was deprecated"},
+		{Severity: Severity_BREAKING, Location: "People.createPerson", Message: "method changed from POST to PUT"},
+		{Severity: Severity_NON_BREAKING, Location: "People.listPeople", Message: "endpoint GET /people was added"},
+	}, report.Changes)
+	assert.Equal(t, 4, report.Breaking)
+	assert.Equal(t, 3, report.NonBreaking)
+	assert.True(t, report.HasBreakingChanges())
+}
+
+func TestDiff_MatchesRenamedEndpointsByRoute(t *testing.T) {
+	newAPI := peopleAPI()
+	newAPI.Services[0].Endpoints[0].Name = "getPersonById"
+	newAPI.Services[0].Endpoints = newAPI.Services[0].Endpoints[:1]
+
+	report := Diff(peopleAPI(), newAPI)
+	assert.Equal(t, []Change{
+		{Severity: Severity_BREAKING, Location: "People.getPerson", Message: "endpoint was renamed to 'People.getPersonById'"},
+		{Severity: Severity_BREAKING, Location: "People.createPerson", Message: "endpoint POST /people was removed"},
+	}, report.Changes)
+}
+
+func TestDiff_ReportsRemovedServicesOnce(t *testing.T) {
+	newAPI := peopleAPI()
+	newAPI.Services[0].Name = "Persons"
+	newAPI.Services[0].Endpoints = nil
+
+	report := Diff(peopleAPI(), newAPI)
+	assert.Equal(t, []Change{
+		{Severity: Severity_BREAKING, Location: "People", Message: "service was removed"},
+		{Severity: Severity_NON_BREAKING, Location: "Persons", Message: "service was added"},
+	}, report.Changes)
+}
+
+func TestDiff_ClassifiesBodyChanges(t *testing.T) {
+	newAPI := peopleAPI()
+	newAPI.Services[0].Endpoints[0].RequestBody = types.RequestValue{Type: stringType}
+	newAPI.Services[0].Endpoints[0].ResponseBody = types.RequestValue{Type: voidType}
+	newAPI.Services[0].Endpoints[1].RequestBody.Type = personType
+
+	report := Diff(peopleAPI(), newAPI)
+	assert.Equal(t, []Change{
+		{Severity: Severity_NON_BREAKING, Location: "People.getPerson", Message: "optional request body of type STRING was added"},
+		{Severity: Severity_BREAKING, Location: "People.getPerson", Message: "response body was removed"},
+		{Severity: Severity_BREAKING, Location: "People.createPerson", Message: "request body type changed from NewPerson to Person"},
+	}, report.Changes)
+}
+
+func TestDiff_ClassifiesPropertyChangesByDirection(t *testing.T) {
+	newAPI := peopleAPI()
+	person, newPerson := newAPI.Entities[0], newAPI.Entities[1]
+
+	// Person is only received, so clients do not mind fields becoming required, but do mind them becoming optional
+	person.Properties = map[string]types.PropertySpec{
+		"id":    {Type: stringType, Required: true},
+		"name":  {Type: stringType},
+		"nick":  {Type: stringType, Required: true},
+		"email": {Type: stringType, Required: true},
+	}
+
+	// NewPerson is only sent, so the opposite applies
+	newPerson.Properties = map[string]types.PropertySpec{
+		"name":  {Type: stringType},
+		"nick":  {Type: stringType, Required: true, WireName: "nickname"},
+		"email": {Type: stringType, Required: true},
+	}
+
+	newAPI.Entities = []types.EntitySpec{person, newPerson, {Name: "Address"}}
+
+	report := Diff(peopleAPI(), newAPI)
+	assert.Equal(t, []Change{
+		{Severity: Severity_BREAKING, Location: "Person.id", Message: "type changed from INTEGER to STRING"},
+		{Severity: Severity_BREAKING, Location: "Person.name", Message: "property became optional, so clients that expect it to be present fail"},
+		{Severity: Severity_NON_BREAKING, Location: "Person.nick", Message: "property became required"},
+		{Severity: Severity_NON_BREAKING, Location: "Person.email", Message: "property was added"},
+		{Severity: Severity_NON_BREAKING, Location: "NewPerson.name", Message: "property became optional"},
+		{Severity: Severity_BREAKING, Location: "NewPerson.nick", Message: "wire name changed from 'nick' to 'nickname'"},
+		{Severity: Severity_BREAKING, Location: "NewPerson.nick", Message: "property became required, so clients that do not send it fail"},
+		{Severity: Severity_BREAKING, Location: "NewPerson.email", Message: "required property was added, so clients that do not send it fail"},
+		{Severity: Severity_NON_BREAKING, Location: "Address", Message: "entity was added"},
+	}, report.Changes)
+}
+
+func TestDiff_ReportsRemovedEntitiesAndProperties(t *testing.T) {
+	newAPI := peopleAPI()
+	delete(newAPI.Entities[0].Properties, "nick")
+	newAPI.Entities = newAPI.Entities[:1]
+
+	report := Diff(peopleAPI(), newAPI)
+	assert.Equal(t, []Change{
+		{Severity: Severity_BREAKING, Location: "Person.nick", Message: "property was removed"},
+		{Severity: Severity_BREAKING, Location: "NewPerson", Message: "entity was removed"},
+	}, report.Changes)
+}
@@ -0,0 +1,238 @@
+package specdiff
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"regexp"
+	"strings"
+)
+
+// pathVariablePattern matches the variables of an endpoint path, such as "{{id}}"
+var pathVariablePattern = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*}}`)
+
+// located is an endpoint along with the service that declares it
+type located struct {
+	service  string
+	endpoint types.APIEndpoint
+}
+
+func (loc located) String() string {
+	return loc.service + "." + loc.endpoint.Name
+}
+
+// route identifies an endpoint by its method and the shape of its path, regardless of how its variables are named
+func (loc located) route() string {
+	return loc.endpoint.Method + " " + pathShape(loc.endpoint.Endpoint)
+}
+
+// services compares the services of both definitions and the endpoints they declare
+func (differ *differ) services() {
+	oldServices := make(map[string]bool, len(differ.oldAPI.Services))
+	var oldEndpoints []located
+	for _, service := range differ.oldAPI.Services {
+		oldServices[service.Name] = true
+		for _, endpoint := range service.Endpoints {
+			oldEndpoints = append(oldEndpoints, located{service: service.Name, endpoint: endpoint})
+		}
+	}
+
+	newServices := make(map[string]bool, len(differ.newAPI.Services))
+	var newEndpoints []located
+	for _, service := range differ.newAPI.Services {
+		newServices[service.Name] = true
+		for _, endpoint := range service.Endpoints {
+			newEndpoints = append(newEndpoints, located{service: service.Name, endpoint: endpoint})
+		}
+	}
+
+	// match endpoints by name first, then match the remaining ones by route to find endpoints that were renamed
+	matches := make(map[int]int)
+	matched := make(map[int]bool)
+	for oldIdx, oldEndpoint := range oldEndpoints {
+		for newIdx, newEndpoint := range newEndpoints {
+			if !matched[newIdx] && oldEndpoint.String() == newEndpoint.String() {
+				matches[oldIdx] = newIdx
+				matched[newIdx] = true
+				break
+			}
+		}
+	}
+
+	newRoutes := make(map[string][]int)
+	for newIdx, newEndpoint := range newEndpoints {
+		if !matched[newIdx] {
+			newRoutes[newEndpoint.route()] = append(newRoutes[newEndpoint.route()], newIdx)
+		}
+	}
+	oldRoutes := make(map[string]int)
+	for oldIdx, oldEndpoint := range oldEndpoints {
+		if _, isMatched := matches[oldIdx]; !isMatched {
+			oldRoutes[oldEndpoint.route()]++
+		}
+	}
+	for oldIdx, oldEndpoint := range oldEndpoints {
+		if _, isMatched := matches[oldIdx]; isMatched {
+			continue
+		}
+
+		// only rename endpoints whose route is unambiguous
+		candidates := newRoutes[oldEndpoint.route()]
+		if len(candidates) == 1 && oldRoutes[oldEndpoint.route()] == 1 {
+			matches[oldIdx] = candidates[0]
+			matched[candidates[0]] = true
+		}
+	}
+
+	for _, service := range differ.oldAPI.Services {
+		if !newServices[service.Name] {
+			differ.breaking(service.Name, "service was removed")
+		}
+	}
+
+	for oldIdx, oldEndpoint := range oldEndpoints {
+		newIdx, isMatched := matches[oldIdx]
+		switch {
+		case isMatched:
+			differ.endpoint(oldEndpoint, newEndpoints[newIdx])
+		case newServices[oldEndpoint.service]:
+			differ.breaking(oldEndpoint.String(), "endpoint %s %s was removed", oldEndpoint.endpoint.Method, oldEndpoint.endpoint.Endpoint)
+		}
+	}
+
+	for _, service := range differ.newAPI.Services {
+		if !oldServices[service.Name] {
+			differ.nonBreaking(service.Name, "service was added")
+		}
+	}
+
+	for newIdx, newEndpoint := range newEndpoints {
+		if !matched[newIdx] && oldServices[newEndpoint.service] {
+			differ.nonBreaking(newEndpoint.String(), "endpoint %s %s was added", newEndpoint.endpoint.Method, newEndpoint.endpoint.Endpoint)
+		}
+	}
+}
+
+// endpoint compares two versions of an endpoint
+func (differ *differ) endpoint(oldLoc, newLoc located) {
+	location := oldLoc.String()
+	oldEndpoint, newEndpoint := oldLoc.endpoint, newLoc.endpoint
+
+	if oldLoc.String() != newLoc.String() {
+		differ.breaking(location, "endpoint was renamed to '%s'", newLoc)
+	}
+
+	if oldEndpoint.Method != newEndpoint.Method {
+		differ.breaking(location, "method changed from %s to %s", oldEndpoint.Method, newEndpoint.Method)
+	}
+
+	differ.path(location, oldEndpoint, newEndpoint)
+	differ.queryVariables(location, oldEndpoint.QueryVariables, newEndpoint.QueryVariables)
+	differ.requestBody(location, oldEndpoint.RequestBody, newEndpoint.RequestBody)
+
+	oldResponse, newResponse := oldEndpoint.ResponseBody.Type, newEndpoint.ResponseBody.Type
+	switch {
+	case oldResponse.IsVoid() && !newResponse.IsVoid():
+		differ.nonBreaking(location, "response body of type %s was added", newResponse)
+	case !oldResponse.IsVoid() && newResponse.IsVoid():
+		differ.breaking(location, "response body was removed")
+	default:
+		differ.typeChange(location, "response body type", oldResponse, newResponse)
+	}
+
+	differ.deprecation(location, oldEndpoint.Documentation, newEndpoint.Documentation)
+}
+
+// path compares the paths of two versions of an endpoint, along with their path variables
+func (differ *differ) path(location string, oldEndpoint, newEndpoint types.APIEndpoint) {
+	oldVariables := pathVariables(oldEndpoint.Endpoint)
+	newVariables := pathVariables(newEndpoint.Endpoint)
+
+	if pathShape(oldEndpoint.Endpoint) != pathShape(newEndpoint.Endpoint) {
+		differ.breaking(location, "path changed from '%s' to '%s'", oldEndpoint.Endpoint, newEndpoint.Endpoint)
+
+		for _, name := range oldVariables {
+			if newValue, exists := newEndpoint.PathVariables[name]; exists {
+				differ.typeChange(location, "type of path variable '"+name+"'", oldEndpoint.PathVariables[name].Type, newValue.Type)
+			}
+		}
+		return
+	}
+
+	for i, oldName := range oldVariables {
+		newName := newVariables[i]
+		if oldName != newName {
+			differ.breaking(location, "path variable '%s' was renamed to '%s'", oldName, newName)
+		}
+
+		differ.typeChange(location, "type of path variable '"+newName+"'", oldEndpoint.PathVariables[oldName].Type, newEndpoint.PathVariables[newName].Type)
+	}
+}
+
+// queryVariables compares the query variables of two versions of an endpoint
+func (differ *differ) queryVariables(location string, oldVariables, newVariables map[string]types.RequestValue) {
+	for _, name := range sortedKeys(oldVariables) {
+		oldValue := oldVariables[name]
+		newValue, exists := newVariables[name]
+		if !exists {
+			differ.breaking(location, "query variable '%s' was removed", name)
+			continue
+		}
+
+		differ.typeChange(location, "type of query variable '"+name+"'", oldValue.Type, newValue.Type)
+
+		switch {
+		case newValue.Required && !oldValue.Required:
+			differ.breaking(location, "query variable '%s' became required", name)
+		case !newValue.Required && oldValue.Required:
+			differ.nonBreaking(location, "query variable '%s' became optional", name)
+		}
+
+		differ.deprecation(location+"?"+name, oldValue.Documentation, newValue.Documentation)
+	}
+
+	for _, name := range sortedKeys(newVariables) {
+		if _, exists := oldVariables[name]; exists {
+			continue
+		}
+
+		if newVariables[name].Required {
+			differ.breaking(location, "required query variable '%s' was added", name)
+		} else {
+			differ.nonBreaking(location, "optional query variable '%s' was added", name)
+		}
+	}
+}
+
+// requestBody compares the request bodies of two versions of an endpoint
+func (differ *differ) requestBody(location string, oldBody, newBody types.RequestValue) {
+	switch {
+	case oldBody.Type.IsVoid() && newBody.Type.IsVoid():
+		return
+	case oldBody.Type.IsVoid() && newBody.Required:
+		differ.breaking(location, "required request body of type %s was added", newBody.Type)
+	case oldBody.Type.IsVoid():
+		differ.nonBreaking(location, "optional request body of type %s was added", newBody.Type)
+	case newBody.Type.IsVoid():
+		differ.breaking(location, "request body was removed")
+	default:
+		differ.typeChange(location, "request body type", oldBody.Type, newBody.Type)
+		if newBody.Required && !oldBody.Required {
+			differ.breaking(location, "request body became required")
+		}
+	}
+}
+
+// pathVariables gets the names of the variables of an endpoint path, in order
+func pathVariables(path string) []string {
+	var names []string
+	for _, match := range pathVariablePattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
+
+// pathShape replaces the variables of an endpoint path with placeholders, so that paths can be compared regardless of
+// how their variables are named
+func pathShape(path string) string {
+	return strings.TrimSuffix(pathVariablePattern.ReplaceAllString(path, "{}"), "/")
+}
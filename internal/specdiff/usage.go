@@ -0,0 +1,67 @@
+package specdiff
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+)
+
+// usage tracks which entities clients send to the API and which they receive from it, including the entities that
+// are nested in them
+type usage struct {
+	sent     map[string]bool
+	received map[string]bool
+}
+
+func newUsage() usage {
+	return usage{
+		sent:     make(map[string]bool),
+		received: make(map[string]bool),
+	}
+}
+
+// add marks the entities that the endpoints of an API definition send and receive
+func (usage usage) add(apiDef types.APIDefinition) {
+	entities := make(map[string]types.EntitySpec, len(apiDef.Entities))
+	for _, entity := range apiDef.Entities {
+		entities[entity.Name] = entity
+	}
+
+	for _, service := range apiDef.Services {
+		for _, endpoint := range service.Endpoints {
+			mark(usage.sent, entities, endpoint.RequestBody.Type)
+			for _, value := range endpoint.PathVariables {
+				mark(usage.sent, entities, value.Type)
+			}
+			for _, value := range endpoint.QueryVariables {
+				mark(usage.sent, entities, value.Type)
+			}
+
+			mark(usage.received, entities, endpoint.ResponseBody.Type)
+		}
+	}
+}
+
+// isSent checks if clients send an entity to the API. Entities that no endpoint uses may be used in either direction
+func (usage usage) isSent(name string) bool {
+	return usage.sent[name] || !usage.received[name]
+}
+
+// isReceived checks if clients receive an entity from the API. Entities that no endpoint uses may be used in either
+// direction
+func (usage usage) isReceived(name string) bool {
+	return usage.received[name] || !usage.sent[name]
+}
+
+// mark adds the entities that a type refers to, and the entities that their properties refer to, to a set
+func mark(set map[string]bool, entities map[string]types.EntitySpec, dtype types.DynamicType) {
+	for _, reference := range dtype.TypeReferences() {
+		entity, isEntity := entities[reference]
+		if !isEntity || set[reference] {
+			continue
+		}
+
+		set[reference] = true
+		for _, property := range entity.Properties {
+			mark(set, entities, property.Type)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package specfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format that specifications can be written in. Every format decodes into the same API definition
+type Format string
+
+const (
+	Format_JSON  Format = "json"  // plain JSON
+	Format_JSONC Format = "jsonc" // JSON that may contain comments and trailing commas
+	Format_YAML  Format = "yaml"  // YAML
+)
+
+// Formats lists every supported format
+var Formats = []Format{Format_JSON, Format_JSONC, Format_YAML}
+
+// ParseFormat parses the name of a format
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+
+	if strings.EqualFold(name, "yml") {
+		return Format_YAML, nil
+	}
+
+	return "", fmt.Errorf("unknown input format '%s'. Options are %v", name, Formats)
+}
+
+// DetectFormat works out the format of a file from its extension. Returns false if the extension is not recognized
+func DetectFormat(path string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return Format_JSON, true
+	case ".jsonc":
+		return Format_JSONC, true
+	case ".yaml", ".yml":
+		return Format_YAML, true
+	default:
+		return "", false
+	}
+}
+
+// parse parses the contents of a file written in the given format
+func (format Format) parse(file string, contents []byte) (*node, error) {
+	switch format {
+	case Format_JSONC:
+		stripped, err := stripJSONC(file, contents)
+		if err != nil {
+			return nil, err
+		}
+
+		return parseJSON(file, stripped)
+	case Format_YAML:
+		return parseYAML(file, contents)
+	default:
+		return parseJSON(file, contents)
+	}
+}
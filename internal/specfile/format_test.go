@@ -0,0 +1,203 @@
+package specfile
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+const yamlSpec = `# people API
+name: people
+config:
+  baseURL: http://localhost
+entities:
+  - name: Person
+    properties:
+      name:
+        type: { typeID: STRING }
+        required: true
+      age:
+        type: { typeID: INTEGER }
+`
+
+const jsoncSpec = `{
+  // people API
+  "name": "people",
+  "config": { "baseURL": "http://localhost" },
+  /* every entity
+     of the API */
+  "entities": [
+    {
+      "name": "Person",
+      "properties": {
+        "name": { "type": { "typeID": "STRING" }, "required": true, },
+        "age": { "type": { "typeID": "INTEGER" } },
+      },
+    },
+  ],
+}`
+
+const jsonSpec = `{
+  "name": "people",
+  "config": { "baseURL": "http://localhost" },
+  "entities": [
+    {
+      "name": "Person",
+      "properties": {
+        "name": { "type": { "typeID": "STRING" }, "required": true },
+        "age": { "type": { "typeID": "INTEGER" } }
+      }
+    }
+  ]
+}`
+
+func TestResolver_Resolve_DecodesEveryFormatTheSame(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json":  jsonSpec,
+		"api.jsonc": jsoncSpec,
+		"api.yaml":  yamlSpec,
+	})
+
+	expected, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.NoError(t, err)
+	assert.Len(t, expected.Entities, 1)
+
+	for _, name := range []string{"api.jsonc", "api.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			apiDef, err := NewResolver().Resolve(filepath.Join(dir, name))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, apiDef)
+		})
+	}
+}
+
+func TestResolver_Resolve_UsesConfiguredFormatForRootFile(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"spec":        "name: people\ninclude: [people.json]\n",
+		"people.json": `{ "entities": [ { "name": "Person", "properties": {} } ] }`,
+	})
+
+	resolver := NewResolver()
+	resolver.Format = Format_YAML
+	apiDef, err := resolver.Resolve(filepath.Join(dir, "spec"))
+	assert.NoError(t, err)
+	assert.Equal(t, "people", apiDef.Name)
+	assert.Equal(t, "Person", apiDef.Entities[0].Name)
+}
+
+func TestResolver_Resolve_ResolvesRefsAcrossFormats(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.yaml": `name: people
+entities:
+  - name: Person
+    properties:
+      salary:
+        type: { $ref: "common.jsonc#/types/Money" }
+`,
+		"common.jsonc": `{ "types": { "Money": { "typeID": "FLOAT" }, }, }`,
+	})
+
+	apiDef, err := NewResolver().Resolve(filepath.Join(dir, "api.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "FLOAT", apiDef.Entities[0].Properties["salary"].Type.TypeID)
+}
+
+func TestResolver_Resolve_ReportsPositionsOfFormatErrors(t *testing.T) {
+	tests := map[string]struct {
+		file     string
+		contents string
+		expected string
+	}{
+		"yaml syntax error": {
+			file:     "api.yaml",
+			contents: "name: people\nentities:\n  - name: a: b\n",
+			expected: "api.yaml:3:3: mapping values are not allowed in this context",
+		},
+		"yaml invalid entity": {
+			file:     "api.yaml",
+			contents: "entities:\n  - name: [a]\n",
+			expected: "api.yaml:2:5: invalid entity: ",
+		},
+		"yaml duplicate key": {
+			file:     "api.yaml",
+			contents: "name: a\nname: b\n",
+			expected: "api.yaml:2:1: duplicate key 'name'",
+		},
+		"jsonc syntax error": {
+			file:     "api.jsonc",
+			contents: "{\n  // comment\n  \"name\": people,\n}",
+			expected: "api.jsonc:3:11: invalid character 'p'",
+		},
+		"jsonc unclosed comment": {
+			file:     "api.jsonc",
+			contents: "{\n  \"name\": \"people\" /* comment\n}",
+			expected: "api.jsonc:2:20: comment is never closed",
+		},
+		"json comment": {
+			file:     "api.json",
+			contents: "{\n  // comment\n}",
+			expected: "api.json:2:3: invalid character '/'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeSpecFiles(t, map[string]string{test.file: test.contents})
+
+			_, err := NewResolver().Resolve(filepath.Join(dir, test.file))
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestStripJSONC_KeepsStringsAndPositions(t *testing.T) {
+	contents := "{\"url\": \"http://a//b/*c*/\", // note\n\"list\": [1, 2,],}"
+
+	stripped, err := stripJSONC("api.jsonc", []byte(contents))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"url\": \"http://a//b/*c*/\",        \n\"list\": [1, 2 ] }", string(stripped))
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{"json": Format_JSON, "JSONC": Format_JSONC, "yaml": Format_YAML, "yml": Format_YAML} {
+		format, err := ParseFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+
+	_, err := ParseFormat("toml")
+	assert.ErrorContains(t, err, "unknown input format 'toml'")
+}
+
+func TestResolver_Resolve_LimitsAliasExpansion(t *testing.T) {
+	// every level refers to the one before it ten times, so the last one expands into ten billion values
+	contents := "name: people\nlevels:\n  l0: &l0 [x, x, x, x, x, x, x, x, x, x]\n"
+	for level := 1; level < 10; level++ {
+		contents += fmt.Sprintf("  l%d: &l%d [*l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d, *l%d]\n",
+			level, level, level-1, level-1, level-1, level-1, level-1, level-1, level-1, level-1, level-1, level-1)
+	}
+	dir := writeSpecFiles(t, map[string]string{"api.yaml": contents})
+
+	_, err := NewResolver().Resolve(filepath.Join(dir, "api.yaml"))
+	assert.ErrorContains(t, err, "aliases expand into more than 100000 values")
+}
+
+func TestResolver_Resolve_RejectsCommasThatDoNotFollowValues(t *testing.T) {
+	tests := map[string]string{
+		"empty array with comma":  "{\"name\": \"people\", \"entities\": [,]}",
+		"empty object with comma": "{\"name\": \"people\", \"config\": {,}}",
+		"double trailing comma":   "{\"name\": \"people\", \"tags\": [1,,]}",
+		"comma after key":         "{\"name\": \"people\", \"config\": {\"baseURL\": ,}}",
+		"commented out value":     "{\"name\": \"people\", \"entities\": [ /* {} */ ,]}",
+	}
+
+	for name, contents := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeSpecFiles(t, map[string]string{"api.jsonc": contents})
+
+			_, err := NewResolver().Resolve(filepath.Join(dir, "api.jsonc"))
+			assert.ErrorContains(t, err, "invalid character ','")
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package specfile
+
+// stripJSONC turns JSON with comments and trailing commas into plain JSON. Removed characters are replaced with spaces
+// and line breaks are kept, so positions within the result match positions within the original file
+func stripJSONC(file string, contents []byte) ([]byte, error) {
+	stripped := make([]byte, len(contents))
+	copy(stripped, contents)
+
+	// remove comments first, so that they cannot hide trailing commas
+	for idx := 0; idx < len(stripped); idx++ {
+		switch {
+		case stripped[idx] == '"':
+			idx = skipString(stripped, idx)
+
+		case stripped[idx] == '/' && idx+1 < len(stripped) && stripped[idx+1] == '/':
+			for ; idx < len(stripped) && stripped[idx] != '\n'; idx++ {
+				blank(stripped, idx)
+			}
+
+		case stripped[idx] == '/' && idx+1 < len(stripped) && stripped[idx+1] == '*':
+			start := idx
+			blank(stripped, idx)
+			blank(stripped, idx+1)
+			idx += 2
+			for ; idx < len(stripped) && !(stripped[idx] == '*' && idx+1 < len(stripped) && stripped[idx+1] == '/'); idx++ {
+				blank(stripped, idx)
+			}
+
+			if idx >= len(stripped) {
+				return nil, errorAt(positionOf(file, contents, int64(start)), "comment is never closed")
+			}
+
+			blank(stripped, idx)
+			blank(stripped, idx+1)
+			idx++
+		}
+	}
+
+	// then remove commas that follow a value and are only followed by the end of an object or array. Commas that
+	// follow anything else, such as in [,] or [1,,], are kept for the JSON parser to reject
+	var previous byte // last character before the current one that is not a space
+	for idx := 0; idx < len(stripped); idx++ {
+		switch stripped[idx] {
+		case '"':
+			idx = skipString(stripped, idx)
+
+		case ',':
+			next := idx + 1
+			for next < len(stripped) && isSpace(stripped[next]) {
+				next++
+			}
+
+			if next < len(stripped) && (stripped[next] == '}' || stripped[next] == ']') && endsValue(previous) {
+				stripped[idx] = ' '
+				continue
+			}
+		}
+
+		if !isSpace(stripped[idx]) {
+			previous = stripped[idx]
+		}
+	}
+
+	return stripped, nil
+}
+
+// skipString gets the index of the quote that closes the string starting at the given index. Unclosed strings run to
+// the end of the contents, where the JSON parser reports them
+func skipString(contents []byte, start int) int {
+	for idx := start + 1; idx < len(contents); idx++ {
+		switch contents[idx] {
+		case '\\':
+			idx++
+		case '"':
+			return idx
+		}
+	}
+
+	return len(contents)
+}
+
+// blank replaces a character with a space, unless it breaks a line
+func blank(contents []byte, idx int) {
+	if contents[idx] != '\n' && contents[idx] != '\r' {
+		contents[idx] = ' '
+	}
+}
+
+// endsValue checks if a character can be the last one of a value, which is anything but the start of a file, object
+// or array, or a separator
+func endsValue(char byte) bool {
+	switch char {
+	case 0, '{', '[', ',', ':':
+		return false
+	default:
+		return true
+	}
+}
+
+func isSpace(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\r' || char == '\n'
+}
@@ -0,0 +1,268 @@
+package specfile
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"strings"
+)
+
+// declaration records where an entity or service was declared
+type declaration struct {
+	pos Position
+}
+
+// merge adds the entities, services, and external types of every file to the API definition. Entities declared in a
+// namespace are prefixed with it, and references to them are updated to match
+func (resolver *Resolver) merge(apiDef types.APIDefinition, files []specFile) (types.APIDefinition, error) {
+	fileEntities := make([][]types.EntitySpec, len(files))
+	fileEntityNodes := make([][]*node, len(files))
+	namespaces := make(map[string]map[string]bool)
+	for idx, file := range files {
+		entities, entityNodes, err := decodeItems[types.EntitySpec](file.root, "entities", "entity")
+		if err != nil {
+			return types.APIDefinition{}, err
+		}
+
+		fileEntities[idx] = entities
+		fileEntityNodes[idx] = entityNodes
+		if len(file.namespace) > 0 {
+			if namespaces[file.namespace] == nil {
+				namespaces[file.namespace] = make(map[string]bool)
+			}
+
+			for _, entity := range entities {
+				namespaces[file.namespace][entity.Name] = true
+			}
+		}
+	}
+
+	apiDef.Entities = nil
+	apiDef.Services = nil
+	apiDef.ExternalTypes = nil
+	entityDeclarations := make(map[string]declaration)
+	serviceDeclarations := make(map[string]declaration)
+	for idx, file := range files {
+		scope := namespaceScope{namespace: file.namespace, namespaces: namespaces}
+
+		for entityIdx, entity := range fileEntities[idx] {
+			entityNode := fileEntityNodes[idx][entityIdx]
+			entity, err := scope.entity(entity)
+			if err != nil {
+				return types.APIDefinition{}, errorAt(entityNode.pos, "entity '%s': %w", entity.Name, err)
+			}
+
+			err = declare(entityDeclarations, "entity", entity.Name, entityNode.pos)
+			if err != nil {
+				return types.APIDefinition{}, err
+			}
+
+			apiDef.Entities = append(apiDef.Entities, entity)
+		}
+
+		services, serviceNodes, err := decodeItems[types.ServiceDefinition](file.root, "services", "service")
+		if err != nil {
+			return types.APIDefinition{}, err
+		}
+
+		for serviceIdx, service := range services {
+			service, err := scope.service(service)
+			if err != nil {
+				return types.APIDefinition{}, errorAt(serviceNodes[serviceIdx].pos, "service '%s': %w", service.Name, err)
+			}
+
+			err = declare(serviceDeclarations, "service", service.Name, serviceNodes[serviceIdx].pos)
+			if err != nil {
+				return types.APIDefinition{}, err
+			}
+
+			apiDef.Services = append(apiDef.Services, service)
+		}
+
+		externalTypes, _, err := decodeItems[types.ExternalType](file.root, "externalTypes", "external type")
+		if err != nil {
+			return types.APIDefinition{}, err
+		}
+
+		apiDef.ExternalTypes = append(apiDef.ExternalTypes, externalTypes...)
+	}
+
+	return apiDef, nil
+}
+
+// declare records where a name was declared, failing if it was already declared elsewhere
+func declare(declarations map[string]declaration, kind, name string, pos Position) error {
+	if existing, exists := declarations[name]; exists {
+		return errorAt(pos, "%s '%s' is already declared at %s", kind, name, existing.pos)
+	}
+
+	declarations[name] = declaration{pos: pos}
+	return nil
+}
+
+// decodeItems decodes every item of an optional list
+func decodeItems[T any](object *node, key, what string) ([]T, []*node, error) {
+	list, exists := object.field(key)
+	if !exists || (list.kind == nodeKind_SCALAR && list.scalar == nil) {
+		return nil, nil, nil
+	}
+
+	if list.kind != nodeKind_ARRAY {
+		return nil, nil, errorAt(list.pos, "%s must be a list", key)
+	}
+
+	items := make([]T, 0, len(list.items))
+	for _, item := range list.items {
+		var value T
+		err := item.decode(&value, what)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items = append(items, value)
+	}
+
+	return items, list.items, nil
+}
+
+// namespaceScope resolves the names of entities as they are written in a single file. Entities of the file's own
+// namespace can be referenced by their plain names, while entities of other namespaces are referenced by qualified
+// names, such as "Billing.Invoice"
+type namespaceScope struct {
+	namespace  string                     // namespace of the file
+	namespaces map[string]map[string]bool // names of the entities declared in each namespace
+}
+
+// entity prefixes an entity with its namespace and resolves the references of its properties
+func (scope namespaceScope) entity(entity types.EntitySpec) (types.EntitySpec, error) {
+	entity.Name = scope.namespace + entity.Name
+
+	properties := make(map[string]types.PropertySpec, len(entity.Properties))
+	for name, propSpec := range entity.Properties {
+		resolved, err := scope.resolveType(propSpec.Type)
+		if err != nil {
+			return entity, fmt.Errorf("property '%s': %w", name, err)
+		}
+
+		propSpec.Type = resolved
+		properties[name] = propSpec
+	}
+
+	if entity.Properties != nil {
+		entity.Properties = properties
+	}
+
+	return entity, nil
+}
+
+// service resolves the references of every endpoint of a service
+func (scope namespaceScope) service(service types.ServiceDefinition) (types.ServiceDefinition, error) {
+	endpoints := make([]types.APIEndpoint, 0, len(service.Endpoints))
+	for _, endpoint := range service.Endpoints {
+		resolved, err := scope.endpoint(endpoint)
+		if err != nil {
+			return service, fmt.Errorf("endpoint '%s': %w", endpoint.Name, err)
+		}
+
+		endpoints = append(endpoints, resolved)
+	}
+
+	if service.Endpoints != nil {
+		service.Endpoints = endpoints
+	}
+
+	return service, nil
+}
+
+func (scope namespaceScope) endpoint(endpoint types.APIEndpoint) (types.APIEndpoint, error) {
+	var err error
+	endpoint.RequestBody.Type, err = scope.resolveType(endpoint.RequestBody.Type)
+	if err != nil {
+		return endpoint, fmt.Errorf("request body: %w", err)
+	}
+
+	endpoint.ResponseBody.Type, err = scope.resolveType(endpoint.ResponseBody.Type)
+	if err != nil {
+		return endpoint, fmt.Errorf("response body: %w", err)
+	}
+
+	endpoint.PathVariables, err = scope.resolveValues(endpoint.PathVariables)
+	if err != nil {
+		return endpoint, fmt.Errorf("path variable %w", err)
+	}
+
+	endpoint.QueryVariables, err = scope.resolveValues(endpoint.QueryVariables)
+	if err != nil {
+		return endpoint, fmt.Errorf("query variable %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (scope namespaceScope) resolveValues(values map[string]types.RequestValue) (map[string]types.RequestValue, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	resolved := make(map[string]types.RequestValue, len(values))
+	for name, value := range values {
+		resolvedType, err := scope.resolveType(value.Type)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", name, err)
+		}
+
+		value.Type = resolvedType
+		resolved[name] = value
+	}
+
+	return resolved, nil
+}
+
+// resolveType resolves every reference within a type
+func (scope namespaceScope) resolveType(dtype types.DynamicType) (types.DynamicType, error) {
+	if dtype.TypeID == types.TypeID_USER || dtype.TypeID == types.TypeID_GENERIC {
+		resolved, err := scope.resolveReference(dtype.Reference)
+		if err != nil {
+			return dtype, err
+		}
+
+		dtype.Reference = resolved
+	}
+
+	if len(dtype.Inner) > 0 {
+		inner := make([]types.DynamicType, 0, len(dtype.Inner))
+		for _, innerTp := range dtype.Inner {
+			resolved, err := scope.resolveType(innerTp)
+			if err != nil {
+				return dtype, err
+			}
+
+			inner = append(inner, resolved)
+		}
+		dtype.Inner = inner
+	}
+
+	return dtype, nil
+}
+
+// resolveReference gets the name of the entity a reference refers to. References that are not to a namespaced entity
+// are left as they are
+func (scope namespaceScope) resolveReference(reference string) (string, error) {
+	if namespace, name, qualified := strings.Cut(reference, "."); qualified {
+		entities, isNamespace := scope.namespaces[namespace]
+		if !isNamespace {
+			return reference, nil
+		}
+
+		if !entities[name] {
+			return "", fmt.Errorf("namespace '%s' does not declare '%s'", namespace, name)
+		}
+
+		return namespace + name, nil
+	}
+
+	if len(scope.namespace) > 0 && scope.namespaces[scope.namespace][reference] {
+		return scope.namespace + reference, nil
+	}
+
+	return reference, nil
+}
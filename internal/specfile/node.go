@@ -0,0 +1,280 @@
+package specfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Position is a location within a specification file
+type Position struct {
+	File   string // path of the file
+	Line   int    // 1-based line number
+	Column int    // 1-based column, counted in bytes
+}
+
+func (pos Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", pos.File, pos.Line, pos.Column)
+}
+
+// Error is an error found at a position within a specification file
+type Error struct {
+	Position Position
+	Err      error
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s: %s", err.Position, err.Err)
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// errorAt creates an error at the position of a node
+func errorAt(pos Position, format string, args ...any) error {
+	return &Error{Position: pos, Err: fmt.Errorf(format, args...)}
+}
+
+type nodeKind int
+
+const (
+	nodeKind_OBJECT nodeKind = iota
+	nodeKind_ARRAY
+	nodeKind_SCALAR
+)
+
+// node is a value parsed from a specification file that remembers where it was found, so that errors found while
+// resolving the specification can point at the file and line that caused them
+type node struct {
+	kind   nodeKind
+	pos    Position
+	keys   []string         // keys of an object, in the order they were written
+	fields map[string]*node // values of an object by key
+	items  []*node          // items of an array
+	scalar any              // value of a scalar, which is a string, json.Number, bool, or nil
+}
+
+// field gets the value of an object key, if the node is an object that has it
+func (n *node) field(key string) (*node, bool) {
+	if n.kind != nodeKind_OBJECT {
+		return nil, false
+	}
+
+	value, exists := n.fields[key]
+	return value, exists
+}
+
+// without gets a copy of an object without the given keys
+func (n *node) without(keys ...string) *node {
+	stripped := *n
+	stripped.keys = nil
+	stripped.fields = make(map[string]*node, len(n.fields))
+	for _, key := range n.keys {
+		if !slices.Contains(keys, key) {
+			stripped.keys = append(stripped.keys, key)
+			stripped.fields[key] = n.fields[key]
+		}
+	}
+
+	return &stripped
+}
+
+// MarshalJSON writes the node back out as JSON, keeping the order of object keys
+func (n *node) MarshalJSON() ([]byte, error) {
+	switch n.kind {
+	case nodeKind_OBJECT:
+		var buffer bytes.Buffer
+		buffer.WriteByte('{')
+		for idx, key := range n.keys {
+			if idx > 0 {
+				buffer.WriteByte(',')
+			}
+
+			encodedKey, err := json.Marshal(key)
+			if err != nil {
+				return nil, err
+			}
+
+			encodedValue, err := n.fields[key].MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+
+			buffer.Write(encodedKey)
+			buffer.WriteByte(':')
+			buffer.Write(encodedValue)
+		}
+		buffer.WriteByte('}')
+		return buffer.Bytes(), nil
+
+	case nodeKind_ARRAY:
+		var buffer bytes.Buffer
+		buffer.WriteByte('[')
+		for idx, item := range n.items {
+			if idx > 0 {
+				buffer.WriteByte(',')
+			}
+
+			encodedItem, err := item.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			buffer.Write(encodedItem)
+		}
+		buffer.WriteByte(']')
+		return buffer.Bytes(), nil
+
+	default:
+		return json.Marshal(n.scalar)
+	}
+}
+
+// decode decodes a node into the given value, reporting any error at the position of the node
+func (n *node) decode(value any, what string) error {
+	encoded, err := n.MarshalJSON()
+	if err != nil {
+		return errorAt(n.pos, "failed to encode %s: %w", what, err)
+	}
+
+	err = json.Unmarshal(encoded, value)
+	if err != nil {
+		return errorAt(n.pos, "invalid %s: %w", what, err)
+	}
+
+	return nil
+}
+
+// jsonParser parses JSON into nodes, keeping track of where each value starts
+type jsonParser struct {
+	file    string
+	data    []byte
+	decoder *json.Decoder
+}
+
+// parseJSON parses a JSON document into nodes
+func parseJSON(file string, data []byte) (*node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	parser := jsonParser{file: file, data: data, decoder: decoder}
+	root, err := parser.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = decoder.Token()
+	if err != io.EOF {
+		return nil, errorAt(parser.position(decoder.InputOffset()), "unexpected data after the end of the document")
+	}
+
+	return root, nil
+}
+
+func (parser *jsonParser) parseValue() (*node, error) {
+	pos := parser.position(parser.valueStart(parser.decoder.InputOffset()))
+	token, err := parser.decoder.Token()
+	if err != nil {
+		return nil, parser.syntaxError(err)
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := &node{kind: nodeKind_OBJECT, pos: pos, fields: make(map[string]*node)}
+		for parser.decoder.More() {
+			keyPos := parser.position(parser.valueStart(parser.decoder.InputOffset()))
+			keyToken, err := parser.decoder.Token()
+			if err != nil {
+				return nil, parser.syntaxError(err)
+			}
+
+			key := keyToken.(string)
+			value, err := parser.parseValue()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, exists := object.fields[key]; exists {
+				return nil, errorAt(keyPos, "duplicate key '%s'", key)
+			}
+
+			object.keys = append(object.keys, key)
+			object.fields[key] = value
+		}
+
+		return object, parser.closeDelim()
+
+	case json.Delim('['):
+		array := &node{kind: nodeKind_ARRAY, pos: pos}
+		for parser.decoder.More() {
+			item, err := parser.parseValue()
+			if err != nil {
+				return nil, err
+			}
+
+			array.items = append(array.items, item)
+		}
+
+		return array, parser.closeDelim()
+
+	default:
+		return &node{kind: nodeKind_SCALAR, pos: pos, scalar: token}, nil
+	}
+}
+
+func (parser *jsonParser) closeDelim() error {
+	_, err := parser.decoder.Token()
+	if err != nil {
+		return parser.syntaxError(err)
+	}
+
+	return nil
+}
+
+// valueStart skips the whitespace and separators that the decoder leaves in front of the next value
+func (parser *jsonParser) valueStart(offset int64) int64 {
+	for offset < int64(len(parser.data)) {
+		switch parser.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+
+	return offset
+}
+
+func (parser *jsonParser) syntaxError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// the offset is just past the character that caused the error
+		return errorAt(parser.position(max(syntaxErr.Offset-1, 0)), "%s", syntaxErr.Error())
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errorAt(parser.position(int64(len(parser.data))), "unexpected end of file")
+	}
+
+	return errorAt(parser.position(parser.decoder.InputOffset()), "%s", err.Error())
+}
+
+// position gets the line and column of a byte offset
+func (parser *jsonParser) position(offset int64) Position {
+	return positionOf(parser.file, parser.data, offset)
+}
+
+// positionOf gets the line and column of a byte offset within a file's contents
+func positionOf(file string, data []byte, offset int64) Position {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return Position{File: file, Line: line, Column: column}
+}
@@ -0,0 +1,374 @@
+// Package specfile loads API specifications, which may be split across multiple files and written in JSON, JSON with
+// comments, or YAML.
+package specfile
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	includeKey   = "include"   // lists files whose entities, services, and external types are added to the specification
+	namespaceKey = "namespace" // prefixes the names of the entities declared in a file
+	refKey       = "$ref"      // replaces an object with the value a JSON pointer points at
+)
+
+// maxReferencedNodes limits how many nodes $refs can expand into across a whole specification. Referenced values can
+// hold $refs themselves, so a small specification could otherwise expand exponentially
+const maxReferencedNodes = 100_000
+
+// rootOnlyKeys can only be set by the root specification, as they describe the whole API
+var rootOnlyKeys = []string{"name", "version", "config", "naming"}
+
+// Resolver loads API specifications that are split across files. The root file is an API definition that can list
+// other files under "include", each of which adds its entities, services, and external types to the specification.
+// Any object can be replaced by the value a "$ref" JSON pointer points at, such as "common.json#/types/Money", and
+// files can declare a "namespace" that prefixes the names of their entities. Paths are relative to the file they are
+// written in. Files can be written in any Format, which is detected from their extensions
+type Resolver struct {
+	Format     Format           // optionally overrides the format of the root file, and of other files with unknown extensions
+	documents  map[string]*node // parsed files, by absolute path
+	files      []string         // every file that was loaded, in the order it was loaded
+	rootPath   string           // absolute path of the root file
+	referenced int              // nodes that $refs have expanded into so far
+}
+
+// specFile is a file of the specification that contributes entities, services, and external types
+type specFile struct {
+	path      string // path of the file
+	namespace string // prefix of the names of the file's entities
+	root      *node  // contents of the file, with every $ref resolved
+}
+
+// NewResolver creates a resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		documents: make(map[string]*node),
+	}
+}
+
+// Files gets every file that the most recent resolution loaded, even if it failed, so that they can be watched
+func (resolver *Resolver) Files() []string {
+	return slices.Clone(resolver.files)
+}
+
+// Resolve loads the specification rooted at the given file, along with every file it includes or references
+func (resolver *Resolver) Resolve(rootPath string) (types.APIDefinition, error) {
+	resolver.documents = make(map[string]*node)
+	resolver.files = nil
+	resolver.referenced = 0
+	resolver.rootPath, _ = filepath.Abs(rootPath)
+
+	var files []specFile
+	err := resolver.collect(filepath.Clean(rootPath), nil, Position{}, make(map[string]bool), &files)
+	if err != nil {
+		return types.APIDefinition{}, err
+	}
+
+	var apiDef types.APIDefinition
+	err = files[0].root.without(includeKey, namespaceKey, "entities", "services", "externalTypes").decode(&apiDef, "API definition")
+	if err != nil {
+		return types.APIDefinition{}, err
+	}
+
+	return resolver.merge(apiDef, files)
+}
+
+// collect loads a file and every file it includes, depth first. Files that were already included through another
+// file are skipped, while a file that includes itself is an error
+func (resolver *Resolver) collect(path string, including []string, includedAt Position, included map[string]bool, files *[]specFile) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path of '%s': %w", path, err)
+	}
+
+	chain := append(slices.Clone(including), path)
+	for _, includingPath := range including {
+		if sameFile(includingPath, absPath) {
+			return errorAt(includedAt, "include cycle: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	if included[absPath] {
+		return nil
+	}
+	included[absPath] = true
+
+	document, err := resolver.load(path)
+	if err != nil {
+		if len(including) == 0 {
+			return err
+		}
+
+		return &Error{Position: includedAt, Err: err}
+	}
+
+	resolved, err := resolver.resolveRefs(document, path, nil)
+	if err != nil {
+		return err
+	}
+
+	if resolved.kind != nodeKind_OBJECT {
+		return errorAt(resolved.pos, "specification must be an object")
+	}
+
+	if len(including) > 0 {
+		for _, key := range rootOnlyKeys {
+			if value, exists := resolved.field(key); exists {
+				return errorAt(value.pos, "'%s' can only be set by the root specification", key)
+			}
+		}
+	}
+
+	namespace, err := stringField(resolved, namespaceKey)
+	if err != nil {
+		return err
+	}
+
+	*files = append(*files, specFile{path: path, namespace: namespace, root: resolved})
+
+	includes, exists := resolved.field(includeKey)
+	if !exists {
+		return nil
+	}
+
+	if includes.kind != nodeKind_ARRAY {
+		return errorAt(includes.pos, "include must be a list of paths")
+	}
+
+	for _, include := range includes.items {
+		pattern, isString := include.scalar.(string)
+		if include.kind != nodeKind_SCALAR || !isString || len(pattern) == 0 {
+			return errorAt(include.pos, "include must be a path")
+		}
+
+		paths, err := expandInclude(filepath.Join(filepath.Dir(path), filepath.FromSlash(pattern)))
+		if err != nil {
+			return &Error{Position: include.pos, Err: err}
+		}
+
+		for _, includedPath := range paths {
+			err = resolver.collect(includedPath, chain, include.pos, included, files)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// expandInclude gets the files an include refers to. Includes may be glob patterns, such as "services/*.json"
+func expandInclude(pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern '%s': %w", pattern, err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("include pattern '%s' does not match any files", pattern)
+	}
+
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// load parses a file, reusing files that were already parsed
+func (resolver *Resolver) load(path string) (*node, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of '%s': %w", path, err)
+	}
+
+	if document, exists := resolver.documents[absPath]; exists {
+		return document, nil
+	}
+
+	resolver.files = append(resolver.files, path)
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+
+	document, err := resolver.formatOf(absPath, path).parse(path, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver.documents[absPath] = document
+	return document, nil
+}
+
+// formatOf gets the format of a file. The configured format takes precedence for the root file, while other files
+// only fall back to it if their extensions are not recognized
+func (resolver *Resolver) formatOf(absPath, path string) Format {
+	if absPath == resolver.rootPath && len(resolver.Format) > 0 {
+		return resolver.Format
+	}
+
+	if format, detected := DetectFormat(path); detected {
+		return format
+	}
+
+	if len(resolver.Format) > 0 {
+		return resolver.Format
+	}
+
+	return Format_JSON
+}
+
+// resolveRefs replaces every $ref within a node with the value it points at. References are resolved relative to the
+// file that contains them, and the chain of references being followed is kept to detect cycles
+func (resolver *Resolver) resolveRefs(n *node, file string, following []string) (*node, error) {
+	if len(following) > 0 {
+		resolver.referenced++
+		if resolver.referenced > maxReferencedNodes {
+			return nil, errorAt(n.pos, "$refs expand into more than %d values", maxReferencedNodes)
+		}
+	}
+
+	switch n.kind {
+	case nodeKind_OBJECT:
+		if ref, isRef := n.field(refKey); isRef {
+			if len(n.keys) != 1 {
+				return nil, errorAt(n.pos, "$ref cannot be combined with other keys")
+			}
+
+			target, targetFile, key, err := resolver.lookupRef(ref, file)
+			if err != nil {
+				return nil, err
+			}
+
+			chain := append(slices.Clone(following), key)
+			if slices.Contains(following, key) {
+				return nil, errorAt(ref.pos, "$ref cycle: %s", strings.Join(chain, " -> "))
+			}
+
+			return resolver.resolveRefs(target, targetFile, chain)
+		}
+
+		resolved := *n
+		resolved.fields = make(map[string]*node, len(n.fields))
+		for _, key := range n.keys {
+			value, err := resolver.resolveRefs(n.fields[key], file, following)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved.fields[key] = value
+		}
+
+		return &resolved, nil
+
+	case nodeKind_ARRAY:
+		resolved := *n
+		resolved.items = make([]*node, 0, len(n.items))
+		for _, item := range n.items {
+			resolvedItem, err := resolver.resolveRefs(item, file, following)
+			if err != nil {
+				return nil, err
+			}
+
+			resolved.items = append(resolved.items, resolvedItem)
+		}
+
+		return &resolved, nil
+
+	default:
+		return n, nil
+	}
+}
+
+// lookupRef finds the value a $ref points at. References are either a JSON pointer into the same file, such as
+// "#/types/Money", or a path to another file followed by an optional pointer. Returns the value, the file that
+// contains it, and a key that identifies it
+func (resolver *Resolver) lookupRef(ref *node, file string) (*node, string, string, error) {
+	refValue, isString := ref.scalar.(string)
+	if ref.kind != nodeKind_SCALAR || !isString {
+		return nil, "", "", errorAt(ref.pos, "$ref must be a string")
+	}
+
+	refPath, pointer, _ := strings.Cut(refValue, "#")
+	targetFile := file
+	if len(refPath) > 0 {
+		targetFile = filepath.Join(filepath.Dir(file), filepath.FromSlash(refPath))
+	}
+
+	document, err := resolver.load(targetFile)
+	if err != nil {
+		return nil, "", "", &Error{Position: ref.pos, Err: err}
+	}
+
+	target, err := followPointer(document, pointer)
+	if err != nil {
+		return nil, "", "", errorAt(ref.pos, "$ref '%s' cannot be resolved: %w", refValue, err)
+	}
+
+	return target, targetFile, fmt.Sprintf("%s#%s", targetFile, pointer), nil
+}
+
+// followPointer finds the value a JSON pointer points at
+func followPointer(document *node, pointer string) (*node, error) {
+	if len(pointer) == 0 {
+		return document, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer '%s' must start with '/'", pointer)
+	}
+
+	current := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch current.kind {
+		case nodeKind_OBJECT:
+			value, exists := current.field(token)
+			if !exists {
+				return nil, fmt.Errorf("no key '%s'", token)
+			}
+			current = value
+
+		case nodeKind_ARRAY:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(current.items) {
+				return nil, fmt.Errorf("no item '%s'", token)
+			}
+			current = current.items[idx]
+
+		default:
+			return nil, fmt.Errorf("cannot look up '%s' in a value that is not an object or list", token)
+		}
+	}
+
+	return current, nil
+}
+
+// stringField gets the value of an optional string key of an object
+func stringField(object *node, key string) (string, error) {
+	value, exists := object.field(key)
+	if !exists {
+		return "", nil
+	}
+
+	str, isString := value.scalar.(string)
+	if value.kind != nodeKind_SCALAR || !isString || len(str) == 0 {
+		return "", errorAt(value.pos, "%s must be a non-empty string", key)
+	}
+
+	return str, nil
+}
+
+// sameFile checks if a path refers to the same file as an absolute path
+func sameFile(path, absPath string) bool {
+	pathAbs, err := filepath.Abs(path)
+	return err == nil && pathAbs == absPath
+}
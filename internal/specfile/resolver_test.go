@@ -0,0 +1,257 @@
+package specfile
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSpecFiles writes each file into a temporary directory, returning the directory
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	return dir
+}
+
+func TestResolver_Resolve_ReadsSingleFileSpecs(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{
+  "name": "people",
+  "config": { "baseURL": "http://localhost" },
+  "entities": [
+    { "name": "Person", "properties": { "name": { "type": { "typeID": "STRING" }, "required": true } } }
+  ]
+}`,
+	})
+
+	apiDef, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "people", apiDef.Name)
+	assert.Equal(t, "http://localhost", apiDef.Config.BaseURL)
+	assert.Len(t, apiDef.Entities, 1)
+	assert.Equal(t, "Person", apiDef.Entities[0].Name)
+}
+
+func TestResolver_Resolve_IncludesFiles(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{
+  "name": "platform",
+  "include": ["models/people.json", "services/*.json"],
+  "entities": [ { "name": "Root", "properties": {} } ]
+}`,
+		"models/people.json": `{
+  "entities": [ { "name": "Person", "properties": {} } ],
+  "externalTypes": [ { "name": "Money", "module": "@acme/money" } ]
+}`,
+		"services/a.json": `{ "services": [ { "name": "Alpha", "endpoints": [] } ] }`,
+		"services/b.json": `{ "services": [ { "name": "Beta", "endpoints": [] } ] }`,
+	})
+
+	resolver := NewResolver()
+	apiDef, err := resolver.Resolve(filepath.Join(dir, "api.json"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"Root", "Person"}, []string{apiDef.Entities[0].Name, apiDef.Entities[1].Name})
+	assert.Equal(t, []string{"Alpha", "Beta"}, []string{apiDef.Services[0].Name, apiDef.Services[1].Name})
+	assert.Equal(t, []types.ExternalType{{Name: "Money", Module: "@acme/money"}}, apiDef.ExternalTypes)
+	assert.Len(t, resolver.Files(), 4)
+}
+
+func TestResolver_Resolve_ResolvesRefs(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{
+  "name": "people",
+  "definitions": {
+    "id": { "type": { "typeID": "STRING" }, "required": true }
+  },
+  "entities": [
+    {
+      "name": "Person",
+      "properties": {
+        "id": { "$ref": "#/definitions/id" },
+        "salary": { "type": { "$ref": "common.json#/types/Money" } }
+      }
+    }
+  ]
+}`,
+		"common.json": `{
+  "types": {
+    "Money": { "$ref": "#/types/Decimal" },
+    "Decimal": { "typeID": "FLOAT" }
+  }
+}`,
+	})
+
+	apiDef, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.NoError(t, err)
+
+	properties := apiDef.Entities[0].Properties
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_STRING}, properties["id"].Type)
+	assert.True(t, properties["id"].Required)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_FLOAT}, properties["salary"].Type)
+}
+
+func TestResolver_Resolve_PrefixesNamespacedEntities(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{
+  "name": "platform",
+  "include": ["billing.json"],
+  "services": [
+    {
+      "name": "Payments",
+      "endpoints": [
+        {
+          "name": "get",
+          "method": "GET",
+          "endpoint": "/invoices",
+          "responseBody": { "type": { "typeID": "USER", "reference": "Billing.Invoice" } }
+        }
+      ]
+    }
+  ]
+}`,
+		"billing.json": `{
+  "namespace": "Billing",
+  "entities": [
+    { "name": "Invoice", "properties": { "lines": { "type": { "typeID": "ARRAY", "nested": [ { "typeID": "USER", "reference": "Line" } ] } } } },
+    { "name": "Line", "properties": {} }
+  ]
+}`,
+	})
+
+	apiDef, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "BillingInvoice", apiDef.Entities[0].Name)
+	assert.Equal(t, "BillingLine", apiDef.Entities[1].Name)
+	assert.Equal(t, "BillingLine", apiDef.Entities[0].Properties["lines"].Type.Inner[0].Reference)
+	assert.Equal(t, "BillingInvoice", apiDef.Services[0].Endpoints[0].ResponseBody.Type.Reference)
+}
+
+func TestResolver_Resolve_FailsForUnknownNamespacedEntities(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{
+  "include": ["billing.json"],
+  "entities": [
+    { "name": "Order", "properties": { "invoice": { "type": { "typeID": "USER", "reference": "Billing.Receipt" } } } }
+  ]
+}`,
+		"billing.json": `{ "namespace": "Billing", "entities": [ { "name": "Invoice", "properties": {} } ] }`,
+	})
+
+	_, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.ErrorContains(t, err, "api.json:4:5: entity 'Order': property 'invoice': namespace 'Billing' does not declare 'Receipt'")
+}
+
+func TestResolver_Resolve_FailsForIncludeCycles(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{ "include": ["a.json"] }`,
+		"a.json":   `{ "include": ["b.json"] }`,
+		"b.json": `{
+  "include": ["api.json"]
+}`,
+	})
+
+	_, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.ErrorContains(t, err, "b.json:2:15: include cycle: ")
+	assert.ErrorContains(t, err, "a.json -> ")
+}
+
+func TestResolver_Resolve_FailsForRefCycles(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": `{
+  "types": {
+    "A": { "$ref": "#/types/B" },
+    "B": { "$ref": "#/types/A" }
+  },
+  "entities": [ { "name": "Person", "properties": { "a": { "type": { "$ref": "#/types/A" } } } } ]
+}`,
+	})
+
+	_, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.ErrorContains(t, err, "api.json:3:20: $ref cycle: ")
+	assert.ErrorContains(t, err, "#/types/B -> ")
+}
+
+func TestResolver_Resolve_LimitsRefExpansion(t *testing.T) {
+	// every level refers to the one before it ten times, so the last one expands into a billion values
+	levels := []string{`"l0": [` + strings.Repeat(`"x", `, 9) + `"x"]`}
+	for level := 1; level < 9; level++ {
+		ref := fmt.Sprintf(`{ "$ref": "#/levels/l%d" }`, level-1)
+		levels = append(levels, fmt.Sprintf(`"l%d": [`, level)+strings.Repeat(ref+", ", 9)+ref+"]")
+	}
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json": "{\n  \"name\": \"people\",\n  \"levels\": {\n    " + strings.Join(levels, ",\n    ") + "\n  }\n}",
+	})
+
+	_, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.ErrorContains(t, err, "$refs expand into more than 100000 values")
+}
+
+func TestResolver_Resolve_ReportsPositionsOfErrors(t *testing.T) {
+	tests := map[string]struct {
+		contents string
+		expected string
+	}{
+		"syntax error": {
+			contents: "{\n  \"name\": \"people\",\n  \"entities\": [,]\n}",
+			expected: "api.json:3:16: invalid character ','",
+		},
+		"unresolved ref": {
+			contents: "{\n  \"entities\": [\n    { \"$ref\": \"#/missing\" }\n  ]\n}",
+			expected: "api.json:3:15: $ref '#/missing' cannot be resolved: no key 'missing'",
+		},
+		"invalid entity": {
+			contents: "{\n  \"entities\": [\n    { \"name\": 42 }\n  ]\n}",
+			expected: "api.json:3:5: invalid entity: ",
+		},
+		"duplicate entity": {
+			contents: "{\n  \"entities\": [\n    { \"name\": \"A\" },\n    { \"name\": \"A\" }\n  ]\n}",
+			expected: "api.json:4:5: entity 'A' is already declared at ",
+		},
+		"duplicate key": {
+			contents: "{\n  \"name\": \"a\",\n  \"name\": \"b\"\n}",
+			expected: "api.json:3:3: duplicate key 'name'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeSpecFiles(t, map[string]string{"api.json": test.contents})
+
+			_, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestResolver_Resolve_RejectsRootOnlyKeysInIncludedFiles(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json":    `{ "include": ["people.json"] }`,
+		"people.json": "{\n  \"config\": {}\n}",
+	})
+
+	_, err := NewResolver().Resolve(filepath.Join(dir, "api.json"))
+	assert.ErrorContains(t, err, "people.json:2:13: 'config' can only be set by the root specification")
+}
+
+func TestResolver_Files_KeepsFilesOfFailedResolutions(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"api.json":    `{ "include": ["broken.json"] }`,
+		"broken.json": `{`,
+	})
+
+	resolver := NewResolver()
+	_, err := resolver.Resolve(filepath.Join(dir, "api.json"))
+	assert.Error(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "api.json"), filepath.Join(dir, "broken.json")}, resolver.Files())
+}
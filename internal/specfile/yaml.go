@@ -0,0 +1,171 @@
+package specfile
+
+import (
+	"bytes"
+	"gopkg.in/yaml.v3"
+	"regexp"
+	"strconv"
+)
+
+// yamlErrorLine matches the line that yaml reports syntax errors at
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// parseYAML parses a YAML document into nodes
+func parseYAML(file string, contents []byte) (*node, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, yamlSyntaxError(file, contents, err)
+	}
+
+	if document.Kind == 0 || len(document.Content) == 0 {
+		return nil, errorAt(Position{File: file, Line: 1, Column: 1}, "document is empty")
+	}
+
+	converter := &yamlConverter{file: file}
+	return converter.convert(document.Content[0], 0)
+}
+
+// yamlSyntaxError reports a syntax error at the position yaml found it. Yaml only reports the line of syntax errors, so
+// they point at the first character of that line
+func yamlSyntaxError(file string, contents []byte, err error) error {
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return errorAt(Position{File: file, Line: 1, Column: 1}, "%s", err.Error())
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	lines := bytes.Split(contents, []byte{'\n'})
+	column := 1
+	if line >= 1 && line <= len(lines) {
+		column += len(lines[line-1]) - len(bytes.TrimLeft(lines[line-1], " \t"))
+	}
+
+	return errorAt(Position{File: file, Line: line, Column: column}, "%s", match[2])
+}
+
+// maxAliasDepth limits how deeply aliases can be nested, so that recursive aliases cannot expand forever
+const maxAliasDepth = 64
+
+// maxAliasedNodes limits how many nodes aliases can expand into across a whole document. Aliases can refer to values
+// that hold aliases themselves, so a small document could otherwise expand exponentially
+const maxAliasedNodes = 100_000
+
+// yamlConverter converts the nodes of a yaml document, counting the nodes that aliases expand into
+type yamlConverter struct {
+	file    string
+	aliased int // number of nodes created by expanding aliases so far
+}
+
+// convert converts a yaml node into a node. Aliases are expanded and merge keys ("<<") are applied
+func (converter *yamlConverter) convert(yamlNode *yaml.Node, aliasDepth int) (*node, error) {
+	file := converter.file
+	pos := Position{File: file, Line: yamlNode.Line, Column: yamlNode.Column}
+
+	if aliasDepth > 0 {
+		converter.aliased++
+		if converter.aliased > maxAliasedNodes {
+			return nil, errorAt(pos, "aliases expand into more than %d values", maxAliasedNodes)
+		}
+	}
+
+	switch yamlNode.Kind {
+	case yaml.AliasNode:
+		if aliasDepth >= maxAliasDepth {
+			return nil, errorAt(pos, "aliases are nested too deeply")
+		}
+
+		return converter.convert(yamlNode.Alias, aliasDepth+1)
+
+	case yaml.MappingNode:
+		object := &node{kind: nodeKind_OBJECT, pos: pos, fields: make(map[string]*node)}
+		var merged []*node
+		for idx := 0; idx+1 < len(yamlNode.Content); idx += 2 {
+			keyNode, valueNode := yamlNode.Content[idx], yamlNode.Content[idx+1]
+			if keyNode.Kind != yaml.ScalarNode {
+				return nil, errorAt(Position{File: file, Line: keyNode.Line, Column: keyNode.Column}, "keys must be scalars")
+			}
+
+			value, err := converter.convert(valueNode, aliasDepth)
+			if err != nil {
+				return nil, err
+			}
+
+			if keyNode.Tag == "!!merge" {
+				merged = append(merged, value)
+				continue
+			}
+
+			if _, exists := object.fields[keyNode.Value]; exists {
+				return nil, errorAt(Position{File: file, Line: keyNode.Line, Column: keyNode.Column}, "duplicate key '%s'", keyNode.Value)
+			}
+
+			object.keys = append(object.keys, keyNode.Value)
+			object.fields[keyNode.Value] = value
+		}
+
+		for _, mergedValue := range merged {
+			err := mergeYAML(object, mergedValue)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return object, nil
+
+	case yaml.SequenceNode:
+		array := &node{kind: nodeKind_ARRAY, pos: pos}
+		for _, itemNode := range yamlNode.Content {
+			item, err := converter.convert(itemNode, aliasDepth)
+			if err != nil {
+				return nil, err
+			}
+
+			array.items = append(array.items, item)
+		}
+
+		return array, nil
+
+	case yaml.ScalarNode:
+		var value any
+		err := yamlNode.Decode(&value)
+		if err != nil {
+			return nil, errorAt(pos, "invalid value: %w", err)
+		}
+
+		return &node{kind: nodeKind_SCALAR, pos: pos, scalar: value}, nil
+
+	default:
+		return nil, errorAt(pos, "unsupported yaml node")
+	}
+}
+
+// mergeYAML applies a merge key, adding every key of the merged mappings that the object does not set itself
+func mergeYAML(object *node, merged *node) error {
+	var sources []*node
+	switch merged.kind {
+	case nodeKind_OBJECT:
+		sources = []*node{merged}
+	case nodeKind_ARRAY:
+		sources = merged.items
+	}
+
+	if len(sources) == 0 {
+		return errorAt(merged.pos, "only mappings can be merged")
+	}
+
+	for _, source := range sources {
+		if source.kind != nodeKind_OBJECT {
+			return errorAt(source.pos, "only mappings can be merged")
+		}
+
+		for _, key := range source.keys {
+			if _, exists := object.fields[key]; !exists {
+				object.keys = append(object.keys, key)
+				object.fields[key] = source.fields[key]
+			}
+		}
+	}
+
+	return nil
+}
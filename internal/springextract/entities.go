@@ -0,0 +1,520 @@
+package springextract
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jdkTypes maps common JDK types onto the types Jackson writes them as
+var jdkTypes = map[string]types.DynamicType{
+	"String":         {TypeID: types.TypeID_STRING},
+	"CharSequence":   {TypeID: types.TypeID_STRING},
+	"char":           {TypeID: types.TypeID_STRING},
+	"Character":      {TypeID: types.TypeID_STRING},
+	"UUID":           {TypeID: types.TypeID_STRING},
+	"URI":            {TypeID: types.TypeID_STRING},
+	"URL":            {TypeID: types.TypeID_STRING},
+	"Locale":         {TypeID: types.TypeID_STRING},
+	"Currency":       {TypeID: types.TypeID_STRING},
+	"ZoneId":         {TypeID: types.TypeID_STRING},
+	"int":            {TypeID: types.TypeID_INTEGER},
+	"long":           {TypeID: types.TypeID_INTEGER},
+	"short":          {TypeID: types.TypeID_INTEGER},
+	"byte":           {TypeID: types.TypeID_INTEGER},
+	"Integer":        {TypeID: types.TypeID_INTEGER},
+	"Long":           {TypeID: types.TypeID_INTEGER},
+	"Short":          {TypeID: types.TypeID_INTEGER},
+	"Byte":           {TypeID: types.TypeID_INTEGER},
+	"BigInteger":     {TypeID: types.TypeID_INTEGER},
+	"AtomicInteger":  {TypeID: types.TypeID_INTEGER},
+	"AtomicLong":     {TypeID: types.TypeID_INTEGER},
+	"OptionalInt":    {TypeID: types.TypeID_INTEGER},
+	"OptionalLong":   {TypeID: types.TypeID_INTEGER},
+	"float":          {TypeID: types.TypeID_FLOAT},
+	"double":         {TypeID: types.TypeID_FLOAT},
+	"Float":          {TypeID: types.TypeID_FLOAT},
+	"Double":         {TypeID: types.TypeID_FLOAT},
+	"BigDecimal":     {TypeID: types.TypeID_FLOAT},
+	"Number":         {TypeID: types.TypeID_FLOAT},
+	"OptionalDouble": {TypeID: types.TypeID_FLOAT},
+	"boolean":        {TypeID: types.TypeID_BOOLEAN},
+	"Boolean":        {TypeID: types.TypeID_BOOLEAN},
+	"Instant":        {TypeID: types.TypeID_TIMESTAMP},
+	"OffsetDateTime": {TypeID: types.TypeID_TIMESTAMP},
+	"ZonedDateTime":  {TypeID: types.TypeID_TIMESTAMP},
+	"LocalDateTime":  {TypeID: types.TypeID_TIMESTAMP},
+	"Date":           {TypeID: types.TypeID_TIMESTAMP},
+	"Timestamp":      {TypeID: types.TypeID_TIMESTAMP},
+	"LocalDate":      {TypeID: types.TypeID_DATE},
+	"Object":         {TypeID: types.TypeID_ANY},
+	"JsonNode":       {TypeID: types.TypeID_ANY},
+	"ObjectNode":     {TypeID: types.TypeID_ANY},
+	"void":           {TypeID: types.TypeID_VOID},
+	"Void":           {TypeID: types.TypeID_VOID},
+}
+
+// jdkFormats are the string formats of JDK types
+var jdkFormats = map[string]string{
+	"UUID": types.Format_UUID,
+	"URI":  types.Format_URI,
+	"URL":  types.Format_URI,
+}
+
+// primitiveTypes can never be null, so properties of these types are always required
+var primitiveTypes = []string{"int", "long", "short", "byte", "char", "float", "double", "boolean"}
+
+// collectionTypes are written as arrays
+var collectionTypes = []string{"List", "ArrayList", "LinkedList", "Set", "HashSet", "LinkedHashSet", "TreeSet", "SortedSet", "Collection", "Iterable", "Stream"}
+
+// mapTypes are written as objects
+var mapTypes = []string{"Map", "HashMap", "LinkedHashMap", "TreeMap", "SortedMap", "ConcurrentMap", "ConcurrentHashMap", "MultiValueMap"}
+
+// requiredAnnotations mark properties that can never be null
+var requiredAnnotations = []string{"NotNull", "NonNull", "Nonnull", "NotBlank", "NotEmpty"}
+
+// typeInfo is what a Java type maps onto
+type typeInfo struct {
+	dtype       types.DynamicType
+	optional    bool                      // the type is an Optional, so values may be missing
+	constraints types.PropertyConstraints // constraints implied by the type, such as the values of an enum
+}
+
+// typeMapper maps Java types onto dynamic types, the same way Jackson would write them. Every class or record that is
+// mapped becomes an entity, so that the specification declares every type it references
+type typeMapper struct {
+	index   *typeIndex
+	classes map[string]*typeDecl // classes and records that become entities, by name
+	order   []string             // names of the classes, in the order they were first mapped
+}
+
+func newTypeMapper(index *typeIndex) *typeMapper {
+	return &typeMapper{
+		index:   index,
+		classes: make(map[string]*typeDecl),
+	}
+}
+
+// convert maps a type, as written within the given type, onto a dynamic type
+func (mapper *typeMapper) convert(ref typeRef, from *typeDecl) (typeInfo, error) {
+	if ref.dims > 0 {
+		elem := ref
+		elem.dims--
+		if elem.dims == 0 && (elem.name == "byte" || elem.name == "char") {
+			return typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_STRING}}, nil
+		}
+
+		return mapper.convertList(elem, from)
+	}
+
+	if ref.name == "?" || isTypeParam(ref.name, from) {
+		return typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_ANY}}, nil
+	}
+
+	if decl := mapper.index.resolve(ref.name, from); decl != nil {
+		return mapper.convertDecl(decl, ref, from)
+	}
+
+	name := ref.simpleName()
+	switch {
+	case name == "Optional":
+		if len(ref.args) != 1 {
+			return typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_ANY}, optional: true}, nil
+		}
+
+		info, err := mapper.convert(ref.args[0], from)
+		info.optional = true
+		return info, err
+
+	case slices.Contains(collectionTypes, name):
+		if len(ref.args) != 1 {
+			return typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}}, nil
+		}
+
+		return mapper.convertList(ref.args[0], from)
+
+	case slices.Contains(mapTypes, name):
+		valueType := types.DynamicType{TypeID: types.TypeID_ANY}
+		if len(ref.args) == 2 {
+			valueInfo, err := mapper.convert(ref.args[1], from)
+			if err != nil {
+				return typeInfo{}, fmt.Errorf("failed to map map value type '%s': %w", ref.args[1].name, err)
+			}
+			valueType = valueInfo.dtype
+		}
+
+		if name == "MultiValueMap" {
+			valueType = types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{valueType}}
+		}
+
+		return typeInfo{dtype: types.DynamicType{
+			TypeID:    types.TypeID_GENERIC,
+			Reference: "Record",
+			Inner:     []types.DynamicType{{TypeID: types.TypeID_STRING}, valueType},
+		}}, nil
+	}
+
+	if dtype, isJDKType := jdkTypes[name]; isJDKType {
+		info := typeInfo{dtype: dtype}
+		info.constraints.Format = jdkFormats[name]
+		return info, nil
+	}
+
+	return typeInfo{}, errorAt(ref.pos, "unknown type '%s'. Types must be declared in the sources or be common JDK types", ref.name)
+}
+
+// convertList maps arrays and collections of the given element type
+func (mapper *typeMapper) convertList(elem typeRef, from *typeDecl) (typeInfo, error) {
+	elemInfo, err := mapper.convert(elem, from)
+	if err != nil {
+		return typeInfo{}, fmt.Errorf("failed to map element type '%s': %w", elem.name, err)
+	}
+
+	return typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{elemInfo.dtype}}}, nil
+}
+
+// convertDecl maps a type, as written within the given type, that is declared by the sources. Enums are written as
+// strings, while classes and records become entities. Generic classes, such as Page<T>, become generic entities whose
+// values typed by their type parameters are typed any, and references to them carry their arguments
+func (mapper *typeMapper) convertDecl(decl *typeDecl, ref typeRef, from *typeDecl) (typeInfo, error) {
+	if decl.kind == "enum" {
+		info := typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_STRING}}
+		for _, constant := range decl.constants {
+			name := constant.name
+			if jsonProperty, exists := findAnnotation(constant.annotations, "JsonProperty"); exists {
+				if text, isString := stringElement(jsonProperty, "value"); isString {
+					name = text
+				}
+			}
+			info.constraints.Enum = append(info.constraints.Enum, name)
+		}
+
+		return info, nil
+	}
+
+	if len(ref.args) > 0 && len(ref.args) != len(decl.typeParams) {
+		return typeInfo{}, errorAt(ref.pos, "type '%s' takes %d type arguments, but is given %d", decl.name, len(decl.typeParams), len(ref.args))
+	}
+
+	if existing, exists := mapper.classes[decl.name]; exists && existing != decl {
+		return typeInfo{}, errorAt(ref.pos, "entity '%s' is declared by both %s and %s", decl.name, existing.pos, decl.pos)
+	}
+
+	if _, exists := mapper.classes[decl.name]; !exists {
+		mapper.classes[decl.name] = decl
+		mapper.order = append(mapper.order, decl.name)
+	}
+
+	// raw references to generic classes do not say what their values hold, so they are plain references
+	if len(ref.args) == 0 {
+		return typeInfo{dtype: types.DynamicType{TypeID: types.TypeID_USER, Reference: decl.name}}, nil
+	}
+
+	dtype := types.DynamicType{TypeID: types.TypeID_GENERIC, Reference: decl.name}
+	for _, arg := range ref.args {
+		argInfo, err := mapper.convert(arg, from)
+		if err != nil {
+			return typeInfo{}, fmt.Errorf("failed to map type argument '%s': %w", arg.name, err)
+		}
+
+		dtype.Inner = append(dtype.Inner, argInfo.dtype)
+	}
+
+	return typeInfo{dtype: dtype}, nil
+}
+
+// entities creates an entity for every class and record that was mapped. Mapping the properties of an entity may
+// reference further classes, which are added as they are found
+func (mapper *typeMapper) entities() ([]types.EntitySpec, error) {
+	var entities []types.EntitySpec
+	for idx := 0; idx < len(mapper.order); idx++ {
+		decl := mapper.classes[mapper.order[idx]]
+		entity := types.EntitySpec{
+			Documentation: documentation(decl.doc, decl.annotations),
+			Name:          decl.name,
+			Properties:    make(map[string]types.PropertySpec),
+		}
+
+		err := mapper.addProperties(entity.Properties, decl, make(map[*typeDecl]bool))
+		if err != nil {
+			return nil, fmt.Errorf("failed to map entity '%s': %w", entity.Name, err)
+		}
+
+		entities = append(entities, entity)
+	}
+
+	return entities, nil
+}
+
+// addProperties adds a property for every field or record component that Jackson would write, including those
+// inherited from superclasses declared by the sources
+func (mapper *typeMapper) addProperties(properties map[string]types.PropertySpec, decl *typeDecl, visited map[*typeDecl]bool) error {
+	if visited[decl] {
+		return nil
+	}
+	visited[decl] = true
+
+	if decl.kind == "class" {
+		for _, extended := range decl.extends {
+			if superclass := mapper.index.resolve(extended.name, decl); superclass != nil {
+				err := mapper.addProperties(properties, superclass, visited)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	_, paramDocs := javadoc(decl.doc)
+	fields := decl.components
+	if decl.kind != "record" {
+		fields = slices.DeleteFunc(slices.Clone(decl.fields), func(field variable) bool {
+			return slices.Contains(field.modifiers, "static") || slices.Contains(field.modifiers, "transient")
+		})
+	}
+
+	for _, field := range fields {
+		if ignore, exists := findAnnotation(field.annotations, "JsonIgnore"); exists && boolElement(ignore, "value", true) {
+			continue
+		}
+
+		name := field.name
+		jsonProperty, hasJSONProperty := findAnnotation(field.annotations, "JsonProperty")
+		if hasJSONProperty {
+			if text, isString := stringElement(jsonProperty, "value"); isString && len(text) > 0 {
+				name = text
+			}
+		}
+
+		info, err := mapper.convert(field.typ, decl)
+		if err != nil {
+			return fmt.Errorf("failed to map property '%s': %w", field.name, err)
+		}
+
+		property := types.PropertySpec{
+			Documentation:       documentation(field.doc, field.annotations),
+			PropertyConstraints: info.constraints,
+			Type:                info.dtype,
+			Required:            !info.optional && isRequired(field, jsonProperty, hasJSONProperty),
+		}
+		if len(property.Description) == 0 {
+			property.Description = paramDocs[field.name]
+		}
+
+		err = addConstraints(&property, field.annotations)
+		if err != nil {
+			return err
+		}
+
+		properties[name] = property
+	}
+
+	return nil
+}
+
+// isRequired checks if a property can never be missing or null
+func isRequired(field variable, jsonProperty annotation, hasJSONProperty bool) bool {
+	if field.typ.dims == 0 && slices.Contains(primitiveTypes, field.typ.name) {
+		return true
+	}
+
+	if hasJSONProperty && boolElement(jsonProperty, "required", false) {
+		return true
+	}
+
+	return slices.ContainsFunc(field.annotations, func(ann annotation) bool {
+		return slices.Contains(requiredAnnotations, ann.simpleName())
+	})
+}
+
+// addConstraints adds the constraints of Bean Validation annotations, such as @Size or @Pattern, to a property
+func addConstraints(property *types.PropertySpec, annotations []annotation) error {
+	isString := property.Type.TypeID == types.TypeID_STRING
+	isArray := property.Type.TypeID == types.TypeID_ARRAY
+
+	for _, ann := range annotations {
+		switch ann.simpleName() {
+		case "Min", "DecimalMin":
+			minimum, err := numberElement(ann, "value")
+			if err != nil {
+				return err
+			}
+			property.Minimum = minimum
+
+		case "Max", "DecimalMax":
+			maximum, err := numberElement(ann, "value")
+			if err != nil {
+				return err
+			}
+			property.Maximum = maximum
+
+		case "Size":
+			minimum, err := numberElement(ann, "min")
+			if err != nil {
+				return err
+			}
+
+			maximum, err := numberElement(ann, "max")
+			if err != nil {
+				return err
+			}
+
+			if isString {
+				setBound(&property.MinLength, minimum)
+				setBound(&property.MaxLength, maximum)
+			} else if isArray {
+				setBound(&property.MinItems, minimum)
+				setBound(&property.MaxItems, maximum)
+			}
+
+		case "NotBlank", "NotEmpty":
+			one := 1
+			if isString && property.MinLength == nil {
+				property.MinLength = &one
+			} else if isArray && property.MinItems == nil {
+				property.MinItems = &one
+			}
+
+		case "Pattern":
+			if text, isString := stringElement(ann, "regexp"); isString {
+				property.Pattern = text
+			}
+
+		case "Email":
+			property.Format = types.Format_EMAIL
+		}
+	}
+
+	return nil
+}
+
+// numberElement gets the value of a numeric annotation element, which may also be written as a string
+func numberElement(ann annotation, element string) (*float64, error) {
+	val, exists := ann.args[element]
+	if !exists {
+		return nil, nil
+	}
+
+	if val.kind != valueKind_NUMBER && val.kind != valueKind_STRING {
+		return nil, errorAt(val.pos, "expected a number")
+	}
+
+	text := strings.TrimRight(strings.ReplaceAll(val.text, "_", ""), "lLfFdD")
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, errorAt(val.pos, "invalid number '%s'", val.text)
+	}
+
+	return &number, nil
+}
+
+// setBound sets a length or item count bound, if it is given
+func setBound(bound **int, number *float64) {
+	if number != nil {
+		value := int(*number)
+		*bound = &value
+	}
+}
+
+// isTypeParam checks if a name refers to a type parameter of a type or of the types that enclose it
+func isTypeParam(name string, from *typeDecl) bool {
+	for scope := from; scope != nil; scope = scope.outer {
+		if slices.Contains(scope.typeParams, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMap checks if a type is written as an object with arbitrary keys
+func isMap(info typeInfo) bool {
+	return info.dtype.TypeID == types.TypeID_GENERIC && info.dtype.Reference == "Record"
+}
+
+// isSimple checks if values of a type can be written into a URL, such as strings, numbers, and lists of them
+func isSimple(dtype types.DynamicType) bool {
+	switch dtype.TypeID {
+	case types.TypeID_STRING, types.TypeID_INTEGER, types.TypeID_FLOAT, types.TypeID_BOOLEAN, types.TypeID_TIMESTAMP, types.TypeID_DATE:
+		return true
+	case types.TypeID_ARRAY:
+		return isSimple(dtype.ArrayElementTp())
+	default:
+		return false
+	}
+}
+
+var (
+	javadocInlineTag = regexp.MustCompile(`\{@\w+\s+([^}]*)}`)
+	javadocHTMLTag   = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+// javadoc gets the description of a Javadoc comment, along with the descriptions of its @param tags
+func javadoc(comment string) (string, map[string]string) {
+	params := make(map[string]string)
+	if len(comment) == 0 {
+		return "", params
+	}
+
+	comment = strings.TrimSuffix(strings.TrimPrefix(comment, "/**"), "*/")
+	var description []string
+	var currentParam string
+	inTags := false
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		line = javadocHTMLTag.ReplaceAllString(javadocInlineTag.ReplaceAllString(line, "$1"), "")
+
+		if strings.HasPrefix(line, "@") {
+			inTags = true
+			currentParam = ""
+			if rest, isParam := strings.CutPrefix(line, "@param "); isParam {
+				name, text, _ := strings.Cut(strings.TrimSpace(rest), " ")
+				currentParam = name
+				params[name] = strings.TrimSpace(text)
+			}
+			continue
+		}
+
+		if inTags {
+			if len(currentParam) > 0 && len(line) > 0 {
+				params[currentParam] = strings.TrimSpace(params[currentParam] + " " + line)
+			}
+			continue
+		}
+
+		description = append(description, line)
+	}
+
+	return strings.TrimSpace(strings.Join(description, "\n")), params
+}
+
+// documentation documents a declaration with its Javadoc comment. @Deprecated declarations are deprecated, and the
+// summary and description of an OpenAPI @Operation are used if they are given
+func documentation(comment string, annotations []annotation) types.Documentation {
+	description, _ := javadoc(comment)
+	doc := types.Documentation{Description: description}
+
+	if _, isDeprecated := findAnnotation(annotations, "Deprecated"); isDeprecated {
+		doc.Deprecated = true
+	}
+
+	if operation, exists := findAnnotation(annotations, "Operation"); exists {
+		if summary, hasSummary := stringElement(operation, "summary"); hasSummary {
+			doc.Summary = summary
+		}
+
+		if opDescription, hasDescription := stringElement(operation, "description"); hasDescription {
+			doc.Description = opDescription
+		}
+
+		if deprecated := boolElement(operation, "deprecated", false); deprecated {
+			doc.Deprecated = true
+		}
+	}
+
+	return doc
+}
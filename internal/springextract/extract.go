@@ -0,0 +1,696 @@
+// Package springextract derives API specifications from the sources of Spring controllers. Java sources are parsed
+// directly, so no JVM or build is needed: controllers become services, their handler methods become endpoints, and
+// the classes, records, and enums they exchange become entities.
+package springextract
+
+import (
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Options configures an extraction
+type Options struct {
+	Name    string // name of the API. Defaults to the name of the first source directory
+	Version string // version of the API
+	BaseURL string // base URL of the API
+}
+
+// mappingMethods maps the shortcut mapping annotations onto the HTTP methods they handle
+var mappingMethods = map[string]string{
+	"GetMapping":    "GET",
+	"PostMapping":   "POST",
+	"PutMapping":    "PUT",
+	"DeleteMapping": "DELETE",
+	"PatchMapping":  "PATCH",
+}
+
+// asyncWrappers are return types whose single type argument is the actual response body
+var asyncWrappers = []string{"ResponseEntity", "HttpEntity", "Optional", "Mono", "CompletableFuture", "CompletionStage", "Future", "Callable", "DeferredResult", "WebAsyncTask"}
+
+// Extract parses the Java sources under the given files and directories, and derives an API definition from the
+// Spring controllers they declare
+func Extract(roots []string, opts Options) (types.APIDefinition, error) {
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	index := newTypeIndex()
+	for _, root := range roots {
+		err := index.addSources(root)
+		if err != nil {
+			return types.APIDefinition{}, err
+		}
+	}
+
+	ext := &extractor{
+		index:  index,
+		mapper: newTypeMapper(index),
+	}
+
+	for _, unit := range index.units {
+		for _, decl := range unit.types {
+			err := ext.extractControllers(decl)
+			if err != nil {
+				return types.APIDefinition{}, err
+			}
+		}
+	}
+
+	if len(ext.services) == 0 {
+		return types.APIDefinition{}, fmt.Errorf("no controllers were found in %v", roots)
+	}
+
+	entities, err := ext.mapper.entities()
+	if err != nil {
+		return types.APIDefinition{}, err
+	}
+
+	apiDef := types.APIDefinition{
+		Name:     opts.Name,
+		Version:  opts.Version,
+		Entities: entities,
+		Services: ext.services,
+		Config: types.APIConfig{
+			BaseURL: opts.BaseURL,
+		},
+	}
+	if len(apiDef.Name) == 0 {
+		absRoot, _ := filepath.Abs(roots[0])
+		apiDef.Name = strings.TrimSuffix(filepath.Base(absRoot), ".java")
+	}
+
+	return apiDef, nil
+}
+
+// typeIndex finds the types declared by the parsed sources
+type typeIndex struct {
+	units []*compilationUnit
+	types map[string]*typeDecl // every declared type, by qualified name
+}
+
+func newTypeIndex() *typeIndex {
+	return &typeIndex{
+		types: make(map[string]*typeDecl),
+	}
+}
+
+// addSources parses a Java file, or every Java file within a directory. Hidden directories are skipped
+func (index *typeIndex) addSources(root string) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read sources: %w", err)
+		}
+
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != ".java" {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %w", path, err)
+		}
+
+		unit, err := parseFile(path, string(contents))
+		if err != nil {
+			return err
+		}
+
+		index.units = append(index.units, unit)
+		for _, decl := range unit.types {
+			index.add(decl, unit.pkg)
+		}
+
+		return nil
+	})
+}
+
+// add indexes a type and its member types
+func (index *typeIndex) add(decl *typeDecl, prefix string) {
+	qualifiedName := decl.name
+	if len(prefix) > 0 {
+		qualifiedName = prefix + "." + decl.name
+	}
+
+	index.types[qualifiedName] = decl
+	for _, nested := range decl.nested {
+		index.add(nested, qualifiedName)
+	}
+}
+
+// resolve finds the declaration of a type name, as written within the given type. Names are looked up the way Java
+// does: member types, types of the same file, single-type imports, the same package, and then on-demand imports.
+// Returns nil for types that are not declared by the sources, such as JDK types
+func (index *typeIndex) resolve(name string, from *typeDecl) *typeDecl {
+	first, rest, isQualified := strings.Cut(name, ".")
+	if isQualified {
+		if decl, exists := index.types[name]; exists {
+			return decl
+		}
+	}
+
+	decl := index.resolveSimple(first, from)
+	if decl == nil || !isQualified {
+		return decl
+	}
+
+	for _, segment := range strings.Split(rest, ".") {
+		idx := slices.IndexFunc(decl.nested, func(nested *typeDecl) bool { return nested.name == segment })
+		if idx < 0 {
+			return nil
+		}
+		decl = decl.nested[idx]
+	}
+
+	return decl
+}
+
+func (index *typeIndex) resolveSimple(name string, from *typeDecl) *typeDecl {
+	for scope := from; scope != nil; scope = scope.outer {
+		if scope.name == name {
+			return scope
+		}
+
+		for _, nested := range scope.nested {
+			if nested.name == name {
+				return nested
+			}
+		}
+	}
+
+	unit := from.unit
+	for _, decl := range unit.types {
+		if decl.name == name {
+			return decl
+		}
+	}
+
+	for _, imported := range unit.imports {
+		if strings.HasSuffix(imported, "."+name) {
+			if decl, exists := index.types[imported]; exists {
+				return decl
+			}
+		}
+	}
+
+	qualifiedName := name
+	if len(unit.pkg) > 0 {
+		qualifiedName = unit.pkg + "." + name
+	}
+	if decl, exists := index.types[qualifiedName]; exists {
+		return decl
+	}
+
+	for _, imported := range unit.imports {
+		if prefix, isOnDemand := strings.CutSuffix(imported, ".*"); isOnDemand {
+			if decl, exists := index.types[prefix+"."+name]; exists {
+				return decl
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveString evaluates a constant string expression, such as a path built from constants
+func (index *typeIndex) resolveString(val value, from *typeDecl) (string, error) {
+	switch val.kind {
+	case valueKind_STRING:
+		return val.text, nil
+
+	case valueKind_CONCAT:
+		var builder strings.Builder
+		for _, part := range val.items {
+			str, err := index.resolveString(part, from)
+			if err != nil {
+				return "", err
+			}
+			builder.WriteString(str)
+		}
+
+		return builder.String(), nil
+
+	case valueKind_NAME:
+		field, declaredBy := index.resolveConstant(val.text, from)
+		if field == nil || field.init == nil {
+			return "", errorAt(val.pos, "cannot resolve the constant '%s'", val.text)
+		}
+
+		return index.resolveString(*field.init, declaredBy)
+
+	default:
+		return "", errorAt(val.pos, "expected a constant string")
+	}
+}
+
+// resolveConstant finds the field a constant name refers to, along with the type that declares it
+func (index *typeIndex) resolveConstant(name string, from *typeDecl) (*variable, *typeDecl) {
+	typeName, fieldName := "", name
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		typeName, fieldName = name[:idx], name[idx+1:]
+	}
+
+	var scopes []*typeDecl
+	if len(typeName) > 0 {
+		scopes = append(scopes, index.resolve(typeName, from))
+	} else {
+		for scope := from; scope != nil; scope = scope.outer {
+			scopes = append(scopes, scope)
+		}
+
+		// static imports, such as "com.acme.Paths.PEOPLE" or "com.acme.Paths.*"
+		for _, imported := range from.unit.imports {
+			if prefix, isImported := strings.CutSuffix(imported, "."+fieldName); isImported {
+				scopes = append(scopes, index.types[prefix])
+			} else if prefix, isOnDemand := strings.CutSuffix(imported, ".*"); isOnDemand {
+				scopes = append(scopes, index.types[prefix])
+			}
+		}
+	}
+
+	for _, scope := range scopes {
+		if scope == nil {
+			continue
+		}
+
+		for idx := range scope.fields {
+			if scope.fields[idx].name == fieldName {
+				return &scope.fields[idx], scope
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// extractor turns controllers into services
+type extractor struct {
+	index    *typeIndex
+	mapper   *typeMapper
+	services []types.ServiceDefinition
+}
+
+// extractControllers adds a service for a type if it is a controller, and then for each of its member types
+func (ext *extractor) extractControllers(decl *typeDecl) error {
+	if isController(decl) {
+		err := ext.extractController(decl)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, nested := range decl.nested {
+		err := ext.extractControllers(nested)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isController checks if a type is a REST controller, whose handler methods write their results as response bodies
+func isController(decl *typeDecl) bool {
+	if decl.kind != "class" {
+		return false
+	}
+
+	_, isRestController := findAnnotation(decl.annotations, "RestController")
+	_, isController := findAnnotation(decl.annotations, "Controller")
+	_, isResponseBody := findAnnotation(decl.annotations, "ResponseBody")
+	return isRestController || (isController && isResponseBody)
+}
+
+// extractController creates a service from a controller, with an endpoint for each handler method
+func (ext *extractor) extractController(decl *typeDecl) error {
+	basePath := ""
+	if mapping, exists := findAnnotation(decl.annotations, "RequestMapping"); exists {
+		var err error
+		basePath, err = ext.mappingPath(mapping, decl.name, decl)
+		if err != nil {
+			return err
+		}
+	}
+
+	name := strings.TrimSuffix(decl.name, "Controller")
+	if len(name) == 0 {
+		name = decl.name
+	}
+
+	service := types.ServiceDefinition{
+		Documentation: documentation(decl.doc, decl.annotations),
+		Name:          name,
+	}
+
+	for _, handler := range decl.methods {
+		endpoint, isHandler, err := ext.extractEndpoint(handler, decl, basePath)
+		if err != nil {
+			return err
+		}
+
+		if isHandler {
+			service.Endpoints = append(service.Endpoints, endpoint)
+		}
+	}
+
+	if len(service.Endpoints) > 0 {
+		ext.services = append(ext.services, service)
+	}
+
+	return nil
+}
+
+// extractEndpoint creates an endpoint from a handler method. Returns false if the method does not handle requests
+func (ext *extractor) extractEndpoint(handler method, controller *typeDecl, basePath string) (types.APIEndpoint, bool, error) {
+	method, mapping, isHandler, err := ext.handlerMapping(handler, controller)
+	if err != nil || !isHandler {
+		return types.APIEndpoint{}, false, err
+	}
+
+	path, err := ext.mappingPath(mapping, handler.name, controller)
+	if err != nil {
+		return types.APIEndpoint{}, false, err
+	}
+
+	uri, pathVariables, err := convertPath(joinPaths(basePath, path), mapping.pos)
+	if err != nil {
+		return types.APIEndpoint{}, false, err
+	}
+
+	endpoint := types.APIEndpoint{
+		Documentation:  documentation(handler.doc, handler.annotations),
+		Name:           handler.name,
+		Endpoint:       uri,
+		Method:         method,
+		PathVariables:  make(map[string]types.RequestValue),
+		QueryVariables: make(map[string]types.RequestValue),
+		RequestBody:    types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_VOID}},
+	}
+
+	for _, param := range handler.params {
+		err = ext.addParam(&endpoint, param, controller, pathVariables)
+		if err != nil {
+			return types.APIEndpoint{}, false, err
+		}
+	}
+
+	// path variables that no parameter declares, such as those bound to a map, are strings
+	for _, variable := range pathVariables {
+		if _, exists := endpoint.PathVariables[variable]; !exists {
+			endpoint.PathVariables[variable] = types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true}
+		}
+	}
+
+	endpoint.ResponseBody, err = ext.responseBody(handler.returns, controller)
+	if err != nil {
+		return types.APIEndpoint{}, false, fmt.Errorf("failed to map the response of '%s': %w", handler.name, err)
+	}
+
+	return endpoint, true, nil
+}
+
+// handlerMapping finds the mapping annotation of a handler method, along with the HTTP method it handles
+func (ext *extractor) handlerMapping(handler method, controller *typeDecl) (string, annotation, bool, error) {
+	for _, ann := range handler.annotations {
+		if method, isShortcut := mappingMethods[ann.simpleName()]; isShortcut {
+			return method, ann, true, nil
+		}
+
+		if ann.simpleName() != "RequestMapping" {
+			continue
+		}
+
+		methods := elementValues(ann, "method")
+		if len(methods) != 1 || methods[0].kind != valueKind_NAME {
+			return "", annotation{}, false, errorAt(ann.pos, "@RequestMapping of '%s' must set exactly one method, such as 'method = RequestMethod.GET'", handler.name)
+		}
+
+		name := methods[0].text
+		return name[strings.LastIndex(name, ".")+1:], ann, true, nil
+	}
+
+	return "", annotation{}, false, nil
+}
+
+// mappingPath gets the path of the mapping annotation of the named controller or handler. Endpoints only have a single
+// path, so mappings that set several paths are rejected rather than dropping all but one of them
+func (ext *extractor) mappingPath(mapping annotation, owner string, from *typeDecl) (string, error) {
+	paths := elementValues(mapping, "value")
+	if len(paths) == 0 {
+		paths = elementValues(mapping, "path")
+	}
+
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	if len(paths) > 1 {
+		return "", errorAt(mapping.pos, "@%s of '%s' must set exactly one path, but sets %d", mapping.simpleName(), owner, len(paths))
+	}
+
+	return ext.index.resolveString(paths[0], from)
+}
+
+// addParam adds a handler parameter to an endpoint as a path variable, query variable, or request body. Parameters
+// that are provided by the framework, such as principals or servlet requests, are skipped
+func (ext *extractor) addParam(endpoint *types.APIEndpoint, param variable, controller *typeDecl, pathVariables []string) error {
+	if ann, exists := findAnnotation(param.annotations, "PathVariable"); exists {
+		info, err := ext.mapper.convert(param.typ, controller)
+		if err != nil {
+			return fmt.Errorf("failed to map path variable '%s': %w", param.name, err)
+		}
+
+		if isMap(info) {
+			return nil
+		}
+
+		name, err := ext.paramName(ann, param, controller)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(pathVariables, name) {
+			return errorAt(param.pos, "path '%s' has no variable '%s'", endpoint.Endpoint, name)
+		}
+
+		endpoint.PathVariables[name] = types.RequestValue{Type: info.dtype, Required: true}
+		return nil
+	}
+
+	if ann, exists := findAnnotation(param.annotations, "RequestParam"); exists {
+		info, err := ext.mapper.convert(param.typ, controller)
+		if err != nil {
+			return fmt.Errorf("failed to map query variable '%s': %w", param.name, err)
+		}
+
+		if isMap(info) {
+			return nil
+		}
+
+		name, err := ext.paramName(ann, param, controller)
+		if err != nil {
+			return err
+		}
+
+		_, hasDefault := ann.args["defaultValue"]
+		required := !info.optional && !hasDefault && boolElement(ann, "required", true)
+		endpoint.QueryVariables[name] = types.RequestValue{Type: info.dtype, Required: required}
+		return nil
+	}
+
+	if ann, exists := findAnnotation(param.annotations, "RequestBody"); exists {
+		info, err := ext.mapper.convert(param.typ, controller)
+		if err != nil {
+			return fmt.Errorf("failed to map the request body of '%s': %w", endpoint.Name, err)
+		}
+
+		endpoint.RequestBody = types.RequestValue{Type: info.dtype, Required: !info.optional && boolElement(ann, "required", true)}
+		return nil
+	}
+
+	if len(param.annotations) > 0 && !onlyValidation(param.annotations) {
+		// bound to something other than the request, such as a header or the principal
+		return nil
+	}
+
+	// like Spring, parameters of simple types without annotations are optional query variables
+	info, err := ext.mapper.convert(param.typ, controller)
+	if err != nil || !isSimple(info.dtype) {
+		return nil
+	}
+
+	endpoint.QueryVariables[param.name] = types.RequestValue{Type: info.dtype}
+	return nil
+}
+
+// paramName gets the name a parameter is bound to, which defaults to the name of the parameter
+func (ext *extractor) paramName(ann annotation, param variable, from *typeDecl) (string, error) {
+	for _, element := range []string{"value", "name"} {
+		if val, exists := ann.args[element]; exists {
+			return ext.index.resolveString(val, from)
+		}
+	}
+
+	return param.name, nil
+}
+
+// responseBody maps the return type of a handler, unwrapping types such as ResponseEntity<T> or Mono<T>
+func (ext *extractor) responseBody(returns typeRef, controller *typeDecl) (types.RequestValue, error) {
+	for returns.dims == 0 && slices.Contains(asyncWrappers, returns.simpleName()) {
+		if len(returns.args) != 1 || returns.args[0].name == "?" {
+			return types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_ANY}, Required: true}, nil
+		}
+
+		returns = returns.args[0]
+	}
+
+	if returns.dims == 0 && returns.simpleName() == "Flux" && len(returns.args) == 1 {
+		returns = typeRef{name: returns.args[0].name, args: returns.args[0].args, dims: returns.args[0].dims + 1, pos: returns.pos}
+	}
+
+	info, err := ext.mapper.convert(returns, controller)
+	if err != nil {
+		return types.RequestValue{}, err
+	}
+
+	return types.RequestValue{Type: info.dtype, Required: !info.dtype.IsVoid()}, nil
+}
+
+// pathVariablePattern matches the start of a path variable
+var pathVariablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+
+// convertPath converts a Spring path, such as "/people/{id:[0-9]+}", into an endpoint such as "/people/{{id}}".
+// Returns the names of the path variables
+func convertPath(path string, pos position) (string, []string, error) {
+	var builder strings.Builder
+	var variables []string
+	for idx := 0; idx < len(path); idx++ {
+		if path[idx] != '{' {
+			builder.WriteByte(path[idx])
+			continue
+		}
+
+		// skip over the variable, along with any pattern that may contain braces itself
+		depth, end := 0, idx
+		for ; end < len(path); end++ {
+			if path[end] == '{' {
+				depth++
+			} else if path[end] == '}' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+
+		if end >= len(path) {
+			return "", nil, errorAt(pos, "path '%s' has a variable that is never closed", path)
+		}
+
+		name := pathVariablePattern.FindString(strings.TrimPrefix(path[idx+1:end], "*"))
+		if len(name) == 0 {
+			return "", nil, errorAt(pos, "path '%s' has a variable without a name", path)
+		}
+
+		variables = append(variables, name)
+		builder.WriteString("{{" + name + "}}")
+		idx = end
+	}
+
+	return builder.String(), variables, nil
+}
+
+// joinPaths joins the path of a controller with the path of one of its handlers
+func joinPaths(basePath, path string) string {
+	joined := strings.TrimSuffix(basePath, "/")
+	if len(path) > 0 {
+		joined += "/" + strings.TrimPrefix(path, "/")
+	}
+
+	if !strings.HasPrefix(joined, "/") {
+		joined = "/" + joined
+	}
+
+	return joined
+}
+
+// findAnnotation finds an annotation by its simple name
+func findAnnotation(annotations []annotation, name string) (annotation, bool) {
+	idx := slices.IndexFunc(annotations, func(ann annotation) bool { return ann.simpleName() == name })
+	if idx < 0 {
+		return annotation{}, false
+	}
+
+	return annotations[idx], true
+}
+
+// elementValues gets the values of an annotation element, unwrapping arrays
+func elementValues(ann annotation, element string) []value {
+	val, exists := ann.args[element]
+	if !exists {
+		return nil
+	}
+
+	if val.kind == valueKind_ARRAY {
+		return val.items
+	}
+
+	return []value{val}
+}
+
+// stringElement gets the value of an annotation element that is a string literal, or a concatenation of them
+func stringElement(ann annotation, element string) (string, bool) {
+	val, exists := ann.args[element]
+	if !exists {
+		return "", false
+	}
+
+	parts := []value{val}
+	if val.kind == valueKind_CONCAT {
+		parts = val.items
+	}
+
+	var builder strings.Builder
+	for _, part := range parts {
+		if part.kind != valueKind_STRING {
+			return "", false
+		}
+		builder.WriteString(part.text)
+	}
+
+	return builder.String(), true
+}
+
+// boolElement gets the value of a boolean annotation element
+func boolElement(ann annotation, element string, defaultValue bool) bool {
+	val, exists := ann.args[element]
+	if !exists || val.kind != valueKind_BOOL {
+		return defaultValue
+	}
+
+	return val.text == "true"
+}
+
+// onlyValidation checks if every annotation of a parameter only validates it, rather than binding it
+func onlyValidation(annotations []annotation) bool {
+	for _, ann := range annotations {
+		if !slices.Contains([]string{"Valid", "Validated", "NotNull", "NonNull", "Nullable"}, ann.simpleName()) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,224 @@
+package springextract
+
+import (
+	"github.com/softwaresale/client-gen/v2/internal/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtract_DerivesServicesFromControllers(t *testing.T) {
+	apiDef, err := Extract([]string{filepath.Join("testdata", "people")}, Options{BaseURL: "http://localhost"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "people", apiDef.Name)
+	assert.Equal(t, "http://localhost", apiDef.Config.BaseURL)
+	assert.Len(t, apiDef.Services, 1)
+
+	people := apiDef.Services[0]
+	assert.Equal(t, "People", people.Name)
+	assert.Equal(t, "Manages the people we know.", people.Description)
+	assert.Len(t, people.Endpoints, 5)
+
+	listPeople := people.Endpoints[0]
+	assert.Equal(t, "listPeople", listPeople.Name)
+	assert.Equal(t, "GET", listPeople.Method)
+	assert.Equal(t, "/api/v1/people", listPeople.Endpoint)
+	assert.Equal(t, "Lists people.", listPeople.Description)
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_INTEGER}}, listPeople.QueryVariables["limit"])
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_STRING}}, listPeople.QueryVariables["q"])
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_STRING}, Required: true}, listPeople.QueryVariables["status"])
+	assert.Len(t, listPeople.QueryVariables, 3)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}}}, listPeople.ResponseBody.Type)
+
+	getPerson := people.Endpoints[1]
+	assert.Equal(t, "/api/v1/people/{{id}}", getPerson.Endpoint)
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_INTEGER}, Required: true}, getPerson.PathVariables["id"])
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, getPerson.ResponseBody.Type)
+
+	createPerson := people.Endpoints[2]
+	assert.Equal(t, "POST", createPerson.Method)
+	assert.Equal(t, "Create a person", createPerson.Summary)
+	assert.Equal(t, "Creates a person and returns it", createPerson.Description)
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Person"}, Required: true}, createPerson.RequestBody)
+
+	updateAddress := people.Endpoints[3]
+	assert.Equal(t, "PUT", updateAddress.Method)
+	assert.Equal(t, "/api/v1/people/{{id}}/address", updateAddress.Endpoint)
+	assert.True(t, updateAddress.Deprecated)
+	assert.Equal(t, types.RequestValue{Type: types.DynamicType{TypeID: types.TypeID_USER, Reference: "Address"}}, updateAddress.RequestBody)
+	assert.True(t, updateAddress.ResponseBody.Type.IsVoid())
+
+	deletePerson := people.Endpoints[4]
+	assert.Equal(t, "DELETE", deletePerson.Method)
+	assert.True(t, deletePerson.ResponseBody.Type.IsVoid())
+	assert.Equal(t, map[string]types.RequestValue{"dryRun": {Type: types.DynamicType{TypeID: types.TypeID_BOOLEAN}}}, deletePerson.QueryVariables)
+}
+
+func TestExtract_DerivesEntitiesFromReferencedTypes(t *testing.T) {
+	apiDef, err := Extract([]string{filepath.Join("testdata", "people")}, Options{})
+	assert.NoError(t, err)
+
+	assert.Len(t, apiDef.Entities, 3)
+	person, address, settings := apiDef.Entities[0], apiDef.Entities[1], apiDef.Entities[2]
+	assert.Equal(t, "Person", person.Name)
+	assert.Equal(t, "Somebody we know", person.Description)
+	assert.Equal(t, "Address", address.Name)
+	assert.Equal(t, "Settings", settings.Name)
+
+	expectedTypes := map[string]string{
+		"id":        types.TypeID_STRING,
+		"name":      types.TypeID_STRING,
+		"nick_name": types.TypeID_STRING,
+		"email":     types.TypeID_STRING,
+		"age":       types.TypeID_INTEGER,
+		"birthday":  types.TypeID_DATE,
+		"address":   types.TypeID_USER,
+		"friends":   types.TypeID_ARRAY,
+		"tags":      types.TypeID_GENERIC,
+		"status":    types.TypeID_STRING,
+		"avatar":    types.TypeID_STRING,
+		"settings":  types.TypeID_USER,
+		"createdAt": types.TypeID_TIMESTAMP,
+	}
+	assert.Len(t, person.Properties, len(expectedTypes))
+	for name, typeID := range expectedTypes {
+		assert.Equal(t, typeID, person.Properties[name].Type.TypeID, name)
+	}
+
+	name := person.Properties["name"]
+	assert.True(t, name.Required)
+	assert.Equal(t, "Full name", name.Description)
+	assert.Equal(t, 1, *name.MinLength)
+	assert.Equal(t, 100, *name.MaxLength)
+
+	age := person.Properties["age"]
+	assert.True(t, age.Required)
+	assert.Equal(t, 0.0, *age.Minimum)
+	assert.Equal(t, 150.0, *age.Maximum)
+
+	assert.False(t, person.Properties["nick_name"].Required)
+	assert.Equal(t, types.Format_UUID, person.Properties["id"].Format)
+	assert.Equal(t, types.Format_EMAIL, person.Properties["email"].Format)
+	assert.Equal(t, []any{"active", "INACTIVE"}, person.Properties["status"].Enum)
+	assert.Equal(t, 1, *person.Properties["friends"].MinItems)
+	assert.Equal(t, "When the record was created", person.Properties["createdAt"].Description)
+
+	assert.Equal(t, types.PropertySpec{
+		Documentation: types.Documentation{Description: "the street and number"},
+		Type:          types.DynamicType{TypeID: types.TypeID_STRING},
+		Required:      true,
+	}, address.Properties["street"])
+	assert.False(t, address.Properties["city"].Required)
+}
+
+func TestExtract_ReportsInvalidSources(t *testing.T) {
+	tests := map[string]struct {
+		source   string
+		expected string
+	}{
+		"syntax error": {
+			source:   "@RestController\npublic class PeopleController {\n  @GetMapping\n  public String get( {}\n}\n",
+			expected: "PeopleController.java:4:22: expected a name, found '{'",
+		},
+		"unknown type": {
+			source:   "@RestController\npublic class PeopleController {\n  @GetMapping\n  public Person get() { return null; }\n}\n",
+			expected: "PeopleController.java:4:10: unknown type 'Person'",
+		},
+		"unknown path variable": {
+			source:   "@RestController\npublic class PeopleController {\n  @GetMapping(\"/people\")\n  public void get(@PathVariable long id) {}\n}\n",
+			expected: "PeopleController.java:4:38: path '/people' has no variable 'id'",
+		},
+		"controller mapping with several paths": {
+			source:   "@RestController\n@RequestMapping({\"/people\", \"/persons\"})\npublic class PeopleController {\n  @GetMapping\n  public void get() {}\n}\n",
+			expected: "PeopleController.java:2:1: @RequestMapping of 'PeopleController' must set exactly one path, but sets 2",
+		},
+		"handler mapping with several paths": {
+			source:   "@RestController\npublic class PeopleController {\n  @GetMapping(path = {\"/people\", \"/persons\"})\n  public void get() {}\n}\n",
+			expected: "PeopleController.java:3:3: @GetMapping of 'get' must set exactly one path, but sets 2",
+		},
+		"request mapping without a method": {
+			source:   "@RestController\npublic class PeopleController {\n  @RequestMapping(\"/people\")\n  public void get() {}\n}\n",
+			expected: "PeopleController.java:3:3: @RequestMapping of 'get' must set exactly one method",
+		},
+		"unresolved constant": {
+			source:   "@RestController\npublic class PeopleController {\n  @GetMapping(Paths.PEOPLE)\n  public void get() {}\n}\n",
+			expected: "PeopleController.java:3:15: cannot resolve the constant 'Paths.PEOPLE'",
+		},
+		"wrong number of type arguments": {
+			source:   "class Page<T> {}\n@RestController\npublic class PeopleController {\n  @GetMapping\n  public Page<String, String> get() { return null; }\n}\n",
+			expected: "PeopleController.java:5:10: type 'Page' takes 1 type arguments, but is given 2",
+		},
+		"no controllers": {
+			source:   "public class PeopleController {\n}\n",
+			expected: "no controllers were found",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, "PeopleController.java"), []byte(test.source), 0644))
+
+			_, err := Extract([]string{dir}, Options{})
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		expected  string
+		variables []string
+	}{
+		"plain":         {path: "/people", expected: "/people"},
+		"variable":      {path: "/people/{id}", expected: "/people/{{id}}", variables: []string{"id"}},
+		"pattern":       {path: "/people/{id:\\d{3}}/{name}", expected: "/people/{{id}}/{{name}}", variables: []string{"id", "name"}},
+		"capture rest":  {path: "/files/{*path}", expected: "/files/{{path}}", variables: []string{"path"}},
+		"no variables":  {path: "", expected: ""},
+		"text and vars": {path: "/v{version}/items", expected: "/v{{version}}/items", variables: []string{"version"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			uri, variables, err := convertPath(test.path, position{})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, uri)
+			assert.Equal(t, test.variables, variables)
+		})
+	}
+}
+
+func TestExtract_MapsGenericClassesToGenericEntities(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"Page.java":   "public class Page<T> {\n  private List<T> content;\n  private int totalPages;\n}\n",
+		"Person.java": "public record Person(@NotNull String name) {}\n",
+		"PeopleController.java": "@RestController\n@RequestMapping(\"/people\")\npublic class PeopleController {\n" +
+			"  @GetMapping\n  public ResponseEntity<Page<Person>> list() { return null; }\n" +
+			"  @GetMapping(\"/raw\")\n  public Page raw() { return null; }\n}\n",
+	}
+	for name, source := range sources {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(source), 0644))
+	}
+
+	apiDef, err := Extract([]string{dir}, Options{})
+	assert.NoError(t, err)
+
+	endpoints := apiDef.Services[0].Endpoints
+	assert.Equal(t, types.DynamicType{
+		TypeID:    types.TypeID_GENERIC,
+		Reference: "Page",
+		Inner:     []types.DynamicType{{TypeID: types.TypeID_USER, Reference: "Person"}},
+	}, endpoints[0].ResponseBody.Type)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_USER, Reference: "Page"}, endpoints[1].ResponseBody.Type)
+
+	assert.Len(t, apiDef.Entities, 2)
+	page := apiDef.Entities[0]
+	assert.Equal(t, "Page", page.Name)
+	assert.Equal(t, types.DynamicType{TypeID: types.TypeID_ARRAY, Inner: []types.DynamicType{{TypeID: types.TypeID_ANY}}}, page.Properties["content"].Type)
+	assert.Equal(t, types.TypeID_INTEGER, page.Properties["totalPages"].Type.TypeID)
+	assert.Equal(t, "Person", apiDef.Entities[1].Name)
+}
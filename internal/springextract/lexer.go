@@ -0,0 +1,321 @@
+package springextract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenKind_EOF    tokenKind = iota
+	tokenKind_IDENT            // identifiers and keywords
+	tokenKind_STRING           // string literals and text blocks, with escapes decoded
+	tokenKind_CHAR             // character literals
+	tokenKind_NUMBER           // numeric literals, as written
+	tokenKind_PUNCT            // a single punctuation character. Operators are split into their characters
+)
+
+// position is a place within a source file
+type position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (pos position) String() string {
+	return fmt.Sprintf("%s:%d:%d", pos.File, pos.Line, pos.Column)
+}
+
+// token is a lexical token of a Java source file
+type token struct {
+	kind tokenKind
+	text string   // the token as written, or the decoded value of a string literal
+	pos  position // where the token starts
+	doc  string   // the Javadoc comment written directly before the token, if any
+}
+
+// errorAt creates an error that points at a position in a source file
+func errorAt(pos position, format string, args ...any) error {
+	return fmt.Errorf("%s: %w", pos, fmt.Errorf(format, args...))
+}
+
+// lexer splits Java source code into tokens. Comments are dropped, except for Javadoc comments, which are attached to
+// the token that follows them
+type lexer struct {
+	file   string
+	src    string
+	offset int
+	line   int
+	column int
+}
+
+// tokenize splits a Java source file into tokens, ending with an EOF token
+func tokenize(file, src string) ([]token, error) {
+	lex := &lexer{file: file, src: src, line: 1, column: 1}
+
+	var tokens []token
+	doc := ""
+	for {
+		lex.skipSpace()
+		if lex.offset >= len(lex.src) {
+			tokens = append(tokens, token{kind: tokenKind_EOF, pos: lex.pos(), doc: doc})
+			return tokens, nil
+		}
+
+		pos := lex.pos()
+		switch {
+		case strings.HasPrefix(lex.src[lex.offset:], "//"):
+			for lex.offset < len(lex.src) && lex.src[lex.offset] != '\n' {
+				lex.advance()
+			}
+
+		case strings.HasPrefix(lex.src[lex.offset:], "/*"):
+			end := strings.Index(lex.src[lex.offset+2:], "*/")
+			if end < 0 {
+				return nil, errorAt(pos, "comment is never closed")
+			}
+
+			comment := lex.src[lex.offset : lex.offset+end+4]
+			lex.advanceBy(len(comment))
+			if strings.HasPrefix(comment, "/**") && comment != "/**/" {
+				doc = comment
+			}
+
+		case lex.src[lex.offset] == '"':
+			value, err := lex.lexString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenKind_STRING, text: value, pos: pos, doc: doc})
+			doc = ""
+
+		case lex.src[lex.offset] == '\'':
+			value, err := lex.lexChar()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenKind_CHAR, text: value, pos: pos, doc: doc})
+			doc = ""
+
+		case isDigit(lex.src[lex.offset]):
+			tokens = append(tokens, token{kind: tokenKind_NUMBER, text: lex.lexNumber(), pos: pos, doc: doc})
+			doc = ""
+
+		case isIdentStart(lex.peekRune()):
+			start := lex.offset
+			for lex.offset < len(lex.src) && isIdentPart(lex.peekRune()) {
+				lex.advance()
+			}
+			tokens = append(tokens, token{kind: tokenKind_IDENT, text: lex.src[start:lex.offset], pos: pos, doc: doc})
+			doc = ""
+
+		default:
+			start := lex.offset
+			lex.advance()
+			tokens = append(tokens, token{kind: tokenKind_PUNCT, text: lex.src[start:lex.offset], pos: pos, doc: doc})
+			doc = ""
+		}
+	}
+}
+
+func (lex *lexer) pos() position {
+	return position{File: lex.file, Line: lex.line, Column: lex.column}
+}
+
+func (lex *lexer) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(lex.src[lex.offset:])
+	return r
+}
+
+// advance moves past the current character, keeping track of lines and columns
+func (lex *lexer) advance() {
+	r, size := utf8.DecodeRuneInString(lex.src[lex.offset:])
+	lex.offset += size
+	if r == '\n' {
+		lex.line++
+		lex.column = 1
+	} else {
+		lex.column++
+	}
+}
+
+func (lex *lexer) advanceBy(size int) {
+	end := lex.offset + size
+	for lex.offset < end {
+		lex.advance()
+	}
+}
+
+func (lex *lexer) skipSpace() {
+	for lex.offset < len(lex.src) && unicode.IsSpace(lex.peekRune()) {
+		lex.advance()
+	}
+}
+
+// lexString reads a string literal or a text block, decoding its escapes
+func (lex *lexer) lexString() (string, error) {
+	pos := lex.pos()
+	if strings.HasPrefix(lex.src[lex.offset:], `"""`) {
+		end := strings.Index(lex.src[lex.offset+3:], `"""`)
+		if end < 0 {
+			return "", errorAt(pos, "text block is never closed")
+		}
+
+		raw := lex.src[lex.offset+3 : lex.offset+3+end]
+		lex.advanceBy(end + 6)
+		return unescape(textBlock(raw)), nil
+	}
+
+	lex.advance()
+	start := lex.offset
+	for lex.offset < len(lex.src) && lex.src[lex.offset] != '"' {
+		if lex.src[lex.offset] == '\n' {
+			return "", errorAt(pos, "string literal is never closed")
+		}
+
+		if lex.src[lex.offset] == '\\' {
+			lex.advance()
+		}
+		lex.advance()
+	}
+
+	if lex.offset >= len(lex.src) {
+		return "", errorAt(pos, "string literal is never closed")
+	}
+
+	raw := lex.src[start:lex.offset]
+	lex.advance()
+	return unescape(raw), nil
+}
+
+// lexChar reads a character literal
+func (lex *lexer) lexChar() (string, error) {
+	pos := lex.pos()
+	lex.advance()
+	start := lex.offset
+	for lex.offset < len(lex.src) && lex.src[lex.offset] != '\'' && lex.src[lex.offset] != '\n' {
+		if lex.src[lex.offset] == '\\' {
+			lex.advance()
+		}
+		lex.advance()
+	}
+
+	if lex.offset >= len(lex.src) || lex.src[lex.offset] != '\'' {
+		return "", errorAt(pos, "character literal is never closed")
+	}
+
+	raw := lex.src[start:lex.offset]
+	lex.advance()
+	return unescape(raw), nil
+}
+
+// lexNumber reads a numeric literal, such as 42, 0x1F, 1_000L, or 1.5e-3
+func (lex *lexer) lexNumber() string {
+	start := lex.offset
+	isHex := strings.HasPrefix(strings.ToLower(lex.src[lex.offset:]), "0x")
+	for lex.offset < len(lex.src) {
+		char := lex.src[lex.offset]
+		switch {
+		case isDigit(char) || char == '_' || char == '.' || unicode.IsLetter(rune(char)):
+			lex.advance()
+		case (char == '+' || char == '-') && !isHex && strings.ContainsAny(lex.src[lex.offset-1:lex.offset], "eE"):
+			lex.advance()
+		default:
+			return lex.src[start:lex.offset]
+		}
+	}
+
+	return lex.src[start:lex.offset]
+}
+
+// textBlock removes the incidental indentation of a text block, along with its opening line break
+func textBlock(raw string) string {
+	_, raw, _ = strings.Cut(raw, "\n")
+	lines := strings.Split(raw, "\n")
+
+	indent := -1
+	for _, line := range lines {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || lineIndent < indent {
+			indent = lineIndent
+		}
+	}
+
+	for idx, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[idx] = line[indent:]
+		}
+		lines[idx] = strings.TrimRight(lines[idx], " \t")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// unescape decodes the escape sequences of a string or character literal
+func unescape(raw string) string {
+	if !strings.Contains(raw, `\`) {
+		return raw
+	}
+
+	var builder strings.Builder
+	for idx := 0; idx < len(raw); idx++ {
+		if raw[idx] != '\\' || idx+1 >= len(raw) {
+			builder.WriteByte(raw[idx])
+			continue
+		}
+
+		idx++
+		switch raw[idx] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'b':
+			builder.WriteByte('\b')
+		case 'f':
+			builder.WriteByte('\f')
+		case 's':
+			builder.WriteByte(' ')
+		case '\n':
+			// a line continuation within a text block
+		case 'u':
+			for idx+1 < len(raw) && raw[idx+1] == 'u' {
+				idx++
+			}
+
+			if idx+4 < len(raw) {
+				code, err := strconv.ParseUint(raw[idx+1:idx+5], 16, 32)
+				if err == nil {
+					builder.WriteRune(rune(code))
+					idx += 4
+				}
+			}
+		default:
+			builder.WriteByte(raw[idx])
+		}
+	}
+
+	return builder.String()
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
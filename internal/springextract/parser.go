@@ -0,0 +1,992 @@
+package springextract
+
+import (
+	"slices"
+	"strings"
+)
+
+// compilationUnit is a parsed Java source file. Only declarations are kept: method bodies and initializers are skipped
+type compilationUnit struct {
+	file    string      // path of the source file
+	pkg     string      // package the file declares
+	imports []string    // imported names, such as "java.util.List" or "java.util.*"
+	types   []*typeDecl // top level types. Nested types are kept by the types that declare them
+}
+
+// typeDecl declares a class, interface, enum, record, or annotation type
+type typeDecl struct {
+	kind        string           // one of "class", "interface", "enum", "record", or "@interface"
+	name        string           // simple name of the type
+	pos         position         // where the name is written
+	doc         string           // Javadoc comment of the type
+	annotations []annotation     // annotations of the type
+	modifiers   []string         // modifiers such as "public" or "abstract"
+	typeParams  []string         // names of the type parameters
+	extends     []typeRef        // extended types
+	components  []variable       // components of a record
+	fields      []variable       // declared fields
+	methods     []method         // declared methods
+	constants   []enumConstant   // constants of an enum
+	nested      []*typeDecl      // member types
+	outer       *typeDecl        // type that declares this one, if it is nested
+	unit        *compilationUnit // file that declares the type
+}
+
+// variable is a field, a record component, or a method parameter
+type variable struct {
+	name        string
+	pos         position
+	doc         string
+	annotations []annotation
+	modifiers   []string
+	typ         typeRef
+	init        *value // value of a field initializer, if it is a simple constant expression
+}
+
+// method is a method declaration
+type method struct {
+	name        string
+	pos         position
+	doc         string
+	annotations []annotation
+	modifiers   []string
+	returns     typeRef
+	params      []variable
+}
+
+// enumConstant is a constant of an enum
+type enumConstant struct {
+	name        string
+	annotations []annotation
+}
+
+// typeRef is a reference to a type, such as "List<Person>" or "int[]"
+type typeRef struct {
+	name string    // name as written, such as "java.util.List", "int", or "?" for wildcards
+	args []typeRef // type arguments. Wildcards with a bound are replaced with their bound
+	dims int       // number of array dimensions
+	pos  position
+}
+
+// simpleName gets the last segment of the type's name
+func (ref typeRef) simpleName() string {
+	return ref.name[strings.LastIndex(ref.name, ".")+1:]
+}
+
+// annotation is an annotation, with the values of its elements
+type annotation struct {
+	name string           // name as written, which may be qualified
+	pos  position         // where the annotation starts
+	args map[string]value // values by element name. A single unnamed value is named "value"
+}
+
+// simpleName gets the last segment of the annotation's name
+func (ann annotation) simpleName() string {
+	return ann.name[strings.LastIndex(ann.name, ".")+1:]
+}
+
+type valueKind int
+
+const (
+	valueKind_OTHER      valueKind = iota // an expression that is not understood
+	valueKind_STRING                      // a string or character literal
+	valueKind_NUMBER                      // a numeric literal
+	valueKind_BOOL                        // true or false
+	valueKind_NAME                        // a possibly qualified name, such as a constant or an enum value
+	valueKind_ARRAY                       // an array initializer, such as {"a", "b"}
+	valueKind_CONCAT                      // a concatenation of strings and names
+	valueKind_ANNOTATION                  // a nested annotation
+)
+
+// value is a constant expression, as used by annotation elements and constant fields
+type value struct {
+	kind  valueKind
+	text  string   // contents of literals and names
+	items []value  // items of arrays and parts of concatenations
+	pos   position // where the value starts
+}
+
+// modifierKeywords are the modifiers that may precede declarations
+var modifierKeywords = []string{"public", "protected", "private", "static", "final", "abstract", "native", "synchronized", "transient", "volatile", "strictfp", "default", "sealed"}
+
+// typeKeywords start type declarations
+var typeKeywords = []string{"class", "interface", "enum", "record"}
+
+// parser parses the declarations of a Java source file
+type parser struct {
+	tokens []token
+	idx    int
+	unit   *compilationUnit
+}
+
+// parseFile parses a Java source file
+func parseFile(file, src string) (*compilationUnit, error) {
+	tokens, err := tokenize(file, src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, unit: &compilationUnit{file: file}}
+	err = p.parseCompilationUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.unit, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.idx]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.idx+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.idx+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.idx]
+	if tok.kind != tokenKind_EOF {
+		p.idx++
+	}
+
+	return tok
+}
+
+// is checks if the current token is punctuation or an identifier with the given text
+func (p *parser) is(text string) bool {
+	tok := p.peek()
+	return (tok.kind == tokenKind_PUNCT || tok.kind == tokenKind_IDENT) && tok.text == text
+}
+
+// accept moves past the current token if it has the given text
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(text string) (token, error) {
+	if !p.is(text) {
+		return token{}, p.unexpected("'" + text + "'")
+	}
+
+	return p.next(), nil
+}
+
+func (p *parser) expectIdent() (token, error) {
+	if p.peek().kind != tokenKind_IDENT {
+		return token{}, p.unexpected("a name")
+	}
+
+	return p.next(), nil
+}
+
+// unexpected reports that the current token is not what was expected
+func (p *parser) unexpected(expected string) error {
+	tok := p.peek()
+	if tok.kind == tokenKind_EOF {
+		return errorAt(tok.pos, "expected %s, found the end of the file", expected)
+	}
+
+	return errorAt(tok.pos, "expected %s, found '%s'", expected, tok.text)
+}
+
+func (p *parser) parseCompilationUnit() error {
+	for {
+		start := p.idx
+		_, _, err := p.parseModifiers()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case p.is("package"):
+			p.next()
+			name, err := p.parseQualifiedName()
+			if err != nil {
+				return err
+			}
+			p.unit.pkg = name
+
+			_, err = p.expect(";")
+			if err != nil {
+				return err
+			}
+
+		case p.is("import"):
+			p.next()
+			p.accept("static")
+			name, err := p.parseQualifiedName()
+			if err != nil {
+				return err
+			}
+
+			if p.accept(".") {
+				_, err = p.expect("*")
+				if err != nil {
+					return err
+				}
+				name += ".*"
+			}
+			p.unit.imports = append(p.unit.imports, name)
+
+			_, err = p.expect(";")
+			if err != nil {
+				return err
+			}
+
+		case p.is("module") || (p.is("open") && p.peekAt(1).text == "module"):
+			// module declarations do not declare any types
+			return nil
+
+		case p.accept(";"):
+
+		case p.peek().kind == tokenKind_EOF:
+			return nil
+
+		default:
+			p.idx = start
+			decl, err := p.parseTypeDecl(nil)
+			if err != nil {
+				return err
+			}
+			p.unit.types = append(p.unit.types, decl)
+		}
+	}
+}
+
+// parseModifiers parses the annotations and modifier keywords that precede a declaration
+func (p *parser) parseModifiers() ([]annotation, []string, error) {
+	var annotations []annotation
+	var modifiers []string
+	for {
+		switch {
+		case p.is("@") && p.peekAt(1).text != "interface":
+			ann, err := p.parseAnnotation()
+			if err != nil {
+				return nil, nil, err
+			}
+			annotations = append(annotations, ann)
+
+		case p.peek().kind == tokenKind_IDENT && slices.Contains(modifierKeywords, p.peek().text):
+			modifiers = append(modifiers, p.next().text)
+
+		case p.is("non") && p.peekAt(1).text == "-" && p.peekAt(2).text == "sealed":
+			p.idx += 3
+			modifiers = append(modifiers, "non-sealed")
+
+		default:
+			return annotations, modifiers, nil
+		}
+	}
+}
+
+func (p *parser) parseQualifiedName() (string, error) {
+	tok, err := p.expectIdent()
+	if err != nil {
+		return "", err
+	}
+
+	name := tok.text
+	for p.is(".") && p.peekAt(1).kind == tokenKind_IDENT {
+		p.next()
+		name += "." + p.next().text
+	}
+
+	return name, nil
+}
+
+// parseAnnotation parses an annotation, such as @GetMapping(value = "/people", produces = "application/json")
+func (p *parser) parseAnnotation() (annotation, error) {
+	at, err := p.expect("@")
+	if err != nil {
+		return annotation{}, err
+	}
+
+	name, err := p.parseQualifiedName()
+	if err != nil {
+		return annotation{}, err
+	}
+
+	ann := annotation{name: name, pos: at.pos, args: make(map[string]value)}
+	if !p.accept("(") {
+		return ann, nil
+	}
+
+	if p.accept(")") {
+		return ann, nil
+	}
+
+	if p.peek().kind == tokenKind_IDENT && p.peekAt(1).text == "=" {
+		for {
+			key, err := p.expectIdent()
+			if err != nil {
+				return annotation{}, err
+			}
+
+			_, err = p.expect("=")
+			if err != nil {
+				return annotation{}, err
+			}
+
+			val, err := p.parseElementValue()
+			if err != nil {
+				return annotation{}, err
+			}
+			ann.args[key.text] = val
+
+			if !p.accept(",") {
+				break
+			}
+		}
+	} else {
+		val, err := p.parseElementValue()
+		if err != nil {
+			return annotation{}, err
+		}
+		ann.args["value"] = val
+	}
+
+	_, err = p.expect(")")
+	if err != nil {
+		return annotation{}, err
+	}
+
+	return ann, nil
+}
+
+// parseElementValue parses the value of an annotation element
+func (p *parser) parseElementValue() (value, error) {
+	pos := p.peek().pos
+	switch {
+	case p.is("@"):
+		_, err := p.parseAnnotation()
+		return value{kind: valueKind_ANNOTATION, pos: pos}, err
+
+	case p.accept("{"):
+		array := value{kind: valueKind_ARRAY, pos: pos}
+		for !p.is("}") {
+			item, err := p.parseElementValue()
+			if err != nil {
+				return value{}, err
+			}
+			array.items = append(array.items, item)
+
+			if !p.accept(",") {
+				break
+			}
+		}
+
+		_, err := p.expect("}")
+		return array, err
+
+	default:
+		return p.parseExpression(), nil
+	}
+}
+
+// parseExpression parses a constant expression. Concatenations of literals and names are understood, while anything
+// else is skipped up to the end of the expression
+func (p *parser) parseExpression() value {
+	pos := p.peek().pos
+	var parts []value
+	for {
+		part, ok := p.parseTerm()
+		if !ok {
+			p.skipExpression()
+			return value{kind: valueKind_OTHER, pos: pos}
+		}
+		parts = append(parts, part)
+
+		if !p.is("+") || p.peekAt(1).text == "+" {
+			break
+		}
+		p.next()
+	}
+
+	if !p.atExpressionEnd() {
+		p.skipExpression()
+		return value{kind: valueKind_OTHER, pos: pos}
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return value{kind: valueKind_CONCAT, items: parts, pos: pos}
+}
+
+// parseTerm parses a literal, a name, or a parenthesized expression. Returns false if the term is not understood
+func (p *parser) parseTerm() (value, bool) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenKind_STRING || tok.kind == tokenKind_CHAR:
+		p.next()
+		return value{kind: valueKind_STRING, text: tok.text, pos: tok.pos}, true
+
+	case tok.kind == tokenKind_NUMBER:
+		p.next()
+		return value{kind: valueKind_NUMBER, text: tok.text, pos: tok.pos}, true
+
+	case tok.text == "-" && p.peekAt(1).kind == tokenKind_NUMBER:
+		p.next()
+		return value{kind: valueKind_NUMBER, text: "-" + p.next().text, pos: tok.pos}, true
+
+	case tok.text == "true" || tok.text == "false":
+		p.next()
+		return value{kind: valueKind_BOOL, text: tok.text, pos: tok.pos}, true
+
+	case tok.kind == tokenKind_IDENT:
+		name, _ := p.parseQualifiedName()
+		if p.is("(") || p.is("[") {
+			return value{}, false
+		}
+
+		return value{kind: valueKind_NAME, text: name, pos: tok.pos}, true
+
+	case tok.text == "(":
+		p.next()
+		inner := p.parseExpression()
+		if inner.kind == valueKind_OTHER || !p.accept(")") {
+			return value{}, false
+		}
+
+		return inner, true
+
+	default:
+		return value{}, false
+	}
+}
+
+// atExpressionEnd checks if the current token ends an expression
+func (p *parser) atExpressionEnd() bool {
+	return p.is(",") || p.is(")") || p.is("}") || p.is(";") || p.peek().kind == tokenKind_EOF
+}
+
+// skipExpression skips tokens up to the end of the current expression, stepping over nested brackets
+func (p *parser) skipExpression() {
+	depth := 0
+	for p.peek().kind != tokenKind_EOF {
+		switch {
+		case p.is("(") || p.is("[") || p.is("{"):
+			depth++
+		case p.is(")") || p.is("]") || p.is("}"):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case (p.is(",") || p.is(";")) && depth == 0:
+			return
+		}
+		p.next()
+	}
+}
+
+// skipBalanced skips a bracketed group, such as a method body, starting at its opening bracket
+func (p *parser) skipBalanced(open, close string) error {
+	start, err := p.expect(open)
+	if err != nil {
+		return err
+	}
+
+	depth := 1
+	for depth > 0 {
+		switch {
+		case p.peek().kind == tokenKind_EOF:
+			return errorAt(start.pos, "'%s' is never closed", open)
+		case p.is(open):
+			depth++
+		case p.is(close):
+			depth--
+		}
+		p.next()
+	}
+
+	return nil
+}
+
+// parseTypeParams parses type parameters, such as <K, V extends Comparable<V>>, returning their names
+func (p *parser) parseTypeParams() ([]string, error) {
+	if !p.is("<") {
+		return nil, nil
+	}
+
+	var names []string
+	depth := 0
+	for {
+		switch {
+		case p.peek().kind == tokenKind_EOF:
+			return nil, p.unexpected("'>'")
+		case p.is("<"):
+			depth++
+		case p.is(">"):
+			depth--
+		case depth == 1 && p.peek().kind == tokenKind_IDENT && (p.tokens[p.idx-1].text == "<" || p.tokens[p.idx-1].text == ","):
+			names = append(names, p.peek().text)
+		}
+
+		p.next()
+		if depth == 0 {
+			return names, nil
+		}
+	}
+}
+
+// parseType parses a type reference, such as java.util.Map<String, List<Person>>[]
+func (p *parser) parseType() (typeRef, error) {
+	for p.is("@") {
+		_, err := p.parseAnnotation()
+		if err != nil {
+			return typeRef{}, err
+		}
+	}
+
+	pos := p.peek().pos
+	if p.accept("?") {
+		if p.accept("extends") || p.accept("super") {
+			return p.parseType()
+		}
+
+		return typeRef{name: "?", pos: pos}, nil
+	}
+
+	tok, err := p.expectIdent()
+	if err != nil {
+		return typeRef{}, err
+	}
+
+	ref := typeRef{name: tok.text, pos: pos}
+	for {
+		if p.is("<") {
+			ref.args, err = p.parseTypeArgs()
+			if err != nil {
+				return typeRef{}, err
+			}
+		}
+
+		if !p.is(".") || p.peekAt(1).kind != tokenKind_IDENT {
+			break
+		}
+
+		p.next()
+		ref.name += "." + p.next().text
+		ref.args = nil
+	}
+
+	for p.is("@") {
+		_, err := p.parseAnnotation()
+		if err != nil {
+			return typeRef{}, err
+		}
+	}
+
+	for p.is("[") && p.peekAt(1).text == "]" {
+		p.idx += 2
+		ref.dims++
+	}
+
+	return ref, nil
+}
+
+// parseTypeArgs parses type arguments, such as <String, Person>. The diamond <> has no arguments
+func (p *parser) parseTypeArgs() ([]typeRef, error) {
+	_, err := p.expect("<")
+	if err != nil {
+		return nil, err
+	}
+
+	var args []typeRef
+	for !p.is(">") {
+		arg, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	_, err = p.expect(">")
+	if err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+// parseTypeDecl parses a class, interface, enum, record, or annotation type declaration
+func (p *parser) parseTypeDecl(outer *typeDecl) (*typeDecl, error) {
+	doc := p.peek().doc
+	annotations, modifiers, err := p.parseModifiers()
+	if err != nil {
+		return nil, err
+	}
+
+	kind := p.peek().text
+	if p.is("@") {
+		p.next()
+		kind = "@interface"
+	}
+
+	if !slices.Contains(typeKeywords, kind) && kind != "@interface" {
+		return nil, p.unexpected("a type declaration")
+	}
+	p.next()
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	decl := &typeDecl{
+		kind:        kind,
+		name:        name.text,
+		pos:         name.pos,
+		doc:         doc,
+		annotations: annotations,
+		modifiers:   modifiers,
+		outer:       outer,
+		unit:        p.unit,
+	}
+
+	decl.typeParams, err = p.parseTypeParams()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == "record" {
+		decl.components, err = p.parseParams()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for p.is("extends") || p.is("implements") || p.is("permits") {
+		isExtends := p.next().text == "extends"
+		for {
+			ref, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+
+			if isExtends {
+				decl.extends = append(decl.extends, ref)
+			}
+
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	err = p.parseTypeBody(decl)
+	if err != nil {
+		return nil, err
+	}
+
+	return decl, nil
+}
+
+// parseTypeBody parses the members of a type declaration
+func (p *parser) parseTypeBody(decl *typeDecl) error {
+	_, err := p.expect("{")
+	if err != nil {
+		return err
+	}
+
+	if decl.kind == "enum" {
+		err = p.parseEnumConstants(decl)
+		if err != nil {
+			return err
+		}
+	}
+
+	for !p.accept("}") {
+		if p.peek().kind == tokenKind_EOF {
+			return p.unexpected("'}'")
+		}
+
+		err = p.parseMember(decl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseEnumConstants parses the constants that start the body of an enum
+func (p *parser) parseEnumConstants(decl *typeDecl) error {
+	for {
+		if p.accept(";") || p.is("}") {
+			return nil
+		}
+
+		annotations, _, err := p.parseModifiers()
+		if err != nil {
+			return err
+		}
+
+		name, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		decl.constants = append(decl.constants, enumConstant{name: name.text, annotations: annotations})
+
+		if p.is("(") {
+			err = p.skipBalanced("(", ")")
+			if err != nil {
+				return err
+			}
+		}
+
+		if p.is("{") {
+			err = p.skipBalanced("{", "}")
+			if err != nil {
+				return err
+			}
+		}
+
+		if !p.accept(",") {
+			if !p.is("}") {
+				_, err = p.expect(";")
+			}
+			return err
+		}
+	}
+}
+
+// parseMember parses a field, method, constructor, initializer, or member type
+func (p *parser) parseMember(decl *typeDecl) error {
+	if p.accept(";") {
+		return nil
+	}
+
+	start := p.idx
+	doc := p.peek().doc
+	annotations, modifiers, err := p.parseModifiers()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.is("{"):
+		// an initializer block
+		return p.skipBalanced("{", "}")
+
+	case slices.Contains(typeKeywords, p.peek().text) && p.peekAt(1).kind == tokenKind_IDENT, p.is("@"):
+		p.idx = start
+		nested, err := p.parseTypeDecl(decl)
+		if err != nil {
+			return err
+		}
+		decl.nested = append(decl.nested, nested)
+		return nil
+	}
+
+	_, err = p.parseTypeParams()
+	if err != nil {
+		return err
+	}
+
+	if p.peek().kind == tokenKind_IDENT && (p.peekAt(1).text == "(" || p.peekAt(1).text == "{") && p.peek().text == decl.name {
+		// a constructor, or the compact constructor of a record
+		p.next()
+		if p.is("(") {
+			err = p.skipBalanced("(", ")")
+			if err != nil {
+				return err
+			}
+		}
+
+		return p.skipMethodRest()
+	}
+
+	typ, err := p.parseType()
+	if err != nil {
+		return err
+	}
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if p.is("(") {
+		params, err := p.parseParams()
+		if err != nil {
+			return err
+		}
+
+		for p.is("[") && p.peekAt(1).text == "]" {
+			p.idx += 2
+			typ.dims++
+		}
+
+		decl.methods = append(decl.methods, method{
+			name:        name.text,
+			pos:         name.pos,
+			doc:         doc,
+			annotations: annotations,
+			modifiers:   modifiers,
+			returns:     typ,
+			params:      params,
+		})
+
+		return p.skipMethodRest()
+	}
+
+	return p.parseFieldDeclarators(decl, variable{doc: doc, annotations: annotations, modifiers: modifiers, typ: typ}, name)
+}
+
+// skipMethodRest skips the throws clause, default value, and body of a method or constructor
+func (p *parser) skipMethodRest() error {
+	if p.accept("throws") {
+		for {
+			_, err := p.parseType()
+			if err != nil {
+				return err
+			}
+
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if p.accept("default") {
+		_, err := p.parseElementValue()
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.accept(";") {
+		return nil
+	}
+
+	return p.skipBalanced("{", "}")
+}
+
+// parseFieldDeclarators parses the names and initializers of a field declaration, such as "a = 1, b;"
+func (p *parser) parseFieldDeclarators(decl *typeDecl, field variable, name token) error {
+	for {
+		declared := field
+		declared.name = name.text
+		declared.pos = name.pos
+		for p.is("[") && p.peekAt(1).text == "]" {
+			p.idx += 2
+			declared.typ.dims++
+		}
+
+		if p.accept("=") {
+			declared.init = p.parseInitializer()
+		}
+		decl.fields = append(decl.fields, declared)
+
+		if p.accept(";") {
+			return nil
+		}
+
+		_, err := p.expect(",")
+		if err != nil {
+			return err
+		}
+
+		name, err = p.expectIdent()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// parseInitializer parses a field initializer. Constant expressions are kept, while anything else is skipped
+func (p *parser) parseInitializer() *value {
+	start := p.idx
+	init := p.parseExpression()
+	if init.kind != valueKind_OTHER && (p.is(",") || p.is(";")) {
+		return &init
+	}
+
+	// commas within type arguments, such as "new HashMap<String, Integer>()", do not end the initializer, so skip up
+	// to a comma that is followed by another declarator
+	p.idx = start
+	for {
+		p.skipExpression()
+		if !p.is(",") || p.isDeclarator(1) {
+			return nil
+		}
+		p.next()
+	}
+}
+
+// isDeclarator checks if the token at the given offset starts another field declarator
+func (p *parser) isDeclarator(offset int) bool {
+	if p.peekAt(offset).kind != tokenKind_IDENT {
+		return false
+	}
+
+	following := p.peekAt(offset + 1).text
+	return following == "=" || following == "," || following == ";" || following == "["
+}
+
+// parseParams parses the parameters of a method, or the components of a record
+func (p *parser) parseParams() ([]variable, error) {
+	_, err := p.expect("(")
+	if err != nil {
+		return nil, err
+	}
+
+	var params []variable
+	for !p.is(")") {
+		doc := p.peek().doc
+		annotations, modifiers, err := p.parseModifiers()
+		if err != nil {
+			return nil, err
+		}
+
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.is(".") && p.peekAt(1).text == "." && p.peekAt(2).text == "." {
+			p.idx += 3
+			typ.dims++
+		}
+
+		name, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+
+		for p.is("[") && p.peekAt(1).text == "]" {
+			p.idx += 2
+			typ.dims++
+		}
+
+		if name.text != "this" {
+			params = append(params, variable{name: name.text, pos: name.pos, doc: doc, annotations: annotations, modifiers: modifiers, typ: typ})
+		}
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	_, err = p.expect(")")
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
@@ -0,0 +1,104 @@
+package springextract
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseFile_ParsesDeclarations(t *testing.T) {
+	source := `package com.acme;
+
+import java.util.*;
+import static com.acme.Paths.PEOPLE;
+
+/** A person */
+@JsonIgnoreProperties(ignoreUnknown = true)
+public sealed class Person<T extends Comparable<T>> extends Base implements Serializable permits Employee {
+    private final Map<String, List<Integer>> scores = new HashMap<String, List<Integer>>(), extra = Map.of();
+    public static final String GREETING = "hello, " + NAME;
+    private int[] counts, totals[];
+
+    /** Gets the score */
+    @Override
+    public <R> Map.Entry<String, R> score(@Nonnull final String name, int... values) throws IOException {
+        String braces = "}}}";
+        return null;
+    }
+
+    @interface Marker {
+        String value() default "";
+    }
+
+    enum Kind { A, B("b") { void run() {} }; Kind() {} Kind(String s) {} }
+
+    record Pair(String first, @Nullable String second) {
+        Pair {
+        }
+    }
+}
+`
+
+	unit, err := parseFile("Person.java", source)
+	assert.NoError(t, err)
+	assert.Equal(t, "com.acme", unit.pkg)
+	assert.Equal(t, []string{"java.util.*", "com.acme.Paths.PEOPLE"}, unit.imports)
+	assert.Len(t, unit.types, 1)
+
+	person := unit.types[0]
+	assert.Equal(t, "class", person.kind)
+	assert.Equal(t, "Person", person.name)
+	assert.Equal(t, "/** A person */", person.doc)
+	assert.Equal(t, []string{"T"}, person.typeParams)
+	assert.Equal(t, "Base", person.extends[0].name)
+	assert.Equal(t, "JsonIgnoreProperties", person.annotations[0].name)
+	assert.Equal(t, value{kind: valueKind_BOOL, text: "true", pos: position{File: "Person.java", Line: 7, Column: 39}}, person.annotations[0].args["ignoreUnknown"])
+
+	var fieldNames []string
+	for _, field := range person.fields {
+		fieldNames = append(fieldNames, field.name)
+	}
+	assert.Equal(t, []string{"scores", "extra", "GREETING", "counts", "totals"}, fieldNames)
+	assert.Equal(t, "Map", person.fields[0].typ.name)
+	assert.Equal(t, "List", person.fields[0].typ.args[1].name)
+	assert.Equal(t, valueKind_CONCAT, person.fields[2].init.kind)
+	assert.Equal(t, 2, person.fields[4].typ.dims)
+
+	assert.Len(t, person.methods, 1)
+	score := person.methods[0]
+	assert.Equal(t, "score", score.name)
+	assert.Equal(t, "/** Gets the score */", score.doc)
+	assert.Equal(t, "Map.Entry", score.returns.name)
+	assert.Equal(t, "name", score.params[0].name)
+	assert.Equal(t, "Nonnull", score.params[0].annotations[0].name)
+	assert.Equal(t, 1, score.params[1].typ.dims)
+
+	assert.Len(t, person.nested, 3)
+	assert.Equal(t, "@interface", person.nested[0].kind)
+	assert.Equal(t, []enumConstant{{name: "A"}, {name: "B"}}, person.nested[1].constants)
+	assert.Equal(t, "record", person.nested[2].kind)
+	assert.Equal(t, "second", person.nested[2].components[1].name)
+}
+
+func TestTokenize_DecodesLiterals(t *testing.T) {
+	tokens, err := tokenize("A.java", "\"a\\tb\\u0041\" \"\"\"\n    first\n      second\n    \"\"\" 'x' 1_000L 1.5e-3")
+	assert.NoError(t, err)
+
+	var texts []string
+	for _, tok := range tokens[:len(tokens)-1] {
+		texts = append(texts, tok.text)
+	}
+	assert.Equal(t, []string{"a\tbA", "first\n  second\n", "x", "1_000L", "1.5e-3"}, texts)
+}
+
+func TestJavadoc(t *testing.T) {
+	description, params := javadoc(`/**
+     * Gets a {@code Person} by their <b>ID</b>.
+     *
+     * @param id the ID of
+     *           the person
+     * @return the person
+     */`)
+
+	assert.Equal(t, "Gets a Person by their ID.", description)
+	assert.Equal(t, map[string]string{"id": "the ID of the person"}, params)
+}
@@ -0,0 +1,10 @@
+package com.acme.people;
+
+/** Paths shared by the controllers */
+public final class ApiPaths {
+    public static final String API = "/api/v1";
+    public static final String PEOPLE = API + "/people";
+
+    private ApiPaths() {
+    }
+}
@@ -0,0 +1,66 @@
+package com.acme.people;
+
+import com.acme.people.model.*;
+import jakarta.validation.Valid;
+import java.security.Principal;
+import java.util.List;
+import java.util.Map;
+import java.util.Optional;
+import org.springframework.http.ResponseEntity;
+import org.springframework.web.bind.annotation.*;
+
+/**
+ * Manages the people we know.
+ */
+@RestController
+@RequestMapping(ApiPaths.PEOPLE)
+public class PeopleController {
+    private final Map<String, Person> people = new HashMap<String, Person>(), cache;
+
+    public PeopleController(PeopleRepository repository) {
+        this.cache = Map.of();
+    }
+
+    /**
+     * Lists people.
+     *
+     * @param limit how many people to list
+     * @return the people
+     */
+    @GetMapping
+    public List<Person> listPeople(@RequestParam(defaultValue = "20") int limit,
+                                   @RequestParam(name = "q", required = false) String query,
+                                   @RequestParam Status status,
+                                   Principal principal) {
+        return List.of();
+    }
+
+    /** Gets a person by their ID */
+    @GetMapping(value = "/{id:[0-9]+}", produces = "application/json")
+    public ResponseEntity<Person> getPerson(@PathVariable("id") long personId) {
+        return ResponseEntity.notFound().build();
+    }
+
+    @PostMapping
+    @Operation(summary = "Create a person", description = "Creates a person " + "and returns it")
+    public Person createPerson(@Valid @RequestBody Person person) {
+        return person;
+    }
+
+    @Deprecated
+    @RequestMapping(path = "/{id}/address", method = RequestMethod.PUT)
+    public void updateAddress(@PathVariable long id, @RequestBody(required = false) Optional<Address> address) {
+        String text = "}{";
+        if (address.isPresent()) {
+            Runnable r = () -> { };
+        }
+    }
+
+    @DeleteMapping("/{id}")
+    public ResponseEntity<Void> deletePerson(@PathVariable Long id, @RequestHeader("X-Trace") String trace, boolean dryRun) {
+        return ResponseEntity.noContent().build();
+    }
+
+    private void helper() {
+    }
+}
@@ -0,0 +1,17 @@
+package com.acme.people.model;
+
+import jakarta.validation.constraints.NotNull;
+
+/**
+ * Where somebody lives
+ *
+ * @param street the street and number
+ * @param city   the city
+ */
+public record Address(@NotNull String street, String city) {
+    public Address {
+        if (street == null) {
+            throw new IllegalArgumentException();
+        }
+    }
+}
@@ -0,0 +1,8 @@
+package com.acme.people.model;
+
+import java.time.Instant;
+
+public abstract class Audited {
+    /** When the record was created */
+    protected Instant createdAt;
+}
@@ -0,0 +1,60 @@
+package com.acme.people.model;
+
+import com.fasterxml.jackson.annotation.JsonIgnore;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import jakarta.validation.constraints.*;
+import java.time.Instant;
+import java.time.LocalDate;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+
+/** Somebody we know */
+public class Person extends Audited {
+    private static final long serialVersionUID = 1L;
+
+    private UUID id;
+
+    /** Full name */
+    @NotBlank
+    @Size(max = 100)
+    private String name;
+
+    @JsonProperty("nick_name")
+    private String nickname;
+
+    @Email
+    private String email;
+
+    @Min(0) @Max(150)
+    private int age;
+
+    private LocalDate birthday;
+
+    private Address address;
+
+    @Size(min = 1)
+    private List<Person> friends;
+
+    private Map<String, String> tags;
+
+    private Status status;
+
+    private byte[] avatar;
+
+    @JsonIgnore
+    private String password;
+
+    private transient String scratch;
+
+    private Person.Settings settings;
+
+    public String getName() {
+        return name;
+    }
+
+    /** Settings of a person */
+    public static class Settings {
+        private boolean newsletter;
+    }
+}
@@ -0,0 +1,24 @@
+package com.acme.people.model;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+/** How a person is doing */
+public enum Status {
+    @JsonProperty("active") ACTIVE,
+    INACTIVE("inactive") {
+        @Override
+        public String toString() {
+            return "x";
+        }
+    };
+
+    private final String label;
+
+    Status() {
+        this("");
+    }
+
+    Status(String label) {
+        this.label = label;
+    }
+}
@@ -2,8 +2,11 @@ package types
 
 // APIDefinition specifies an entire API, which consists of multiple services
 type APIDefinition struct {
-	Name     string              `json:"name"`     // overall API name
-	Entities []EntitySpec        `json:"entities"` // the entities needed to consume this API
-	Services []ServiceDefinition `json:"services"` // the services provided by this API
-	Config   APIConfig           `json:"config"`   // additional API configuration data
+	Name          string              `json:"name"`                    // overall API name
+	Version       string              `json:"version"`                 // version of this API specification
+	Entities      []EntitySpec        `json:"entities"`                // the entities needed to consume this API
+	Services      []ServiceDefinition `json:"services"`                // the services provided by this API
+	Config        APIConfig           `json:"config"`                  // additional API configuration data
+	Naming        NamingStrategy      `json:"naming"`                  // how property names are written on the wire
+	ExternalTypes []ExternalType      `json:"externalTypes,omitempty"` // types defined outside of this specification
 }
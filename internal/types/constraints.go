@@ -0,0 +1,29 @@
+package types
+
+const (
+	Format_EMAIL = "email"
+	Format_UUID  = "uuid"
+	Format_URI   = "uri"
+)
+
+// PropertyConstraints restricts the values that a property may take. All constraints are optional
+type PropertyConstraints struct {
+	Minimum   *float64 `json:"minimum,omitempty"`   // smallest allowed number
+	Maximum   *float64 `json:"maximum,omitempty"`   // largest allowed number
+	MinLength *int     `json:"minLength,omitempty"` // minimum string length
+	MaxLength *int     `json:"maxLength,omitempty"` // maximum string length
+	Pattern   string   `json:"pattern,omitempty"`   // regular expression that strings must match
+	Enum      []any    `json:"enum,omitempty"`      // the only values allowed
+	MinItems  *int     `json:"minItems,omitempty"`  // minimum number of array items
+	MaxItems  *int     `json:"maxItems,omitempty"`  // maximum number of array items
+	Format    string   `json:"format,omitempty"`    // well-known string format. Comes from predefined enum
+}
+
+// IsEmpty returns true if no constraints are specified
+func (constraints PropertyConstraints) IsEmpty() bool {
+	return constraints.Minimum == nil && constraints.Maximum == nil &&
+		constraints.MinLength == nil && constraints.MaxLength == nil &&
+		len(constraints.Pattern) == 0 && len(constraints.Enum) == 0 &&
+		constraints.MinItems == nil && constraints.MaxItems == nil &&
+		len(constraints.Format) == 0
+}
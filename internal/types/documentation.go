@@ -0,0 +1,15 @@
+package types
+
+// Documentation holds optional human-readable documentation for an element of the spec. Generators render it as doc
+// comments in the target language
+type Documentation struct {
+	Summary     string `json:"summary,omitempty"`     // a short, one line summary
+	Description string `json:"description,omitempty"` // a longer description. May span multiple lines
+	Example     any    `json:"example,omitempty"`     // an example value
+	Deprecated  bool   `json:"deprecated,omitempty"`  // if true, this element should no longer be used
+}
+
+// IsEmpty returns true if there is nothing to document
+func (doc Documentation) IsEmpty() bool {
+	return len(doc.Summary) == 0 && len(doc.Description) == 0 && doc.Example == nil && !doc.Deprecated
+}
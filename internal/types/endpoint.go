@@ -3,12 +3,14 @@ package types
 // RequestValue specifies a value that is passed in an API endpoint. Each value is typed and has optional
 // metadata
 type RequestValue struct {
+	Documentation
 	Type     DynamicType `json:"type"`
 	Required bool        `json:"required"`
 }
 
 // APIEndpoint is an endpoint to call
 type APIEndpoint struct {
+	Documentation
 	Name           string                  `json:"name"`           // the name of the endpoint
 	Endpoint       string                  `json:"endpoint"`       // the URI endpoint that this request is located at
 	Method         string                  `json:"method"`         // the HTTP method that this endpoint consumes
@@ -2,12 +2,16 @@ package types
 
 // PropertySpec specifies an entity property
 type PropertySpec struct {
-	Type     DynamicType `json:"type"`     // Defines the type of this property
-	Required bool        `json:"required"` // if true, this property must be specified. if false, can be an optional value
+	Documentation
+	PropertyConstraints
+	Type     DynamicType `json:"type"`               // Defines the type of this property
+	Required bool        `json:"required"`           // if true, this property must be specified. if false, can be an optional value
+	WireName string      `json:"wireName,omitempty"` // optional name this property is sent with, overriding the naming strategy
 }
 
 // EntitySpec specifies an entity model that is used
 type EntitySpec struct {
+	Documentation
 	Name       string                  `json:"name"`       // name of this entity
 	Properties map[string]PropertySpec `json:"properties"` // the properties that this entity defines
 }
@@ -0,0 +1,40 @@
+package types
+
+// ExternalType is a type that is defined outside of the specification, such as in a shared package. References to it
+// are imported from its module, and any entity declared with the same name is not generated
+type ExternalType struct {
+	Name    string                         `json:"name"`              // name the type is referenced by in the specification
+	Module  string                         `json:"module"`            // module that provides the type, such as "@acme/common-models"
+	Targets map[string]ExternalTypeMapping `json:"targets,omitempty"` // optional target-specific mappings, keyed by target name
+}
+
+// ExternalTypeMapping describes where a target imports an external type from
+type ExternalTypeMapping struct {
+	Module string `json:"module,omitempty"` // module that provides the type. Defaults to the module of the external type
+	Name   string `json:"name,omitempty"`   // name the module exports the type as. Defaults to the name of the external type
+}
+
+// ForTarget gets the mapping of this type for the given target, falling back to the module and name of the type
+func (external ExternalType) ForTarget(target string) ExternalTypeMapping {
+	mapping := external.Targets[target]
+	if len(mapping.Module) == 0 {
+		mapping.Module = external.Module
+	}
+
+	if len(mapping.Name) == 0 {
+		mapping.Name = external.Name
+	}
+
+	return mapping
+}
+
+// IsExternal checks if the named type is one of the API's external types
+func (api APIDefinition) IsExternal(typeName string) bool {
+	for _, external := range api.ExternalTypes {
+		if external.Name == typeName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExternalType_ForTarget_FallsBackToDefaults(t *testing.T) {
+	external := ExternalType{
+		Name:   "Money",
+		Module: "@acme/common-models",
+		Targets: map[string]ExternalTypeMapping{
+			"spring": {Module: "com.acme.models", Name: "MoneyDto"},
+			"legacy": {Name: "LegacyMoney"},
+		},
+	}
+
+	assert.Equal(t, ExternalTypeMapping{Module: "@acme/common-models", Name: "Money"}, external.ForTarget("angular"))
+	assert.Equal(t, ExternalTypeMapping{Module: "com.acme.models", Name: "MoneyDto"}, external.ForTarget("spring"))
+	assert.Equal(t, ExternalTypeMapping{Module: "@acme/common-models", Name: "LegacyMoney"}, external.ForTarget("legacy"))
+}
+
+func TestAPIDefinition_IsExternal(t *testing.T) {
+	api := APIDefinition{ExternalTypes: []ExternalType{{Name: "Money", Module: "@acme/common-models"}}}
+
+	assert.True(t, api.IsExternal("Money"))
+	assert.False(t, api.IsExternal("Person"))
+}
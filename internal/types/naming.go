@@ -0,0 +1,55 @@
+package types
+
+import (
+	"fmt"
+	"github.com/iancoleman/strcase"
+)
+
+// NamingStrategy specifies how property names are written on the wire
+type NamingStrategy string
+
+const (
+	NamingStrategy_PRESERVE NamingStrategy = "preserve" // names are sent exactly as they are written in the spec
+	NamingStrategy_CAMEL    NamingStrategy = "camel"    // names are sent in camelCase
+	NamingStrategy_SNAKE    NamingStrategy = "snake"    // names are sent in snake_case
+	NamingStrategy_KEBAB    NamingStrategy = "kebab"    // names are sent in kebab-case
+)
+
+// IsPreserve checks if names are left untouched. An unset strategy preserves names
+func (strategy NamingStrategy) IsPreserve() bool {
+	return len(strategy) == 0 || strategy == NamingStrategy_PRESERVE
+}
+
+// Validate checks that this is a known naming strategy
+func (strategy NamingStrategy) Validate() error {
+	switch strategy {
+	case "", NamingStrategy_PRESERVE, NamingStrategy_CAMEL, NamingStrategy_SNAKE, NamingStrategy_KEBAB:
+		return nil
+	default:
+		return fmt.Errorf("unknown naming strategy '%s'", strategy)
+	}
+}
+
+// Apply converts a name into this naming strategy
+func (strategy NamingStrategy) Apply(name string) string {
+	switch strategy {
+	case NamingStrategy_CAMEL:
+		return strcase.ToLowerCamel(name)
+	case NamingStrategy_SNAKE:
+		return strcase.ToSnake(name)
+	case NamingStrategy_KEBAB:
+		return strcase.ToKebab(name)
+	default:
+		return name
+	}
+}
+
+// ResolveWireName gets the name a property is sent with. An explicit wire name takes precedence over the naming
+// strategy
+func (spec PropertySpec) ResolveWireName(name string, strategy NamingStrategy) string {
+	if len(spec.WireName) > 0 {
+		return spec.WireName
+	}
+
+	return strategy.Apply(name)
+}
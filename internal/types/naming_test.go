@@ -0,0 +1,26 @@
+package types
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNamingStrategy_Apply(t *testing.T) {
+	assert.Equal(t, "firstName", NamingStrategy_CAMEL.Apply("first_name"))
+	assert.Equal(t, "first_name", NamingStrategy_SNAKE.Apply("firstName"))
+	assert.Equal(t, "first-name", NamingStrategy_KEBAB.Apply("firstName"))
+	assert.Equal(t, "first_Name", NamingStrategy_PRESERVE.Apply("first_Name"))
+	assert.Equal(t, "first_Name", NamingStrategy("").Apply("first_Name"))
+}
+
+func TestNamingStrategy_Validate(t *testing.T) {
+	assert.NoError(t, NamingStrategy("").Validate())
+	assert.NoError(t, NamingStrategy_SNAKE.Validate())
+	assert.Error(t, NamingStrategy("upper").Validate())
+}
+
+func TestPropertySpec_ResolveWireName_PrefersExplicitName(t *testing.T) {
+	prop := PropertySpec{WireName: "surname"}
+	assert.Equal(t, "surname", prop.ResolveWireName("lastName", NamingStrategy_SNAKE))
+	assert.Equal(t, "last_name", PropertySpec{}.ResolveWireName("lastName", NamingStrategy_SNAKE))
+}
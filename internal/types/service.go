@@ -2,6 +2,7 @@ package types
 
 // ServiceDefinition defines a service that consumes a controller
 type ServiceDefinition struct {
+	Documentation
 	Name      string        `json:"name"`      // defines the name of the service. Don't include any suffixes
 	Endpoints []APIEndpoint `json:"endpoints"` // defines all endpoints defined by the controller
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	mapset "github.com/deckarep/golang-set/v2"
 	"reflect"
+	"strings"
 )
 
 const (
@@ -15,7 +16,8 @@ const (
 	TypeID_USER      = "USER"
 	TypeID_ARRAY     = "ARRAY"
 	TypeID_GENERIC   = "GENERIC"
-	TypeID_TIMESTAMP = "TIMESTAMP"
+	TypeID_TIMESTAMP = "TIMESTAMP" // a date and time, sent as an ISO-8601 date-time string
+	TypeID_DATE      = "DATE"      // a calendar date without a time, sent as an ISO-8601 date string
 	TypeID_ANY       = "ANY"
 )
 
@@ -30,6 +32,11 @@ func (tp DynamicType) IsVoid() bool {
 	return tp.TypeID == TypeID_VOID
 }
 
+// IsTemporal checks if values of this type are dates or timestamps
+func (tp DynamicType) IsTemporal() bool {
+	return tp.TypeID == TypeID_TIMESTAMP || tp.TypeID == TypeID_DATE
+}
+
 func (tp DynamicType) ArrayElementTp() DynamicType {
 	if tp.TypeID != TypeID_ARRAY {
 		panic("type is not an array")
@@ -42,6 +49,26 @@ func (tp DynamicType) ArrayElementTp() DynamicType {
 	return tp.Inner[0]
 }
 
+// String describes the type the way it is written in specifications, such as "ARRAY<Person>" or
+// "Record<STRING, INTEGER>". Types that describe the same values are described the same way
+func (tp DynamicType) String() string {
+	inner := make([]string, 0, len(tp.Inner))
+	for _, innerTp := range tp.Inner {
+		inner = append(inner, innerTp.String())
+	}
+
+	switch tp.TypeID {
+	case TypeID_USER:
+		return tp.Reference
+	case TypeID_ARRAY:
+		return fmt.Sprintf("ARRAY<%s>", strings.Join(inner, ", "))
+	case TypeID_GENERIC:
+		return fmt.Sprintf("%s<%s>", tp.Reference, strings.Join(inner, ", "))
+	default:
+		return tp.TypeID
+	}
+}
+
 func (tp DynamicType) TypeReferences() []string {
 	references := mapset.NewSet[string](tp.Reference)
 	for _, inner := range tp.Inner {
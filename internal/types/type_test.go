@@ -117,3 +117,24 @@ func TestGoTypeToDynamicType_MapsSlice(t *testing.T) {
 	assert.NotEmpty(t, dtype.Inner)
 	assert.Equal(t, TypeID_STRING, dtype.Inner[0].TypeID)
 }
+
+func TestDynamicType_String(t *testing.T) {
+	tests := map[string]struct {
+		tp       DynamicType
+		expected string
+	}{
+		"scalar": {tp: DynamicType{TypeID: TypeID_STRING}, expected: "STRING"},
+		"entity": {tp: DynamicType{TypeID: TypeID_USER, Reference: "Person"}, expected: "Person"},
+		"array":  {tp: DynamicType{TypeID: TypeID_ARRAY, Inner: []DynamicType{{TypeID: TypeID_USER, Reference: "Person"}}}, expected: "ARRAY<Person>"},
+		"generic": {
+			tp:       DynamicType{TypeID: TypeID_GENERIC, Reference: "Record", Inner: []DynamicType{{TypeID: TypeID_STRING}, {TypeID: TypeID_INTEGER}}},
+			expected: "Record<STRING, INTEGER>",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.tp.String())
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PathProvider gets the set of paths that should be watched. It is consulted after every change, so the set of
+// watched paths can grow or shrink as the watched files change
+type PathProvider func() []string
+
+// fileState is the observable state of a watched file
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// PollingWatcher watches a set of files for changes by periodically checking their size and modification time
+type PollingWatcher struct {
+	Paths    PathProvider  // which paths to watch
+	Interval time.Duration // how often to poll files for changes
+	Debounce time.Duration // how long files must stay unchanged before a change is reported
+}
+
+// Run polls the watched files until the context is cancelled, calling onChange once a burst of changes has settled
+func (watcher *PollingWatcher) Run(ctx context.Context, onChange func()) error {
+	if watcher.Interval <= 0 {
+		return fmt.Errorf("polling interval must be positive, but is %s", watcher.Interval)
+	}
+
+	ticker := time.NewTicker(watcher.Interval)
+	defer ticker.Stop()
+
+	states := watcher.snapshot()
+	var lastChange time.Time
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			current := watcher.snapshot()
+			if !sameStates(states, current) {
+				states = current
+				lastChange = now
+				pending = true
+				continue
+			}
+
+			if pending && now.Sub(lastChange) >= watcher.Debounce {
+				pending = false
+				onChange()
+				// the path set may have changed as a result of this change
+				states = watcher.snapshot()
+			}
+		}
+	}
+}
+
+func (watcher *PollingWatcher) snapshot() map[string]fileState {
+	states := make(map[string]fileState)
+	for _, path := range watcher.Paths() {
+		stat, err := os.Stat(path)
+		if err != nil {
+			states[path] = fileState{}
+			continue
+		}
+
+		states[path] = fileState{
+			exists:  true,
+			size:    stat.Size(),
+			modTime: stat.ModTime(),
+		}
+	}
+
+	return states
+}
+
+func sameStates(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for path, state := range a {
+		other, exists := b[path]
+		if !exists || other != state {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+package watch
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPollingWatcher_Run_ReportsChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+
+	watcher := PollingWatcher{
+		Paths:    func() []string { return []string{path} },
+		Interval: 5 * time.Millisecond,
+		Debounce: 20 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changes := make(chan struct{}, 10)
+	go func() {
+		_ = watcher.Run(ctx, func() { changes <- struct{}{} })
+	}()
+
+	// give the watcher a chance to take its first snapshot
+	time.Sleep(20 * time.Millisecond)
+
+	// a burst of writes should be reported once
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, os.WriteFile(path, []byte(`{"name": "changed"}`)[:10+i], 0644))
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-changes:
+	case <-ctx.Done():
+		t.Fatal("change was never reported")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, changes)
+}
+
+func TestPollingWatcher_Run_StopsWhenCancelled(t *testing.T) {
+	watcher := PollingWatcher{
+		Paths:    func() []string { return nil },
+		Interval: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := watcher.Run(ctx, func() {})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPollingWatcher_Run_FailsForNonPositiveIntervals(t *testing.T) {
+	watcher := PollingWatcher{
+		Paths: func() []string { return nil },
+	}
+
+	err := watcher.Run(context.Background(), func() {})
+	assert.ErrorContains(t, err, "polling interval must be positive, but is 0s")
+}
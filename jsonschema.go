@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"github.com/softwaresale/client-gen/v2/internal/specfile"
+	"os"
+)
+
+// JSONSchemaArgs specifies the arguments passed to the jsonschema command
+type JSONSchemaArgs struct {
+	Input     string
+	Format    string
+	Output    string
+	Entity    string
+	ID        string
+	Namespace string
+}
+
+// EntityFile is a specification file that only declares entities, so that it can be included by other specifications
+type EntityFile struct {
+	Namespace string                 `json:"namespace,omitempty"`
+	Entities  []clientgen.EntitySpec `json:"entities"`
+}
+
+func runJSONSchema(argv []string) int {
+	if len(argv) == 0 || (argv[0] != "import" && argv[0] != "export") {
+		printJSONSchemaUsage()
+		return 2
+	}
+
+	var args JSONSchemaArgs
+
+	flags := flag.NewFlagSet("jsonschema "+argv[0], flag.ContinueOnError)
+	flags.StringVar(&args.Output, "output", "", "Path to write the converted document to. Defaults to standard output")
+	if argv[0] == "import" {
+		flags.StringVar(&args.Input, "input", "", "Path to the JSON Schema document")
+		flags.StringVar(&args.Namespace, "namespace", "", "Namespace that prefixes the names of the imported entities")
+	} else {
+		flags.StringVar(&args.Input, "input", "", "Path to the input specification")
+		flags.StringVar(&args.Format, "input-format", "", "Format of the input specification: json, jsonc or yaml. Detected from the file extension by default")
+		flags.StringVar(&args.Entity, "entity", "", "Entity that the document itself describes. Every entity is declared under $defs regardless")
+		flags.StringVar(&args.ID, "id", "", "$id of the document")
+	}
+
+	err := flags.Parse(argv[1:])
+	if err != nil {
+		return 2
+	}
+
+	if len(args.Input) == 0 {
+		fmt.Println("input path is required")
+		return 2
+	}
+
+	var output any
+	if argv[0] == "import" {
+		output, err = importJSONSchema(args)
+	} else {
+		output, err = exportJSONSchema(args)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	err = writeJSON(output, args.Output)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func printJSONSchemaUsage() {
+	fmt.Println("usage: client-gen jsonschema <import|export> [flags]")
+	fmt.Println()
+	fmt.Println("  import     Convert the definitions of a JSON Schema document into entities")
+	fmt.Println("  export     Convert the entities of a specification into a JSON Schema document")
+}
+
+// importJSONSchema reads a JSON Schema document and converts its definitions into a file of entities
+func importJSONSchema(args JSONSchemaArgs) (EntityFile, error) {
+	data, err := os.ReadFile(args.Input)
+	if err != nil {
+		return EntityFile{}, fmt.Errorf("failed to read JSON schema: %w", err)
+	}
+
+	document, err := clientgen.ReadJSONSchema(data)
+	if err != nil {
+		return EntityFile{}, err
+	}
+
+	entities, err := clientgen.ImportJSONSchema(document)
+	if err != nil {
+		return EntityFile{}, err
+	}
+
+	return EntityFile{Namespace: args.Namespace, Entities: entities}, nil
+}
+
+// exportJSONSchema reads a specification and converts its entities into a JSON Schema document
+func exportJSONSchema(args JSONSchemaArgs) (*clientgen.JSONSchema, error) {
+	resolver := specfile.NewResolver()
+	if len(args.Format) > 0 {
+		format, err := specfile.ParseFormat(args.Format)
+		if err != nil {
+			return nil, err
+		}
+		resolver.Format = format
+	}
+
+	apiDef, err := readAPIDefinition(resolver, args.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientgen.ExportJSONSchema(apiDef, clientgen.JSONSchemaExportOptions{ID: args.ID, Root: args.Entity})
+}
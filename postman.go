@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/softwaresale/client-gen/v2/clientgen"
+	"github.com/softwaresale/client-gen/v2/internal/specfile"
+	"os"
+)
+
+// PostmanArgs specifies the arguments passed to the postman command
+type PostmanArgs struct {
+	Input   string
+	Format  string
+	Output  string
+	Name    string
+	Version string
+	BaseURL string
+}
+
+func runPostman(argv []string) int {
+	if len(argv) == 0 || (argv[0] != "import" && argv[0] != "export") {
+		printPostmanUsage()
+		return 2
+	}
+
+	var args PostmanArgs
+
+	flags := flag.NewFlagSet("postman "+argv[0], flag.ContinueOnError)
+	flags.StringVar(&args.Output, "output", "", "Path to write the converted document to. Defaults to standard output")
+	if argv[0] == "import" {
+		flags.StringVar(&args.Input, "input", "", "Path to the Postman collection")
+		flags.StringVar(&args.Name, "name", "", "Name of the API. Defaults to the name of the collection")
+		flags.StringVar(&args.Version, "version", "", "Version of the API. Defaults to the version of the collection")
+		flags.StringVar(&args.BaseURL, "base-url", "", "Base URL of the API. Defaults to the base URL of the first request")
+	} else {
+		flags.StringVar(&args.Input, "input", "", "Path to the input specification")
+		flags.StringVar(&args.Format, "input-format", "", "Format of the input specification: json, jsonc or yaml. Detected from the file extension by default")
+	}
+
+	err := flags.Parse(argv[1:])
+	if err != nil {
+		return 2
+	}
+
+	if len(args.Input) == 0 {
+		fmt.Println("input path is required")
+		return 2
+	}
+
+	var output any
+	if argv[0] == "import" {
+		output, err = importPostman(args)
+	} else {
+		output, err = exportPostman(args)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	err = writeJSON(output, args.Output)
+	if err != nil {
+		fmt.Println(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func printPostmanUsage() {
+	fmt.Println("usage: client-gen postman <import|export> [flags]")
+	fmt.Println()
+	fmt.Println("  import     Convert a Postman collection into a specification")
+	fmt.Println("  export     Convert a specification into a Postman collection")
+}
+
+// importPostman reads a Postman collection and converts it into a specification
+func importPostman(args PostmanArgs) (clientgen.APIDefinition, error) {
+	data, err := os.ReadFile(args.Input)
+	if err != nil {
+		return clientgen.APIDefinition{}, fmt.Errorf("failed to read collection: %w", err)
+	}
+
+	collection, err := clientgen.ReadPostmanCollection(data)
+	if err != nil {
+		return clientgen.APIDefinition{}, err
+	}
+
+	return clientgen.ImportPostmanCollection(collection, clientgen.PostmanImportOptions{
+		Name:    args.Name,
+		Version: args.Version,
+		BaseURL: args.BaseURL,
+		OnWarning: func(warning string) {
+			_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+		},
+	})
+}
+
+// exportPostman reads a specification and converts it into a Postman collection
+func exportPostman(args PostmanArgs) (clientgen.PostmanCollection, error) {
+	resolver := specfile.NewResolver()
+	if len(args.Format) > 0 {
+		format, err := specfile.ParseFormat(args.Format)
+		if err != nil {
+			return clientgen.PostmanCollection{}, err
+		}
+		resolver.Format = format
+	}
+
+	apiDef, err := readAPIDefinition(resolver, args.Input)
+	if err != nil {
+		return clientgen.PostmanCollection{}, err
+	}
+
+	return clientgen.ExportPostmanCollection(apiDef)
+}